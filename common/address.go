@@ -1,5 +1,18 @@
 package common
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultMaxAddressLength is the default maximum length of an Address in bytes
+const DefaultMaxAddressLength = 64
+
+// ErrInvalidAddress is returned by ValidateAddress for an Address that is empty,
+// too long or contains a character outside of the Address charset
+var ErrInvalidAddress = errors.New("invalid address")
+
 // Address represents the address for an Account
 // Placeholder for [20]byte type Addresses.
 type Address string
@@ -18,3 +31,43 @@ func NullAddress() Address {
 func MinerAddress() Address {
 	return "Aneesh"
 }
+
+// NormalizeAddress converts a string received at the RPC boundary into an Address.
+// Surrounding whitespace is removed, while the case of the Address is preserved:
+// Addresses are case-sensitive, so "Alice" and "alice" are different owners.
+func NormalizeAddress(address string) Address {
+	return Address(strings.TrimSpace(address))
+}
+
+// ValidateAddress checks that an Address is not empty, is no longer than maxLength bytes and only
+// contains ASCII letters, digits and the characters '-', '_' and '.'. The length is not limited if
+// maxLength is 0. Returns an error wrapping ErrInvalidAddress if the Address is not valid.
+func ValidateAddress(address Address, maxLength int) error {
+	if address == NullAddress() {
+		return fmt.Errorf("%w: empty address", ErrInvalidAddress)
+	}
+
+	if maxLength > 0 && len(address) > maxLength {
+		return fmt.Errorf("%w: length %v exceeds maximum address length %v", ErrInvalidAddress, len(address), maxLength)
+	}
+
+	for idx, char := range string(address) {
+		if !isAddressChar(char) {
+			return fmt.Errorf("%w: disallowed character %q at position %v", ErrInvalidAddress, char, idx)
+		}
+	}
+
+	return nil
+}
+
+// isAddressChar returns whether a character is in the Address charset
+func isAddressChar(char rune) bool {
+	switch {
+	case 'a' <= char && char <= 'z', 'A' <= char && char <= 'Z', '0' <= char && char <= '9':
+		return true
+	case char == '-', char == '_', char == '.':
+		return true
+	default:
+		return false
+	}
+}
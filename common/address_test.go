@@ -0,0 +1,59 @@
+package common
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestNormalizeAddress checks that addresses differing only by surrounding whitespace normalize to the same Address,
+// while addresses differing by case remain different
+func TestNormalizeAddress(t *testing.T) {
+	for _, input := range []string{"alice", " alice", "alice\n", "\t alice \r\n"} {
+		if address := NormalizeAddress(input); address != "alice" {
+			t.Errorf("expected %q to normalize to %q, got %q", input, "alice", address)
+		}
+	}
+
+	if NormalizeAddress("Alice") == NormalizeAddress("alice") {
+		t.Errorf("expected addresses differing by case to remain different")
+	}
+
+	if address := NormalizeAddress("al ice"); address != "al ice" {
+		t.Errorf("expected inner whitespace to be preserved, got %q", address)
+	}
+}
+
+// TestValidateAddress checks that ValidateAddress rejects empty, over-long and disallowed-character addresses,
+// and does not limit the length of an Address for a maximum length of 0
+func TestValidateAddress(t *testing.T) {
+	tests := []struct {
+		name      string
+		address   Address
+		maxLength int
+		valid     bool
+	}{
+		{"charset", "Alice-01_x.y", DefaultMaxAddressLength, true},
+		{"at maximum length", Address(strings.Repeat("a", DefaultMaxAddressLength)), DefaultMaxAddressLength, true},
+		{"over maximum length", Address(strings.Repeat("a", DefaultMaxAddressLength+1)), DefaultMaxAddressLength, false},
+		{"unlimited length", Address(strings.Repeat("a", 1<<20)), 0, true},
+		{"empty", NullAddress(), 0, false},
+		{"whitespace", "al ice", 0, false},
+		{"punctuation", "alice;drop", 0, false},
+		{"non-ascii", "alicé", 0, false},
+		{"control", "alice\x00", 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateAddress(test.address, test.maxLength)
+			if test.valid && err != nil {
+				t.Fatalf("expected the address to be valid, got %v", err)
+			}
+
+			if !test.valid && !errors.Is(err, ErrInvalidAddress) {
+				t.Fatalf("expected the address to be invalid, got %v", err)
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package common
 
 import (
+	"bytes"
 	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"math/big"
 )
 
@@ -24,20 +27,49 @@ func BytesToHash(b []byte) (h Hash) {
 	return
 }
 
+// HexToHash decodes a hex string with 0x prefix into a Hash.
+// Returns an error if the decoded value is not exactly HashLength bytes.
+func HexToHash(input string) (Hash, error) {
+	b, err := HexDecode(input)
+	if err != nil {
+		return Hash{}, err
+	}
+
+	if len(b) != HashLength {
+		return Hash{}, fmt.Errorf("expected %v bytes, got %v", HashLength, len(b))
+	}
+
+	return BytesToHash(b), nil
+}
+
 // NullHash returns a zero Hash
 func NullHash() Hash { return [32]byte{} }
 
+// IsZero returns whether the Hash is a zero Hash
+func (h Hash) IsZero() bool { return h == Hash{} }
+
+// Equal returns whether the Hash is equal to another Hash
+func (h Hash) Equal(other Hash) bool { return h == other }
+
+// Cmp compares the Hash with another Hash as big-endian byte strings.
+// Returns -1 if h < other, 0 if h == other and +1 if h > other.
+func (h Hash) Cmp(other Hash) int { return bytes.Compare(h[:], other[:]) }
+
 // Bytes returns the byte representation of the Hash
 func (h Hash) Bytes() []byte { return h[:] }
 
 // Big returns the Hash as big integer
 func (h Hash) Big() *big.Int { return new(big.Int).SetBytes(h[:]) }
 
-// Hex returns the Hash as a hex string
+// Hex returns the Hash as a 0x prefixed string of 64 lowercase hex digits.
+// This is the canonical form of a Hash in logs, errors and RPC results.
 func (h Hash) Hex() string { return HexEncode(h.Bytes()) }
 
+// BareHex returns the Hash as a string of 64 lowercase hex digits without the 0x prefix
+func (h Hash) BareHex() string { return hex.EncodeToString(h.Bytes()) }
+
 // String implements the Stringer interface for Hash.
-// Returns the Hash as hex string.
+// Returns the Hash in its canonical Hex form.
 func (h Hash) String() string { return h.Hex() }
 
 // Hash256 generates a 256-bit hash of some given data.
@@ -0,0 +1,92 @@
+package common
+
+import (
+	"sort"
+	"testing"
+)
+
+// TestHashIsZero checks that only the zero Hash is detected as zero
+func TestHashIsZero(t *testing.T) {
+	if !NullHash().IsZero() || !(Hash{}).IsZero() || !BytesToHash(nil).IsZero() {
+		t.Fatalf("expected the null hash to be zero")
+	}
+
+	if BytesToHash([]byte{1}).IsZero() || Hash256(nil).IsZero() {
+		t.Fatalf("expected a non-null hash not to be zero")
+	}
+}
+
+// TestHashEqual checks that Hashes are equal only to Hashes of the same bytes
+func TestHashEqual(t *testing.T) {
+	hash := Hash256([]byte("data"))
+
+	if !hash.Equal(Hash256([]byte("data"))) || !hash.Equal(BytesToHash(hash.Bytes())) {
+		t.Fatalf("expected hashes of the same data to be equal")
+	}
+
+	if hash.Equal(Hash256([]byte("other"))) || hash.Equal(NullHash()) {
+		t.Fatalf("expected hashes of different data not to be equal")
+	}
+}
+
+// TestHashCmp checks that Hashes are ordered as big-endian byte strings, consistently with their big integers
+func TestHashCmp(t *testing.T) {
+	low, high := BytesToHash([]byte{0xff}), BytesToHash([]byte{0x01, 0x00})
+
+	tests := []struct {
+		a, b Hash
+		cmp  int
+	}{
+		{low, high, -1},
+		{high, low, 1},
+		{low, low, 0},
+		{NullHash(), low, -1},
+	}
+
+	for _, test := range tests {
+		if cmp := test.a.Cmp(test.b); cmp != test.cmp {
+			t.Fatalf("expected %v cmp %v to be %v, got %v", test.a, test.b, test.cmp, cmp)
+		}
+
+		if cmp := test.a.Big().Cmp(test.b.Big()); cmp != test.cmp {
+			t.Fatalf("expected the big integers of %v and %v to compare as %v, got %v", test.a, test.b, test.cmp, cmp)
+		}
+	}
+
+	hashes := []Hash{Hash256([]byte("a")), Hash256([]byte("b")), Hash256([]byte("c")), NullHash()}
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i].Cmp(hashes[j]) < 0 })
+
+	if !hashes[0].IsZero() {
+		t.Fatalf("expected the null hash to sort first")
+	}
+
+	for idx := 1; idx < len(hashes); idx++ {
+		if hashes[idx-1].Cmp(hashes[idx]) >= 0 {
+			t.Fatalf("expected the hashes to be sorted")
+		}
+	}
+}
+
+// TestHashHex checks that Hex and String return the 0x prefixed form of a Hash and that BareHex returns it without
+// the prefix, and that only the prefixed form is parsed back into the Hash by HexToHash
+func TestHashHex(t *testing.T) {
+	hash := BytesToHash([]byte{0xab, 0x01})
+	bare := "000000000000000000000000000000000000000000000000000000000000ab01"
+
+	if hash.BareHex() != bare || hash.Hex() != "0x"+bare || hash.String() != hash.Hex() {
+		t.Fatalf("expected the hex forms of %v, got %v and %v", bare, hash.Hex(), hash.BareHex())
+	}
+
+	parsed, err := HexToHash(hash.Hex())
+	if err != nil {
+		t.Fatalf("failed to parse %v: %v", hash.Hex(), err)
+	}
+
+	if !parsed.Equal(hash) {
+		t.Fatalf("expected %v to parse into %v, got %v", hash.Hex(), hash, parsed)
+	}
+
+	if _, err := HexToHash(hash.BareHex()); err == nil {
+		t.Fatalf("expected the bare hex form not to be parsed")
+	}
+}
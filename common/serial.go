@@ -3,8 +3,29 @@ package common
 import (
 	"bytes"
 	"encoding/gob"
+	"errors"
+	"fmt"
+	"sort"
 )
 
+// ErrDataTooLarge is returned by GobDecodeLimit for data that exceeds its size limit
+var ErrDataTooLarge = errors.New("data exceeds size limit")
+
+// init registers the concrete types of the common package with gob, so that they can
+// be decoded when they are held in interface fields of serialized objects.
+func init() {
+	RegisterTypes(Hash{}, Address(""))
+}
+
+// RegisterTypes registers the given concrete types with gob.
+// All concrete types that may be serialized through an interface
+// value must be registered before any encoding or decoding occurs.
+func RegisterTypes(values ...any) {
+	for _, value := range values {
+		gob.Register(value)
+	}
+}
+
 // Serializable is an interface for types that can
 // be encoded to and decoded from a stream of bytes.
 type Serializable interface {
@@ -46,3 +67,56 @@ func GobDecode(data []byte, object any) (any, error) {
 	// Return the object
 	return object, nil
 }
+
+// GobDecodeLimit decodes a stream of bytes into a given object like GobDecode,
+// after checking that the data is no longer than the limit in bytes.
+// Gob never allocates a slice longer than its remaining input, so bounding the data bounds the
+// allocations of the decode. Returns an error wrapping ErrDataTooLarge if the data exceeds the limit.
+func GobDecodeLimit(data []byte, object any, limit int) (any, error) {
+	if len(data) > limit {
+		return nil, fmt.Errorf("%w: %v bytes exceeds %v bytes", ErrDataTooLarge, len(data), limit)
+	}
+
+	return GobDecode(data, object)
+}
+
+// Ordered is a constraint for the map key types that can be sorted for canonical serialization
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~string
+}
+
+// MapEntry represents a key and value pair of a map
+type MapEntry[K Ordered, V any] struct {
+	Key   K
+	Value V
+}
+
+// SortedEntries returns the entries of a map as a slice sorted by key.
+// Unlike a map, the slice serializes to the same bytes for the same logical contents,
+// so it must be used in place of a map in any structure that is hashed or compared by its bytes.
+func SortedEntries[K Ordered, V any](m map[K]V) []MapEntry[K, V] {
+	entries := make([]MapEntry[K, V], 0, len(m))
+	for key, value := range m {
+		entries = append(entries, MapEntry[K, V]{key, value})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
+// EntriesMap returns a map of the given entries. It is the inverse of SortedEntries.
+func EntriesMap[K Ordered, V any](entries []MapEntry[K, V]) map[K]V {
+	m := make(map[K]V, len(entries))
+	for _, entry := range entries {
+		m[entry.Key] = entry.Value
+	}
+
+	return m
+}
+
+// GobEncodeMap encodes a map into a gob encoded stream of bytes, as its entries sorted by key.
+// Gob encodes maps in their iteration order, which is random, so the same map yields different
+// bytes with GobEncode. The data can be decoded into a []MapEntry with GobDecode.
+func GobEncodeMap[K Ordered, V any](m map[K]V) ([]byte, error) {
+	return GobEncode(SortedEntries(m))
+}
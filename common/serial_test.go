@@ -0,0 +1,93 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestRegisteredTypes checks that the registered types of the package are decoded from interface fields
+func TestRegisteredTypes(t *testing.T) {
+	for _, value := range []any{Hash256([]byte("hash")), Address("address")} {
+		data, err := GobEncode(struct{ Value any }{value})
+		if err != nil {
+			t.Fatalf("%T: encode failed: %v", value, err)
+		}
+
+		decoded := new(struct{ Value any })
+		if _, err := GobDecode(data, decoded); err != nil {
+			t.Fatalf("%T: decode failed: %v", value, err)
+		}
+
+		if decoded.Value != value {
+			t.Fatalf("%T: expected %v, got %v", value, value, decoded.Value)
+		}
+	}
+}
+
+// TestGobDecodeLimit checks that data within the limit is decoded and that oversized data is rejected
+func TestGobDecodeLimit(t *testing.T) {
+	data, err := GobEncode(Hash256([]byte("hash")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := GobDecodeLimit(data, new(Hash), len(data)); err != nil {
+		t.Fatalf("expected data at the limit to be decoded, got %v", err)
+	}
+
+	if _, err := GobDecodeLimit(data, new(Hash), len(data)-1); !errors.Is(err, ErrDataTooLarge) {
+		t.Fatalf("expected data beyond the limit to be rejected, got %v", err)
+	}
+}
+
+// TestGobEncodeMap checks that repeated serializations of the same logical map, built in different orders,
+// yield identical bytes, which decode to the entries of the map sorted by key
+func TestGobEncodeMap(t *testing.T) {
+	keys := make([]Address, 0, 64)
+	for idx := 0; idx < 64; idx++ {
+		keys = append(keys, Address(fmt.Sprintf("address-%02d", idx)))
+	}
+
+	var encoded []byte
+	for run := 0; run < 8; run++ {
+		// Insert the keys in a different rotation for each run
+		m := make(map[Address]int, len(keys))
+		for idx := range keys {
+			key := keys[(idx+run*7)%len(keys)]
+			m[key] = len(key)
+		}
+
+		data, err := GobEncodeMap(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if encoded != nil && !bytes.Equal(data, encoded) {
+			t.Fatalf("expected identical bytes for the same map on run %v", run)
+		}
+
+		encoded = data
+	}
+
+	object, err := GobDecode(encoded, new([]MapEntry[Address, int]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := *object.(*[]MapEntry[Address, int])
+	if len(entries) != len(keys) {
+		t.Fatalf("expected %v entries, got %v", len(keys), len(entries))
+	}
+
+	for idx, entry := range entries {
+		if entry.Key != keys[idx] || entry.Value != len(keys[idx]) {
+			t.Fatalf("expected entry %v to be %v, got %+v", idx, keys[idx], entry)
+		}
+	}
+
+	if m := EntriesMap(entries); len(m) != len(keys) || m[keys[0]] != len(keys[0]) {
+		t.Fatalf("expected the entries to map back to the original map, got %v", m)
+	}
+}
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/anee769/essensio/common"
+)
+
+// ErrNoPendingTransactions is returned by MinePending if no pending Transaction can be included
+// in the Block and Options.AllowEmptyBlocks is not set
+var ErrNoPendingTransactions = errors.New("no pending transactions to mine")
+
+// RejectedTransaction represents a pending Transaction that was rejected from a Block, along with the reason
+type RejectedTransaction struct {
+	ID     common.Hash
+	Reason error
+}
+
+// BlockAssembly represents the result of mining the pending Transactions of the Mempool with MinePending
+type BlockAssembly struct {
+	// Block is the mined Block, which is the head of the chain
+	Block *Block
+	// Included are the IDs of the pending Transactions included in the Block, in order
+	Included []common.Hash
+	// Rejected are the pending Transactions that are no longer valid for the chain, which were evicted from the Mempool
+	Rejected []RejectedTransaction
+}
+
+// MinePending mines a Block with the pending Transactions of the Mempool, like MineBlock with the given data.
+// Each pending Transaction is checked against the chain and the Transactions included before it in order of
+// arrival, and Transactions that are no longer valid, such as those whose inputs were spent, are rejected from
+// the Block and evicted from the Mempool instead of failing the Block. Transactions that would exceed MaxBlockDataSize
+// are kept in the Mempool for a later Block. If no Transaction can be included, a Block with only the coinbase
+// is mined with Options.AllowEmptyBlocks, and ErrNoPendingTransactions is returned otherwise.
+func (chain *ChainManager) MinePending(data string) (*BlockAssembly, error) {
+	chain.chainMutex.Lock()
+	assembly, err := chain.minePending(data)
+	chain.chainMutex.Unlock()
+
+	if err != nil {
+		return assembly, err
+	}
+
+	chain.publish(ChainEvent{Added: []common.Hash{assembly.Block.BlockHash}})
+	return assembly, nil
+}
+
+// minePending mines a Block with the pending Transactions of the Mempool like MinePending,
+// without publishing it. Must be called with the chain lock held.
+func (chain *ChainManager) minePending(data string) (*BlockAssembly, error) {
+	assembly := new(BlockAssembly)
+
+	var txns Transactions
+	spent := make(map[Outpoint]common.Hash)
+
+	// Outputs of the included Transactions, which can be spent by the pending Transactions that follow them
+	pending := make(pendingOutputs)
+
+	// Pending Transactions that do not fit in the Block are deferred to a later Block, along with the
+	// Transactions that spend from them. The header and the coinbase are reserved the maximum of their sizes.
+	deferred := make(map[common.Hash]bool)
+	budget := MaxBlockDataSize - MaxHeaderDataSize - MaxTxDataSize
+
+	for _, txn := range chain.mempool.Transactions() {
+		if spendsDeferred(txn, deferred) {
+			deferred[txn.ID] = true
+			continue
+		}
+
+		size := txn.Size()
+		if size > budget {
+			deferred[txn.ID] = true
+			continue
+		}
+
+		_, err := chain.checkTransaction(txn, pending)
+
+		// Reject Transactions that spend an output already spent by an earlier Transaction in the Block
+		if err == nil {
+			for _, input := range txn.Inputs {
+				if spender, exists := spent[Outpoint{input.ID, input.Out}]; exists {
+					err = fmt.Errorf("output %v is spent by transaction '%v'", Outpoint{input.ID, input.Out}, spender)
+					break
+				}
+			}
+		}
+
+		if err != nil {
+			assembly.Rejected = append(assembly.Rejected, RejectedTransaction{txn.ID, err})
+			continue
+		}
+
+		for _, input := range txn.Inputs {
+			spent[Outpoint{input.ID, input.Out}] = txn.ID
+		}
+
+		pending.add(txn, chain.Height)
+		budget -= size
+
+		txns = append(txns, txn)
+		assembly.Included = append(assembly.Included, txn.ID)
+	}
+
+	// Evict the rejected Transactions from the Mempool
+	if len(assembly.Rejected) > 0 {
+		ids := make([]common.Hash, 0, len(assembly.Rejected))
+		for _, rejected := range assembly.Rejected {
+			chain.opts.Logger.Infof("Evicted pending transaction '%v': %v.", rejected.ID, rejected.Reason)
+			ids = append(ids, rejected.ID)
+		}
+
+		chain.mempool.Remove(ids...)
+	}
+
+	if len(txns) == 0 && !chain.opts.AllowEmptyBlocks {
+		return assembly, ErrNoPendingTransactions
+	}
+
+	block, err := chain.mineBlock(txns, data)
+	if err != nil {
+		return nil, err
+	}
+
+	assembly.Block = block
+	return assembly, nil
+}
+
+// spendsDeferred returns whether a Transaction spends an output of a deferred Transaction
+func spendsDeferred(txn *Transaction, deferred map[common.Hash]bool) bool {
+	for _, input := range txn.Inputs {
+		if deferred[input.ID] {
+			return true
+		}
+	}
+
+	return false
+}
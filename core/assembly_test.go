@@ -0,0 +1,86 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestMinePending checks that MinePending includes the valid pending Transactions in the Block, and reports
+// a pending Transaction whose input was spent before mining as rejected and evicts it from the Mempool
+func TestMinePending(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	miner := common.MinerAddress()
+
+	spend := signedTransaction(t, miner, []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: 90, PubKey: "alice"})
+	conflict := signedTransaction(t, miner, []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: 90, PubKey: "bob"})
+
+	if err := chain.MineBlock(Transactions{spend}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	// The conflict is added to the Mempool unchecked, as if it was pending when its input was spent
+	if err := chain.Mempool().Add(conflict, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := signedTransaction(t, "alice", []Outpoint{{spend.ID, 0}}, TxOutput{Value: 80, PubKey: "carol"})
+	if err := chain.SubmitTransaction(valid); err != nil {
+		t.Fatalf("failed to submit transaction: %v", err)
+	}
+
+	assembly, err := chain.MinePending("")
+	if err != nil {
+		t.Fatalf("failed to mine pending transactions: %v", err)
+	}
+
+	if len(assembly.Included) != 1 || !assembly.Included[0].Equal(valid.ID) {
+		t.Fatalf("expected only '%v' to be included, got %v", valid.ID, assembly.Included)
+	}
+
+	if len(assembly.Rejected) != 1 || !assembly.Rejected[0].ID.Equal(conflict.ID) || assembly.Rejected[0].Reason == nil {
+		t.Fatalf("expected '%v' to be rejected with a reason, got %+v", conflict.ID, assembly.Rejected)
+	}
+
+	if !assembly.Block.BlockHash.Equal(chain.Head) || assembly.Block.TxnCount() != 2 {
+		t.Fatalf("expected the chain head with the coinbase and the included transaction, got %v transactions", assembly.Block.TxnCount())
+	}
+
+	if size := chain.Mempool().Size(); size != 0 {
+		t.Fatalf("expected the rejected transaction to be evicted from the mempool, got %v pending", size)
+	}
+}
+
+// TestMinePendingEmpty checks that MinePending mines a valid coinbase-only Block that extends the chain
+// when the Mempool is empty, and fails with ErrNoPendingTransactions without Options.AllowEmptyBlocks
+func TestMinePendingEmpty(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	head := chain.Head
+
+	assembly, err := chain.MinePending("")
+	if err != nil {
+		t.Fatalf("failed to mine empty block: %v", err)
+	}
+
+	if assembly.Block.TxnCount() != 1 || !assembly.Block.BlockTxns[0].IsCoinbase() || len(assembly.Included) != 0 {
+		t.Fatalf("expected a block with only the coinbase, got %v transactions", assembly.Block.TxnCount())
+	}
+
+	if !assembly.Block.Priori.Equal(head) || !chain.Head.Equal(assembly.Block.BlockHash) || chain.Height != 2 {
+		t.Fatalf("expected the empty block to extend the chain, got height %v", chain.Height)
+	}
+
+	if err := chain.VerifyChain(); err != nil {
+		t.Fatalf("expected the chain with the empty block to be valid, got %v", err)
+	}
+
+	chain.opts.AllowEmptyBlocks = false
+	if _, err := chain.MinePending(""); !errors.Is(err, ErrNoPendingTransactions) {
+		t.Fatalf("expected empty blocks to be disallowed, got %v", err)
+	}
+
+	if chain.Height != 2 {
+		t.Fatalf("expected no block to be mined, got height %v", chain.Height)
+	}
+}
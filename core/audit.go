@@ -0,0 +1,147 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anee769/essensio/common"
+)
+
+// UTXODiscrepancy kinds reported by AuditUTXO
+const (
+	// DiscrepancyMissing is an output created and not spent by the chain that is absent from the UTXO set
+	DiscrepancyMissing = "missing"
+	// DiscrepancyUnexpected is an entry in the UTXO set for an output that is not unspent on the chain
+	DiscrepancyUnexpected = "unexpected"
+	// DiscrepancyMismatched is an entry in the UTXO set that differs from the output on the chain
+	DiscrepancyMismatched = "mismatched"
+	// DiscrepancyCorrupt is an entry in the UTXO set that cannot be decoded
+	DiscrepancyCorrupt = "corrupt"
+)
+
+// UTXODiscrepancy represents a difference between the UTXO set and the unspent outputs of the chain
+type UTXODiscrepancy struct {
+	// Kind is the kind of the discrepancy, one of the Discrepancy constants
+	Kind string
+	// Outpoint is the output of the discrepancy, which is zero for a corrupt entry with an invalid key
+	Outpoint Outpoint
+	// Expected is the entry of the output replayed from the chain, nil if the output is not unspent
+	Expected *UTXOEntry
+	// Actual is the entry of the output in the UTXO set, nil if the output is absent or corrupt
+	Actual *UTXOEntry
+}
+
+// UTXOAudit represents the result of an audit of the UTXO set with AuditUTXO
+type UTXOAudit struct {
+	// Height is the height of the chain that was audited
+	Height int64
+	// Head is the hash of the chain head that was audited
+	Head common.Hash
+	// Checked is the number of unspent outputs replayed from the chain
+	Checked int
+	// Discrepancies are the differences between the UTXO set and the chain
+	Discrepancies []UTXODiscrepancy
+}
+
+// Consistent returns whether the audit found no discrepancies
+func (audit *UTXOAudit) Consistent() bool {
+	return len(audit.Discrepancies) == 0
+}
+
+// AuditUTXO compares the maintained UTXO set against the unspent outputs replayed from every Block
+// of the chain, and reports any outputs that are present in one but not the other, or that differ.
+// Neither the UTXO set nor the chain is modified. The audit holds the UTXO set lock for reading, so Blocks
+// cannot be connected or disconnected while it runs, and it holds every unspent output in memory.
+func (chain *ChainManager) AuditUTXO() (*UTXOAudit, error) {
+	return chain.AuditUTXOContext(context.Background())
+}
+
+// AuditUTXOContext is AuditUTXO with a context that stops the audit when it is done
+func (chain *ChainManager) AuditUTXOContext(ctx context.Context) (*UTXOAudit, error) {
+	chain.utxoMutex.RLock()
+	defer chain.utxoMutex.RUnlock()
+
+	// Replay the unspent outputs of the chain from the Genesis Block
+	expected := make(map[Outpoint]UTXOEntry)
+	for height := int64(0); height < chain.Height; height++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		block, err := chain.GetBlockByHeight(height)
+		if err != nil {
+			return nil, fmt.Errorf("block at height %v retrieve failed: %w", height, err)
+		}
+
+		for _, txn := range block.BlockTxns {
+			if !txn.IsCoinbase() {
+				for _, input := range txn.Inputs {
+					delete(expected, Outpoint{input.ID, input.Out})
+				}
+			}
+
+			for idx, output := range txn.Outputs {
+				expected[Outpoint{txn.ID, idx}] = UTXOEntry{output, block.BlockHeight, txn.IsCoinbase()}
+			}
+		}
+	}
+
+	audit := &UTXOAudit{Height: chain.Height, Head: chain.Head, Checked: len(expected)}
+
+	// Compare every entry of the UTXO set against the replayed outputs
+	seen := make(map[Outpoint]struct{}, len(expected))
+	if err := chain.db.IteratePrefix(UTXOPrefix, func(key, value []byte) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		outpoint, ok := outpointFromKey(key)
+		if !ok {
+			audit.Discrepancies = append(audit.Discrepancies, UTXODiscrepancy{Kind: DiscrepancyCorrupt})
+			return nil
+		}
+
+		seen[outpoint] = struct{}{}
+		want, unspent := expected[outpoint]
+
+		discrepancy := UTXODiscrepancy{Outpoint: outpoint}
+		if unspent {
+			discrepancy.Expected = &want
+		}
+
+		object, err := common.GobDecode(value, new(UTXOEntry))
+		if err != nil {
+			discrepancy.Kind = DiscrepancyCorrupt
+			audit.Discrepancies = append(audit.Discrepancies, discrepancy)
+			return nil
+		}
+
+		discrepancy.Actual = object.(*UTXOEntry)
+
+		switch {
+		case !unspent:
+			discrepancy.Kind = DiscrepancyUnexpected
+		case *discrepancy.Actual != want:
+			discrepancy.Kind = DiscrepancyMismatched
+		default:
+			return nil
+		}
+
+		audit.Discrepancies = append(audit.Discrepancies, discrepancy)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("utxo scan failed: %w", err)
+	}
+
+	// Report the replayed outputs that are absent from the UTXO set
+	for outpoint, want := range expected {
+		if _, exists := seen[outpoint]; exists {
+			continue
+		}
+
+		want := want
+		audit.Discrepancies = append(audit.Discrepancies, UTXODiscrepancy{Kind: DiscrepancyMissing, Outpoint: outpoint, Expected: &want})
+	}
+
+	return audit, nil
+}
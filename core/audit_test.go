@@ -0,0 +1,101 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestAuditUTXOContext checks that the audit of the UTXO set stops when its context is done
+func TestAuditUTXOContext(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(5))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := chain.AuditUTXOContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the audit to be cancelled, got %v", err)
+	}
+}
+
+// TestAuditUTXO checks that the audit of a consistent UTXO set reports no discrepancies, and that entries of
+// the UTXO set that are deliberately changed, corrupted, added or removed are each reported with their kind
+func TestAuditUTXO(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(2))
+
+	audit, err := chain.AuditUTXO()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !audit.Consistent() || audit.Checked == 0 || audit.Height != chain.Height || !audit.Head.Equal(chain.Head) {
+		t.Fatalf("expected a consistent audit of the chain head, got %+v", audit)
+	}
+
+	tip, err := chain.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parent, err := chain.GetBlockByHeight(tip.BlockHeight - 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mismatched := Outpoint{tip.BlockTxns[0].ID, 0}
+	corrupt := Outpoint{tip.BlockTxns[1].ID, 0}
+	unexpected := Outpoint{common.Hash256([]byte("unexpected")), 0}
+	missing := Outpoint{parent.BlockTxns[0].ID, 0}
+
+	// Change the value of an entry, and store a copy of it for an output that does not exist
+	entry, err := chain.GetUTXOEntry(mismatched)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry.Output.Value++
+
+	changed, err := common.GobEncode(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for outpoint, value := range map[Outpoint][]byte{mismatched: changed, unexpected: changed, corrupt: []byte("corrupt")} {
+		if err := chain.db.SetEntry(outpoint.Key(), value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := chain.db.DeleteEntry(missing.Key()); err != nil {
+		t.Fatal(err)
+	}
+
+	audit, err = chain.AuditUTXO()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kinds := make(map[Outpoint]string)
+	for _, discrepancy := range audit.Discrepancies {
+		kinds[discrepancy.Outpoint] = discrepancy.Kind
+	}
+
+	want := map[Outpoint]string{
+		mismatched: DiscrepancyMismatched,
+		corrupt:    DiscrepancyCorrupt,
+		unexpected: DiscrepancyUnexpected,
+		missing:    DiscrepancyMissing,
+	}
+
+	if len(kinds) != len(want) {
+		t.Fatalf("expected discrepancies %v, got %v", want, kinds)
+	}
+
+	for outpoint, kind := range want {
+		if kinds[outpoint] != kind {
+			t.Fatalf("expected a %v discrepancy for %v, got %q", kind, outpoint, kinds[outpoint])
+		}
+	}
+}
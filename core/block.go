@@ -25,8 +25,8 @@ func (block *Block) String() string {
 	var s strings.Builder
 
 	s.WriteString(fmt.Sprintf("=======[%v][%v]\n", block.BlockHeight, time.Unix(block.Timestamp, 0)))
-	s.WriteString(fmt.Sprintf("Block Hash: 0x%x\n", block.BlockHash))
-	s.WriteString(fmt.Sprintf("Priori Hash: 0x%x\n", block.Priori))
+	s.WriteString(fmt.Sprintf("Block Hash: %v\n", block.BlockHash))
+	s.WriteString(fmt.Sprintf("Priori Hash: %v\n", block.Priori))
 	s.WriteString(fmt.Sprintf("Data: %v\n", block.BlockTxns))
 	s.WriteString(fmt.Sprintf("Nonce: %v\n", block.Nonce))
 	s.WriteString("=========================================\n")
@@ -35,8 +35,13 @@ func (block *Block) String() string {
 }
 
 // NewBlock generates a new Block for some given data,
-// the hash of the previous block and the block height
-func NewBlock(txns Transactions, priori common.Hash, height int64) *Block {
+// the hash of the previous block, the block height and the compact PoW target
+func NewBlock(txns Transactions, priori common.Hash, height int64, bits uint32) *Block {
+	// Normalize an empty set of Transactions to nil, which is how it is deserialized
+	if len(txns) == 0 {
+		txns = nil
+	}
+
 	block := &Block{
 		BlockTxns:   txns,
 		BlockHeight: height,
@@ -45,8 +50,8 @@ func NewBlock(txns Transactions, priori common.Hash, height int64) *Block {
 	// Generate the hash of the data
 	summary := GenerateSummary(txns)
 
-	// Create a BlockHeader with the priori and summary
-	header := NewBlockHeader(priori, summary)
+	// Create a BlockHeader with the priori, summary and target
+	header := NewBlockHeader(priori, summary, bits)
 	block.BlockHeader = header
 
 	// Mine the Block & set the block hash
@@ -67,10 +72,11 @@ func (block *Block) Serialize() ([]byte, error) {
 }
 
 // Deserialize implements the common.Serializable interface for Block.
-// Converts the given data into Block and sets it the method's receiver using common.GobDecode.
+// Converts the given data into Block and sets it the method's receiver using common.GobDecodeLimit.
+// Returns an error without decoding if the data is longer than MaxBlockDataSize.
 func (block *Block) Deserialize(data []byte) error {
 	// Decode the data into a *Block
-	object, err := common.GobDecode(data, new(Block))
+	object, err := common.GobDecodeLimit(data, new(Block), MaxBlockDataSize)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/anee769/essensio/common"
+)
+
+// BlockPath returns the Blocks that connect two Blocks along their Priori links, ordered from the
+// ancestor to the descendant and including both. Either Block may be the ancestor, and the Blocks need
+// not be on the chain. Returns an error if neither Block descends from the other, or if the path would
+// have more than limit Blocks. The path is not limited if limit is 0.
+func (chain *ChainManager) BlockPath(from, to common.Hash, limit int64) ([]*Block, error) {
+	ancestor, err := chain.GetBlock(from)
+	if err != nil {
+		return nil, err
+	}
+
+	descendant, err := chain.GetBlock(to)
+	if err != nil {
+		return nil, err
+	}
+
+	if ancestor.BlockHeight > descendant.BlockHeight {
+		ancestor, descendant = descendant, ancestor
+	}
+
+	length := descendant.BlockHeight - ancestor.BlockHeight + 1
+	if limit > 0 && length > limit {
+		return nil, fmt.Errorf("path of %v blocks exceeds the maximum of %v blocks", length, limit)
+	}
+
+	// Walk the Priori links from the descendant down to the height of the ancestor
+	path := make([]*Block, length)
+	path[length-1] = descendant
+
+	for idx := length - 2; idx >= 0; idx-- {
+		block, err := chain.GetBlock(path[idx+1].Priori)
+		if err != nil {
+			return nil, err
+		}
+
+		if block.BlockHeight != path[idx+1].BlockHeight-1 {
+			return nil, fmt.Errorf("block '%v' at height %v has priori at height %v", path[idx+1].BlockHash, path[idx+1].BlockHeight, block.BlockHeight)
+		}
+
+		path[idx] = block
+	}
+
+	if !path[0].BlockHash.Equal(ancestor.BlockHash) {
+		return nil, fmt.Errorf("blocks '%v' and '%v' are not on the same line", ancestor.BlockHash, descendant.BlockHash)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestBlockPath checks that the path between the Genesis Block and the chain head lists every Block in order of height
+// for either order of its arguments, that it is bounded by the limit, and that Blocks on different lines have no path
+func TestBlockPath(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(4))
+
+	genesis, err := chain.BlockHashAtHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range [][2]int64{{0, 4}, {4, 0}} {
+		from, err := chain.BlockHashAtHeight(path[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		to, err := chain.BlockHashAtHeight(path[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		blocks, err := chain.BlockPath(from, to, 0)
+		if err != nil {
+			t.Fatalf("failed to find block path: %v", err)
+		}
+
+		if int64(len(blocks)) != chain.Height {
+			t.Fatalf("expected a path of %v blocks, got %v", chain.Height, len(blocks))
+		}
+
+		for height, block := range blocks {
+			hash, err := chain.BlockHashAtHeight(int64(height))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !block.BlockHash.Equal(hash) {
+				t.Fatalf("expected block '%v' at position %v of the path, got '%v'", hash, height, block.BlockHash)
+			}
+		}
+	}
+
+	if _, err := chain.BlockPath(genesis, chain.Head, chain.Height-1); err == nil || !strings.Contains(err.Error(), "exceeds the maximum") {
+		t.Fatalf("expected a path beyond the limit to be rejected, got %v", err)
+	}
+
+	// Store a Block that branches from the Block at height 1 without connecting it
+	parent, err := chain.GetBlockByHeight(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	branch := branchBlock(t, chain, parent, "branch")
+	if err := chain.storeBlock(branch); err != nil {
+		t.Fatal(err)
+	}
+
+	if blocks, err := chain.BlockPath(genesis, branch.BlockHash, 0); err != nil || len(blocks) != 3 {
+		t.Fatalf("expected a path of 3 blocks from the genesis block to the branch, got %v", err)
+	}
+
+	sibling, err := chain.BlockHashAtHeight(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := chain.BlockPath(sibling, branch.BlockHash, 0); err == nil || !strings.Contains(err.Error(), "not on the same line") {
+		t.Fatalf("expected blocks on different lines to have no path, got %v", err)
+	}
+}
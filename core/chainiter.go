@@ -14,36 +14,84 @@ type ChainIterator struct {
 	cursor common.Hash
 	// Represents the database containing all Block data indexed by their hash
 	database *db.Database
+	// Represents the number of Blocks that can still be returned by the iterator
+	remaining int64
+	// Represents whether Blocks are checked against the hash they are stored under
+	verify bool
 }
 
+// IteratorSlack is the number of Blocks beyond the chain height that a ChainIterator may return
+// before it fails. It bounds the iteration of a corrupted chain whose Priori links form a cycle.
+const IteratorSlack = 16
+
 // NewIterator constructs a new ChainIterator for the BlockChain.
 func (chain *ChainManager) NewIterator() *ChainIterator {
-	return &ChainIterator{chain.Head, chain.db}
+	return &ChainIterator{chain.Head, chain.db, chain.Height + IteratorSlack, chain.opts.VerifyBlockHashes}
+}
+
+// NewIteratorFrom constructs a new ChainIterator for the BlockChain that starts
+// at the Block at the given height, located with the height index.
+func (chain *ChainManager) NewIteratorFrom(height int64) (*ChainIterator, error) {
+	hash, err := chain.BlockHashAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChainIterator{hash, chain.db, height + 1 + IteratorSlack, chain.opts.VerifyBlockHashes}, nil
+}
+
+// advance moves the iterator cursor to the given priori of the current Block.
+// Returns an error if the Block refers to itself or the iteration bound is exceeded.
+func (iter *ChainIterator) advance(priori common.Hash) error {
+	if priori.Equal(iter.cursor) {
+		return fmt.Errorf("block '%v' refers to itself as priori", iter.cursor)
+	}
+
+	if iter.remaining--; iter.remaining < 0 {
+		return fmt.Errorf("iteration exceeded the chain height at block '%v': priori links may form a cycle", iter.cursor)
+	}
+
+	iter.cursor = priori
+	return nil
 }
 
 // Next returns the next Block in the ChainIterator.
-// Returns an error if a Block is not found or is invalid.
+// Returns an error if a Block is not found or is invalid, or if a cycle of Priori links is detected.
+// With Options.VerifyBlockHashes, it also returns an error for a Block stored under a different hash.
 func (iter *ChainIterator) Next() (*Block, error) {
 	// Find the Block with hash represented by the iterator cursor
-	data, err := iter.database.GetEntry(iter.cursor.Bytes())
+	block, err := readBlock(iter.database, iter.cursor, iter.verify)
 	if err != nil {
-		return nil, fmt.Errorf("cannot finding block '%x': %w", iter.cursor, err)
+		return nil, err
 	}
 
-	// Create a new Block and deserialize the block data into it
-	block := new(Block)
-	if err := block.Deserialize(data); err != nil {
-		return nil, fmt.Errorf("block deserialize failed: %w", err)
+	// Update the iterator cursor to the hash of the previous Block
+	if err := iter.advance(block.Priori); err != nil {
+		return nil, err
 	}
 
-	// Update the iterator cursor to the hash of the previous Block
-	iter.cursor = block.Priori
 	return block, nil
 }
 
+// NextHeader returns the BlockHeader of the next Block in the ChainIterator, without loading its body.
+// It advances the ChainIterator like Next, so it is suited for walking the Priori links of the chain.
+func (iter *ChainIterator) NextHeader() (*BlockHeader, error) {
+	header, err := getHeader(iter.database, iter.cursor, iter.verify)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the iterator cursor to the hash of the previous Block
+	if err := iter.advance(header.Priori); err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
 // Done returns whether the ChainIterator has
 // reached the Genesis Block of the chain.
 func (iter *ChainIterator) Done() bool {
 	// If the cursor hash is null, the ChainIterator is done
-	return iter.cursor == common.NullHash()
+	return iter.cursor.IsZero()
 }
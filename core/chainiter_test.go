@@ -0,0 +1,202 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestNextHeader checks that iterating over the headers of the chain yields the headers of the Blocks
+// returned by Next, linked by their Priori hashes
+func TestNextHeader(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(10))
+
+	blocks, headers := chain.NewIterator(), chain.NewIterator()
+	priori := chain.Head
+
+	for !blocks.Done() {
+		block, err := blocks.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if headers.Done() {
+			t.Fatalf("expected the header iterator to end with the block iterator")
+		}
+
+		header, err := headers.NextHeader()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if *header != block.BlockHeader || !header.Hash().Equal(block.BlockHash) {
+			t.Fatalf("expected the header of block '%v' at height %v", block.BlockHash, block.BlockHeight)
+		}
+
+		// Each header is the Block at the Priori of the header before it, from the chain head
+		if !block.BlockHash.Equal(priori) {
+			t.Fatalf("expected the block at priori '%v', got '%v'", priori, block.BlockHash)
+		}
+
+		priori = header.Priori
+	}
+
+	if !headers.Done() {
+		t.Fatalf("expected the header iterator to end at the genesis block")
+	}
+}
+
+// benchmarkIterator benchmarks the iteration of a chain of Blocks with many Transactions with fn
+func benchmarkIterator(b *testing.B, fn func(iter *ChainIterator) error) {
+	opts := testChainOptions(50)
+	opts.TxnsPerBlock = 20
+
+	chain := newTestChain(b, opts)
+	b.ResetTimer()
+
+	for idx := 0; idx < b.N; idx++ {
+		iter := chain.NewIterator()
+		for !iter.Done() {
+			if err := fn(iter); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkIteratorNext benchmarks the iteration of full Blocks
+func BenchmarkIteratorNext(b *testing.B) {
+	benchmarkIterator(b, func(iter *ChainIterator) error {
+		_, err := iter.Next()
+		return err
+	})
+}
+
+// BenchmarkIteratorNextHeader benchmarks the iteration of headers, which is faster as the bodies are not loaded
+func BenchmarkIteratorNextHeader(b *testing.B) {
+	benchmarkIterator(b, func(iter *ChainIterator) error {
+		_, err := iter.NextHeader()
+		return err
+	})
+}
+
+// corruptPriori overwrites the stored Block at the given height with a Block whose priori is the given hash,
+// stored under its original hash
+func corruptPriori(t *testing.T, chain *ChainManager, height int64, priori common.Hash) {
+	t.Helper()
+
+	block, err := chain.GetBlockByHeight(height)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block.Priori = priori
+	if err := chain.storeBlock(block); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestIteratorCycle checks that iterating over a chain whose Priori links form a cycle fails rather than hanging,
+// for both a self-referential Block and a cycle of two Blocks
+func TestIteratorCycle(t *testing.T) {
+	iterate := func(next func(iter *ChainIterator) error, iter *ChainIterator) error {
+		for !iter.Done() {
+			if err := next(iter); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	nexts := map[string]func(iter *ChainIterator) error{
+		"Next":       func(iter *ChainIterator) error { _, err := iter.Next(); return err },
+		"NextHeader": func(iter *ChainIterator) error { _, err := iter.NextHeader(); return err },
+	}
+
+	for name, next := range nexts {
+		t.Run(name, func(t *testing.T) {
+			opts := testChainOptions(3)
+			opts.Options.VerifyBlockHashes = false
+
+			chain := newTestChain(t, opts)
+
+			// The chain head refers to itself
+			corruptPriori(t, chain, chain.Height-1, chain.Head)
+			if err := iterate(next, chain.NewIterator()); err == nil || !strings.Contains(err.Error(), "refers to itself") {
+				t.Fatalf("expected a self-referential block to fail the iteration, got %v", err)
+			}
+
+			// The chain head refers to the Block below it, which refers back to the chain head
+			parent, err := chain.BlockHashAtHeight(chain.Height - 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			corruptPriori(t, chain, chain.Height-1, parent)
+			corruptPriori(t, chain, chain.Height-2, chain.Head)
+
+			if err := iterate(next, chain.NewIterator()); err == nil || !strings.Contains(err.Error(), "may form a cycle") {
+				t.Fatalf("expected a cycle of blocks to fail the iteration, got %v", err)
+			}
+		})
+	}
+}
+
+// TestVerifyBlockHashes checks that with Options.VerifyBlockHashes, a Block and a header stored under the hash
+// of another Block are detected by GetBlock, GetHeader and the ChainIterator, and go unnoticed without it
+func TestVerifyBlockHashes(t *testing.T) {
+	opts := testChainOptions(2)
+	opts.Options.VerifyBlockHashes = true
+
+	chain := newTestChain(t, opts)
+
+	block, err := chain.GetBlockByHeight(chain.Height - 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Store the Block below the chain head and its header under the hash of the chain head
+	data, err := block.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, err := block.BlockHeader.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.db.SetEntry(chain.Head.Bytes(), data); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.db.SetEntry(headerKey(chain.Head), header); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := chain.GetBlock(chain.Head); !errors.Is(err, ErrBlockHashMismatch) {
+		t.Fatalf("expected the mis-keyed block to be detected, got %v", err)
+	}
+
+	if _, err := chain.GetHeader(chain.Head); !errors.Is(err, ErrBlockHashMismatch) {
+		t.Fatalf("expected the mis-keyed header to be detected, got %v", err)
+	}
+
+	if _, err := chain.NewIterator().Next(); !errors.Is(err, ErrBlockHashMismatch) {
+		t.Fatalf("expected the iterator to detect the mis-keyed block, got %v", err)
+	}
+
+	if _, err := chain.NewIterator().NextHeader(); !errors.Is(err, ErrBlockHashMismatch) {
+		t.Fatalf("expected the iterator to detect the mis-keyed header, got %v", err)
+	}
+
+	chain.opts.VerifyBlockHashes = false
+
+	read, err := chain.GetBlock(chain.Head)
+	if err != nil || !read.BlockHash.Equal(block.BlockHash) {
+		t.Fatalf("expected the mis-keyed block to be read without verification, got %v", err)
+	}
+}
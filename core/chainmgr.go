@@ -1,13 +1,22 @@
 package core
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
+	"sync"
 
 	"github.com/anee769/essensio/common"
 	"github.com/anee769/essensio/db"
 )
 
 var (
+	ChainStateKey    = []byte("state-chain")
+	ChainTxnCountKey = []byte("state-chaintxncount")
+	ChainSupplyKey   = []byte("state-chainsupply")
+
+	// Legacy keys of the chain head and height, stored separately before ChainState.
+	// Databases with these keys are migrated to ChainStateKey when loaded.
 	ChainHeadKey   = []byte("state-chainhead")
 	ChainHeightKey = []byte("state-chainheight")
 )
@@ -17,55 +26,303 @@ type ChainManager struct {
 	// Represents the database of blockchain data
 	// This contains the state and blocks of the blockchain
 	db *db.Database
+	// Represents the configuration of the chain
+	opts Options
 
 	// Represents the hash of the last Block
 	Head common.Hash
 	// Represents the Height of the chain. Last block Height+1
 	Height int64
+	// Represents the cumulative Proof of Work of the Blocks on the chain
+	Work *big.Int
+
+	// Represents the number of Transactions on the chain
+	TxnCount int64
+	// Represents the total value issued by coinbase Transactions on the chain
+	Supply int
+
+	// Represents the cached Block at the chain head
+	tip *Block
+	// Represents the pool of pending Transactions
+	mempool *Mempool
+	// Represents the pool of orphan Transactions
+	orphans *OrphanPool
+	// Represents the write-ahead log of the chain, if its durability is batched
+	wal *writeAheadLog
+
+	// Represents the subscriptions to chain events
+	subs      map[*Subscription]struct{}
+	subsMutex sync.Mutex
+
+	// Represents the lock on the chain for writers, held from the verification of a Block until it is connected,
+	// so that concurrent writers never verify Blocks against the same chain head
+	chainMutex sync.Mutex
+	// Represents the lock on the UTXO set and chain head, held for writing while a Block is connected or
+	// disconnected and for reading by the lookups and scans of the UTXO set
+	utxoMutex sync.RWMutex
+
+	// Represents the locks on the coin selection of each Address
+	spends spendLocks
+
+	// Represents the callbacks for finalized Blocks
+	finalizers []*finalizer
+	finalMutex sync.Mutex
 }
 
 // String implements the Stringer interface for BlockChain
 func (chain *ChainManager) String() string {
-	return fmt.Sprintf("Chain Head: %x || Chain Height: %v", chain.Head, chain.Height)
+	return fmt.Sprintf("Chain Head: %v || Chain Height: %v", chain.Head, chain.Height)
 }
 
-// AddBlock generates and appends a Block to the chain for a given string data.
+// AddBlock generates and appends a Block to the chain for a given set of Transactions, the first of
+// which must be the coinbase of the Block, such as from NewCoinbase.
+// The Transactions are verified against the UTXO set before the Block is generated.
 // The generated block is stored in the database. Any error that occurs is returned.
 func (chain *ChainManager) AddBlock(txns Transactions) error {
-	// Create a new Block with the given data
-	block := NewBlock(txns, chain.Head, chain.Height)
+	chain.chainMutex.Lock()
+	block, err := chain.addBlock(txns)
+	chain.chainMutex.Unlock()
 
-	// Serialize the Block
-	blockData, err := block.Serialize()
 	if err != nil {
-		return fmt.Errorf("block serialize failed: %w", err)
+		return err
+	}
+
+	chain.publish(ChainEvent{Added: []common.Hash{block.BlockHash}})
+	return nil
+}
+
+// addBlock generates and appends a Block to the chain for a given set of Transactions like AddBlock,
+// without publishing it. Must be called with the chain lock held.
+func (chain *ChainManager) addBlock(txns Transactions) (*Block, error) {
+	// Assemble a new Block with the given data
+	block, err := chain.SimulateBlock(txns)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify that the Block extends the chain head and can be deserialized again
+	if err := checkBlockSize(block); err != nil {
+		return nil, fmt.Errorf("block verification failed: %w", err)
+	}
+
+	if err := chain.verifyHeader(block); err != nil {
+		return nil, fmt.Errorf("block verification failed: %w", err)
+	}
+
+	if err := chain.appendBlock(block); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// ErrKnownBlock is returned by AcceptBlock for a Block that is already on the chain.
+// Receiving a Block again is benign, so callers can treat it as a successful no-op.
+var ErrKnownBlock = errors.New("already have block")
+
+// AcceptBlock verifies and appends an externally generated Block to the chain.
+// The Block must extend the chain head and contain valid Transactions.
+// Returns an error wrapping ErrKnownBlock if the Block is already on the chain.
+func (chain *ChainManager) AcceptBlock(block *Block) error {
+	chain.chainMutex.Lock()
+	err := chain.acceptBlock(block)
+	chain.chainMutex.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	chain.publish(ChainEvent{Added: []common.Hash{block.BlockHash}})
+	return nil
+}
+
+// acceptBlock verifies and appends an externally generated Block to the chain like AcceptBlock,
+// without publishing it. Must be called with the chain lock held.
+func (chain *ChainManager) acceptBlock(block *Block) error {
+	known, err := chain.HasBlock(block.BlockHash)
+	if err != nil {
+		return fmt.Errorf("block lookup failed: %w", err)
+	}
+
+	if known {
+		return fmt.Errorf("block '%v': %w", block.BlockHash, ErrKnownBlock)
+	}
+
+	if err := chain.VerifyBlock(block); err != nil {
+		return fmt.Errorf("block verification failed: %w", err)
+	}
+
+	return chain.appendBlock(block)
+}
+
+// appendBlock stores a verified Block in the database and updates the chain state with it
+func (chain *ChainManager) appendBlock(block *Block) error {
+	chain.utxoMutex.Lock()
+	defer chain.utxoMutex.Unlock()
+
+	// Record the block in the write-ahead log before applying it
+	if err := chain.logBlock(block); err != nil {
+		return err
 	}
 
 	// Add block to db
-	if err := chain.db.SetEntry(block.BlockHash.Bytes(), blockData); err != nil {
-		return fmt.Errorf("block store to db failed: %w", err)
+	if err := chain.storeBlock(block); err != nil {
+		return err
+	}
+
+	// Apply the block to the UTXO set
+	if err := chain.updateUTXO(block); err != nil {
+		return fmt.Errorf("utxo update failed: %w", err)
+	}
+
+	// Snapshot the balances if the block is at a snapshot interval
+	if err := chain.snapshotBalances(block); err != nil {
+		return err
+	}
+
+	// Add the block to the height index
+	if err := chain.indexBlock(block); err != nil {
+		return err
+	}
+
+	// Prune the undo data that falls outside the retention
+	if err := chain.pruneUndo(block); err != nil {
+		return err
 	}
 
 	// Update the chain head with the new block hash and increment chain height
 	chain.Head = block.BlockHash
 	chain.Height++
+	chain.tip = block
+	chain.countBlock(block)
 
 	// Sync the chain state into the DB
 	if err := chain.syncState(); err != nil {
 		return fmt.Errorf("chain state sync failed: %w", err)
 	}
 
+	// Remove the block transactions and any conflicts from the mempool
+	chain.mempool.removeBlock(block)
+
+	// Flush the chain to disk if the batch is due
+	if err := chain.maybeFlush(); err != nil {
+		return fmt.Errorf("chain flush failed: %w", err)
+	}
+
+	chain.opts.Logger.Debugf("Added block '%v' at height %v.", block.BlockHash, block.BlockHeight)
 	return nil
 }
 
+// SimulateBlock generates the Block that would be appended to the chain for a given set of Transactions.
+// The Transactions are verified against the UTXO set, but neither the Block nor the chain state is stored.
+func (chain *ChainManager) SimulateBlock(txns Transactions) (*Block, error) {
+	// Verify the transactions against the current chain state
+	if err := chain.verifyTransactions(txns); err != nil {
+		return nil, fmt.Errorf("block verification failed: %w", err)
+	}
+
+	// Determine the PoW target for the new Block
+	bits, err := chain.nextBits()
+	if err != nil {
+		return nil, fmt.Errorf("target generation failed: %w", err)
+	}
+
+	// Create a new Block with the given data
+	return NewBlock(txns, chain.Head, chain.Height, bits), nil
+}
+
+// MineBlock generates and appends a Block to the chain for a given set of Transactions,
+// along with the coinbase Transaction generated by NewCoinbase for them with the given data.
+func (chain *ChainManager) MineBlock(txns Transactions, data string) error {
+	chain.chainMutex.Lock()
+	block, err := chain.mineBlock(txns, data)
+	chain.chainMutex.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	chain.publish(ChainEvent{Added: []common.Hash{block.BlockHash}})
+	return nil
+}
+
+// mineBlock generates and appends a Block to the chain for a given set of Transactions and its coinbase
+// like MineBlock, without publishing it. Must be called with the chain lock held.
+func (chain *ChainManager) mineBlock(txns Transactions, data string) (*Block, error) {
+	coinbase, err := chain.NewCoinbase(txns, data)
+	if err != nil {
+		return nil, fmt.Errorf("coinbase generation failed: %w", err)
+	}
+
+	// Prepend the coinbase Transaction to the Block Transactions
+	return chain.addBlock(append(Transactions{coinbase}, txns...))
+}
+
+// NewCoinbase generates the coinbase Transaction of the next Block of the chain for a given set of Transactions.
+// The coinbase pays the block reward and the fees of the Transactions to the miner address, or splits them
+// across Options.CoinbaseShares if configured. It is tagged with the given data, or Options.CoinbaseData if empty.
+func (chain *ChainManager) NewCoinbase(txns Transactions, data string) (*Transaction, error) {
+	if data == "" {
+		data = chain.opts.CoinbaseData
+	}
+
+	fees, err := chain.TransactionFees(txns)
+	if err != nil {
+		return nil, fmt.Errorf("fee calculation failed: %w", err)
+	}
+
+	shares := chain.opts.CoinbaseShares
+	if len(shares) == 0 {
+		shares = []CoinbaseShare{{Address: common.MinerAddress(), Share: 1}}
+	}
+
+	return SplitCoinbaseTxn(shares, data, chain.Height, fees)
+}
+
 // NewChainManager returns a new BlockChain with an initialized
-// Genesis Block with the provided genesis data.
-func NewChainManager() (*ChainManager, error) {
+// Genesis Block with the provided genesis data, configured with the given Options.
+func NewChainManager(opts Options) (*ChainManager, error) {
+	if CompactToTarget(opts.InitialBits).Sign() <= 0 {
+		return nil, fmt.Errorf("invalid initial target bits: %08x", opts.InitialBits)
+	}
+
+	if len(opts.CoinbaseShares) > 0 {
+		if err := verifyShares(opts.CoinbaseShares); err != nil {
+			return nil, fmt.Errorf("invalid coinbase shares: %w", err)
+		}
+	}
+
+	// Retarget with the default algorithm without a DifficultyAdjuster
+	if opts.DifficultyAdjuster == nil {
+		opts.DifficultyAdjuster = DefaultWindowAdjuster()
+	}
+
+	// Discard chain events without a Logger
+	if opts.Logger == nil {
+		opts.Logger = NopLogger()
+	}
+
 	// Create a new ChainManager object
-	chain := new(ChainManager)
+	chain := &ChainManager{opts: opts, Work: new(big.Int), mempool: NewMempool(), orphans: NewOrphanPool(opts.MaxOrphans), subs: make(map[*Subscription]struct{})}
 
-	// Check if the database already exists
-	if db.Exists() {
+	// Check if the database already exists, before opening it creates the database
+	exists := db.Exists()
+
+	// Open the database
+	if err := chain.openDB(); err != nil {
+		return nil, err
+	}
+
+	// Check if the database holds an initialized chain
+	initialized := false
+	if exists {
+		var err error
+		if initialized, err = chain.initialized(); err != nil {
+			return nil, fmt.Errorf("chain state lookup failed: %w", err)
+		}
+	}
+
+	if initialized {
 		// Load blockchain state from database
 		if err := chain.load(); err != nil {
 			return nil, fmt.Errorf("failed to load existing blockchain: %w", err)
@@ -81,66 +338,115 @@ func NewChainManager() (*ChainManager, error) {
 	return chain, nil
 }
 
+// initialized returns whether the database holds an initialized chain, which is when the chain state
+// is stored at ChainStateKey or the legacy ChainHeadKey. The Genesis Block and the chain state are
+// written together by init, so a database without a chain state never holds a partial chain.
+func (chain *ChainManager) initialized() (bool, error) {
+	for _, key := range [][]byte{ChainStateKey, ChainHeadKey} {
+		exists, err := chain.db.HasEntry(key)
+		if err != nil || exists {
+			return exists, err
+		}
+	}
+
+	return false, nil
+}
+
 // load restarts a ChainManager from the database.
 // It updates its in-memory chain state chain information from the DB.
+// If the Block at the chain head is corrupt, the chain is rolled back to the last valid Block.
 func (chain *ChainManager) load() (err error) {
-	// Open the database
-	if chain.db, err = db.Open(); err != nil {
-		return err
+	// Get the chain head, height and work
+	legacy, err := chain.loadState()
+	if err != nil {
+		return fmt.Errorf("chain state retrieve failed: %w", err)
 	}
 
-	// Get the chain head and set it
-	head, err := chain.db.GetEntry(ChainHeadKey)
-	if err != nil {
-		return fmt.Errorf("chain head retrieve failed: %w", err)
+	// Rewrite the Blocks of a chain in the legacy block format, which cannot be verified or recovered
+	if legacy {
+		if err := chain.migrateLegacyBlocks(); err != nil {
+			return fmt.Errorf("chain block migration failed: %w", err)
+		}
 	}
 
-	// Get the chain height
-	height, err := chain.db.GetEntry(ChainHeightKey)
-	if err != nil {
-		return fmt.Errorf("chain height retrieve failed: %w", err)
+	// Verify the chain head and recover from a corrupt head if required
+	if err := chain.recoverHead(); err != nil {
+		return fmt.Errorf("chain recovery failed: %w", err)
 	}
 
-	// Deserialize the height into an int64
-	object, err := common.GobDecode(height, new(int64))
-	if err != nil {
-		return fmt.Errorf("error deserializing chain height: %w", err)
+	// Load the chain statistics
+	if err := chain.loadStats(); err != nil {
+		return fmt.Errorf("chain statistics retrieve failed: %w", err)
+	}
+
+	// Migrate a chain state stored at the legacy keys, once the chain head is verified
+	if legacy {
+		if err := chain.finishMigration(); err != nil {
+			return fmt.Errorf("chain state migration failed: %w", err)
+		}
 	}
 
-	// Cast the object into an int64 and set it
-	chain.Height = *object.(*int64)
-	// Convert the head bytes into a Hash and set it
-	chain.Head = common.BytesToHash(head)
+	// Recover any Blocks that were not flushed before a crash
+	if err := chain.recoverWAL(); err != nil {
+		return fmt.Errorf("write-ahead log recovery failed: %w", err)
+	}
 
 	return nil
 }
 
 // init initializes a new chain in the database.
 // It generates a Genesis Block and adds it to DB and updates all chain state data.
+// All the writes of the Genesis Block and the chain state are applied in a single database Batch,
+// so a crash during init leaves the database without a chain, which is initialized again when reopened.
 func (chain *ChainManager) init() (err error) {
-	// Open the database
-	if chain.db, err = db.Open(); err != nil {
-		return err
+	chain.opts.Logger.Infof("New blockchain initialization. Creating genesis block.")
+
+	// Create Genesis Block
+	genesisBlock, err := NewGenesisBlock(chain.opts.Genesis, chain.opts.InitialBits)
+	if err != nil {
+		return fmt.Errorf("genesis block generation failed: %w", err)
 	}
 
-	fmt.Println(">>>> New Blockchain Initialization. Creating Genesis Block <<<<")
+	if err := chain.db.Batch(func() error {
+		return chain.initGenesis(genesisBlock)
+	}); err != nil {
+		// Reset the chain state, none of which was stored
+		chain.Head, chain.Height, chain.tip = common.NullHash(), 0, nil
+		chain.TxnCount, chain.Supply, chain.Work = 0, 0, new(big.Int)
 
-	// Create Genesis Block & serialize it
-	genesisBlock := NewBlock(
-		Transactions{CoinbaseTxn(common.MinerAddress(), "Genesis Block Coinbase Transaction")},
-		common.NullHash(), 0)
-	genesisData, err := genesisBlock.Serialize()
-	if err != nil {
-		return fmt.Errorf("block serialize failed: %w", err)
+		return err
+	}
+
+	// Flush the new chain to disk and discard any stale write-ahead log
+	if err := chain.Flush(); err != nil {
+		return fmt.Errorf("chain flush failed: %w", err)
 	}
 
+	return nil
+}
+
+// initGenesis stores the Genesis Block, applies it to the UTXO set and indexes
+// and sets it as the chain head, with the chain state synced into the DB
+func (chain *ChainManager) initGenesis(genesisBlock *Block) error {
 	// Add Genesis Block to DB
-	if err := chain.db.SetEntry(genesisBlock.BlockHash.Bytes(), genesisData); err != nil {
-		return fmt.Errorf("genesis block store to db failed: %w", err)
+	if err := chain.storeBlock(genesisBlock); err != nil {
+		return fmt.Errorf("genesis block store failed: %w", err)
+	}
+
+	// Apply Genesis Block to the UTXO set
+	if err := chain.updateUTXO(genesisBlock); err != nil {
+		return fmt.Errorf("utxo update failed: %w", err)
 	}
 
-	// Set the chain height and head into struct
+	// Add Genesis Block to the height index
+	if err := chain.indexBlock(genesisBlock); err != nil {
+		return err
+	}
+
+	// Set the chain height, head and tip into struct
 	chain.Head, chain.Height = genesisBlock.BlockHash, 1
+	chain.tip = genesisBlock
+	chain.countBlock(genesisBlock)
 
 	// Sync the chain state into the DB
 	if err := chain.syncState(); err != nil {
@@ -150,45 +456,230 @@ func (chain *ChainManager) init() (err error) {
 	return nil
 }
 
-func (chain *ChainManager) Stop() {
-	chain.db.Close()
+// Tip returns the Block at the head of the chain.
+// The Block is cached and is only retrieved from the database if not already loaded.
+func (chain *ChainManager) Tip() (*Block, error) {
+	if chain.tip == nil || !chain.tip.BlockHash.Equal(chain.Head) {
+		tip, err := chain.GetBlock(chain.Head)
+		if err != nil {
+			return nil, err
+		}
+
+		chain.tip = tip
+	}
+
+	return chain.tip, nil
 }
 
-// syncState updates the chain head and height values into the DB at keys
-// specified by the ChainHeadKey and ChainHeightKey respectively.
-func (chain *ChainManager) syncState() error {
-	// Sync chain head into the DB
-	if err := chain.db.SetEntry(ChainHeadKey, chain.Head.Bytes()); err != nil {
-		return fmt.Errorf("error syncing chain head: %w", err)
+// GetBlock returns the Block with the given hash from the database.
+// Returns an error if the Block is not found or cannot be deserialized, or if
+// the Block is stored under a different hash with Options.VerifyBlockHashes.
+func (chain *ChainManager) GetBlock(hash common.Hash) (*Block, error) {
+	return readBlock(chain.db, hash, chain.opts.VerifyBlockHashes)
+}
+
+// ErrBlockHashMismatch is returned for a Block whose header hash differs from the hash it is stored under
+var ErrBlockHashMismatch = errors.New("block hash does not match stored key")
+
+// readBlock returns the Block with the given hash from a database.
+// If verify is set, the hash of the Block header is recomputed and checked against the given hash.
+func readBlock(database *db.Database, hash common.Hash, verify bool) (*Block, error) {
+	// Find the Block data for the given hash
+	data, err := database.GetEntry(hash.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot find block '%v': %w", hash, err)
+	}
+
+	// Create a new Block and deserialize the block data into it
+	block := new(Block)
+	if err := block.Deserialize(data); err != nil {
+		return nil, fmt.Errorf("block deserialize failed: %w", err)
+	}
+
+	if verify {
+		if err := verifyHeaderHash(&block.BlockHeader, hash); err != nil {
+			return nil, err
+		}
+	}
+
+	return block, nil
+}
+
+// verifyHeaderHash checks that the recomputed hash of a BlockHeader is the hash it was read with
+func verifyHeaderHash(header *BlockHeader, hash common.Hash) error {
+	if computed := header.Hash(); !computed.Equal(hash) {
+		return fmt.Errorf("%w: block '%v' has header hash '%v'", ErrBlockHashMismatch, hash, computed)
+	}
+
+	return nil
+}
+
+// HasBlock returns whether the Block with the given hash is on the chain.
+// Blocks that remain in the database after being disconnected by a reorganization are not on the chain.
+func (chain *ChainManager) HasBlock(hash common.Hash) (bool, error) {
+	exists, err := chain.db.HasEntry(hash.Bytes())
+	if err != nil || !exists {
+		return false, err
+	}
+
+	// Check the stored Block against the height index
+	block, err := chain.GetBlock(hash)
+	if err != nil {
+		return false, err
+	}
+
+	if block.BlockHeight < 0 || block.BlockHeight >= chain.Height {
+		return false, nil
+	}
+
+	indexed, err := chain.BlockHashAtHeight(block.BlockHeight)
+	if err != nil {
+		return false, err
+	}
+
+	return indexed.Equal(hash), nil
+}
+
+// GetHeader returns the BlockHeader of the Block with the given hash from the database, without its body.
+// Falls back to the full Block if its header is not stored separately.
+func (chain *ChainManager) GetHeader(hash common.Hash) (*BlockHeader, error) {
+	return getHeader(chain.db, hash, chain.opts.VerifyBlockHashes)
+}
+
+// getHeader returns the BlockHeader of the Block with the given hash from a database.
+// If verify is set, the hash of the BlockHeader is recomputed and checked against the given hash.
+func getHeader(database *db.Database, hash common.Hash, verify bool) (*BlockHeader, error) {
+	exists, err := database.HasEntry(headerKey(hash))
+	if err != nil {
+		return nil, err
+	}
+
+	// Blocks stored before headers were stored separately only have the full Block
+	if !exists {
+		block, err := readBlock(database, hash, verify)
+		if err != nil {
+			return nil, err
+		}
+
+		return &block.BlockHeader, nil
+	}
+
+	data, err := database.GetEntry(headerKey(hash))
+	if err != nil {
+		return nil, fmt.Errorf("cannot find header '%v': %w", hash, err)
+	}
+
+	header := new(BlockHeader)
+	if err := header.Deserialize(data); err != nil {
+		return nil, fmt.Errorf("header deserialize failed: %w", err)
+	}
+
+	if verify {
+		if err := verifyHeaderHash(header, hash); err != nil {
+			return nil, err
+		}
 	}
 
-	// Serialize the chain height
-	height, err := common.GobEncode(chain.Height)
+	return header, nil
+}
+
+// storeBlock adds a Block and its BlockHeader to the database
+func (chain *ChainManager) storeBlock(block *Block) error {
+	// Serialize the Block
+	blockData, err := block.Serialize()
 	if err != nil {
-		return fmt.Errorf("error serializing chain height: %w", err)
+		return fmt.Errorf("block serialize failed: %w", err)
+	}
+
+	if err := chain.db.SetEntry(block.BlockHash.Bytes(), blockData); err != nil {
+		return fmt.Errorf("block store to db failed: %w", err)
+	}
+
+	// Serialize the BlockHeader
+	headerData, err := block.BlockHeader.Serialize()
+	if err != nil {
+		return fmt.Errorf("header serialize failed: %w", err)
+	}
+
+	if err := chain.db.SetEntry(headerKey(block.BlockHash), headerData); err != nil {
+		return fmt.Errorf("header store to db failed: %w", err)
+	}
+
+	return nil
+}
+
+// FindTransaction returns the Transaction with the given ID, located with the transaction index.
+// Returns an error if no such Transaction exists on the chain.
+func (chain *ChainManager) FindTransaction(id common.Hash) (*Transaction, error) {
+	block, err := chain.FindTransactionBlock(id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, txn := range block.BlockTxns {
+		if txn.ID.Equal(id) {
+			return txn, nil
+		}
+	}
+
+	return nil, fmt.Errorf("transaction '%v' not found in block '%v'", id, block.BlockHash)
+}
+
+func (chain *ChainManager) Stop() {
+	if chain.wal != nil {
+		if err := chain.Flush(); err != nil {
+			chain.opts.Logger.Infof("Chain flush failed: %v", err)
+		}
+
+		chain.wal.file.Close()
+	}
+
+	chain.db.Close()
+}
+
+// syncState updates the chain state into the DB as a single ChainState
+// at the key specified by ChainStateKey, along with the chain statistics.
+func (chain *ChainManager) syncState() error {
+	// Sync the chain head, height and work into the DB
+	if err := chain.writeState(); err != nil {
+		return fmt.Errorf("error syncing chain state: %w", err)
 	}
 
-	// Sync the encoded height into the DB
-	if err := chain.db.SetEntry(ChainHeightKey, height); err != nil {
-		return fmt.Errorf("error syncing chain height: %w", err)
+	// Sync the chain statistics into the DB
+	if err := chain.syncStats(); err != nil {
+		return fmt.Errorf("error syncing chain statistics: %w", err)
 	}
 
 	return nil
 }
 
+// FindUnspentTransactions returns the Transactions with unspent outputs owned by the Address,
+// by scanning every Block of the chain from the chain head to the Genesis Block.
 func (chain *ChainManager) FindUnspentTransactions(address common.Address) (Transactions, error) {
+	return chain.FindUnspentTransactionsFrom(address, 0)
+}
+
+// FindUnspentTransactionsFrom is FindUnspentTransactions with a floor on the scan, which stops at the first
+// Block below the minimum height. The result is partial with a floor: it only holds the Transactions of the
+// Blocks at or above the minimum height, with the unspent outputs created by them.
+func (chain *ChainManager) FindUnspentTransactionsFrom(address common.Address, minHeight int64) (Transactions, error) {
 	var unspentTxs Transactions
 
 	spentTXOs := make(map[common.Hash][]int)
 
 	iter := chain.NewIterator()
 
-	for {
+	for !iter.Done() {
 		block, err := iter.Next()
 		if err != nil {
 			return nil, err
 		}
 
+		// Stop the scan below the floor
+		if block.BlockHeight < minHeight {
+			break
+		}
+
 		for _, tx := range block.BlockTxns {
 			txID := tx.ID
 
@@ -214,54 +705,29 @@ func (chain *ChainManager) FindUnspentTransactions(address common.Address) (Tran
 				}
 			}
 		}
-
-		if len(block.Priori) == 0 {
-			break
-		}
 	}
 	return unspentTxs, nil
 }
 
+// FindUTXO returns the unspent outputs owned by the Address from the UTXO set
 func (chain *ChainManager) FindUTXO(address common.Address) ([]TxOutput, error) {
-	var UTXOs []TxOutput
-	unspentTransactions, err := chain.FindUnspentTransactions(address)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, tx := range unspentTransactions {
-		for _, out := range tx.Outputs {
-			if out.CanBeUnlocked(address) {
-				UTXOs = append(UTXOs, out)
-			}
-		}
-	}
-	return UTXOs, nil
+	return chain.FindUTXOFrom(address, 0)
 }
 
-func (chain *ChainManager) FindSpendableOutputs(address common.Address, amount int) (int, map[common.Hash][]int, error) {
-	unspentOuts := make(map[common.Hash][]int)
-	unspentTxs, err := chain.FindUnspentTransactions(address)
-	if err != nil {
-		return -1, nil, err
-	}
-	accumulated := 0
-
-Work:
-	for _, tx := range unspentTxs {
-		txID := tx.ID
-
-		for outIdx, out := range tx.Outputs {
-			if out.CanBeUnlocked(address) && accumulated < amount {
-				accumulated += out.Value
-				unspentOuts[txID] = append(unspentOuts[txID], outIdx)
-
-				if accumulated >= amount {
-					break Work
-				}
-			}
+// FindUTXOFrom returns the unspent outputs owned by the Address from the UTXO set that were created
+// by Blocks at or above the minimum height. The outputs are partial with a floor, so their total is
+// only the balance of the Address if it has no unspent outputs below the floor.
+func (chain *ChainManager) FindUTXOFrom(address common.Address, minHeight int64) ([]TxOutput, error) {
+	var UTXOs []TxOutput
+	if err := chain.forEachUTXOEntry(address, func(_ Outpoint, entry *UTXOEntry) bool {
+		if entry.Height >= minHeight {
+			UTXOs = append(UTXOs, entry.Output)
 		}
+
+		return true
+	}); err != nil {
+		return nil, err
 	}
 
-	return accumulated, unspentOuts, nil
+	return UTXOs, nil
 }
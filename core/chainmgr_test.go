@@ -0,0 +1,256 @@
+package core
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestConcurrentMineBlock checks that concurrent writers each append their Block on top of the
+// Block of the previous writer, while concurrent readers always see a consistent UTXO set
+func TestConcurrentMineBlock(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+
+	const writers, blocks = 4, 5
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*blocks)
+
+	for writer := 0; writer < writers; writer++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for idx := 0; idx < blocks; idx++ {
+				if err := chain.MineBlock(nil, ""); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+
+	// Every coinbase pays the block reward, so the total balance is always a multiple of it
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for idx := 0; idx < 20; idx++ {
+			balances, err := chain.AllBalances()
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			total := 0
+			for _, balance := range balances {
+				total += balance
+			}
+
+			if total%BlockReward != 0 {
+				t.Errorf("expected the total balance to be a multiple of the block reward, got %v", total)
+			}
+		}
+	}()
+
+	wg.Wait()
+	<-done
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("concurrent operation failed: %v", err)
+	}
+
+	if want := int64(1 + writers*blocks); chain.Height != want {
+		t.Fatalf("expected chain height %v, got %v", want, chain.Height)
+	}
+
+	// Each Block must extend the Block below it
+	for height := int64(1); height < chain.Height; height++ {
+		block, err := chain.GetBlockByHeight(height)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		parent, err := chain.BlockHashAtHeight(height - 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !block.Priori.Equal(parent) {
+			t.Fatalf("expected the block at height %v to extend the block below it", height)
+		}
+	}
+
+	audit, err := chain.AuditUTXO()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !audit.Consistent() {
+		t.Fatalf("expected the utxo set to be consistent, got %v discrepancies", len(audit.Discrepancies))
+	}
+}
+
+// TestTip checks that Tip returns the most recently added Block, including after the chain is reopened
+func TestTip(t *testing.T) {
+	chain := generateTestChain(t, testChainOptions(2))
+
+	for idx := 0; idx < 2; idx++ {
+		if err := chain.MineBlock(nil, ""); err != nil {
+			t.Fatalf("failed to mine block: %v", err)
+		}
+
+		tip, err := chain.Tip()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		head, err := chain.GetBlock(chain.Head)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !tip.BlockHash.Equal(head.BlockHash) || tip.BlockHeight != chain.Height-1 {
+			t.Fatalf("expected the tip to be the block at the chain head, got '%v' at height %v", tip.BlockHash, tip.BlockHeight)
+		}
+	}
+
+	head := chain.Head
+	chain.Stop()
+
+	chain = openTestChain(t, DefaultOptions())
+
+	tip, err := chain.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !tip.BlockHash.Equal(head) {
+		t.Fatalf("expected the tip of the reopened chain to be '%v', got '%v'", head, tip.BlockHash)
+	}
+}
+
+// TestSimulateBlock checks that SimulateBlock has no side effects and returns the Block that AddBlock appends
+// for the same Transactions, which has the same hash once it is mined at the same timestamp
+func TestSimulateBlock(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	miner := common.MinerAddress()
+
+	spend := signedTransaction(t, miner, []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: 90, PubKey: "alice"})
+	coinbase, err := chain.NewCoinbase(Transactions{spend}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txns := Transactions{coinbase, spend}
+	head, height := chain.Head, chain.Height
+
+	simulated, err := chain.SimulateBlock(txns)
+	if err != nil {
+		t.Fatalf("failed to simulate block: %v", err)
+	}
+
+	if !chain.Head.Equal(head) || chain.Height != height {
+		t.Fatalf("expected the chain state to be unchanged by the simulation")
+	}
+
+	if exists, err := chain.HasBlock(simulated.BlockHash); err != nil || exists {
+		t.Fatalf("expected the simulated block not to be stored, got %v", err)
+	}
+
+	if err := chain.AddBlock(txns); err != nil {
+		t.Fatalf("failed to add block: %v", err)
+	}
+
+	added, err := chain.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if simulated.BlockHeight != added.BlockHeight || !simulated.Summary.Equal(added.Summary) || !simulated.Priori.Equal(added.Priori) {
+		t.Fatalf("expected the simulated block to match the added block")
+	}
+
+	// The Blocks only differ by their timestamp and nonce, so they are equal when mined at the same time
+	simulated.Timestamp = added.Timestamp
+	if hash := simulated.BlockHeader.Mint(); !hash.Equal(added.BlockHash) {
+		t.Fatalf("expected the simulated block hash '%v' to match the added block hash '%v'", hash, added.BlockHash)
+	}
+}
+
+// TestChainStats checks that the running chain statistics count the Blocks added to the chain,
+// are restored when the chain is reopened and are reverted when a Block is disconnected
+func TestChainStats(t *testing.T) {
+	chain := generateTestChain(t, testChainOptions(0))
+	miner := common.MinerAddress()
+
+	spend := signedTransaction(t, miner, []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: 90, PubKey: "alice"})
+	if err := chain.MineBlock(Transactions{spend}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	// The fee of the spend is paid to the miner by the coinbase of its Block
+	if chain.TxnCount != 3 || chain.Supply != 2*BlockReward+10 {
+		t.Fatalf("expected 3 transactions and a supply of %v, got %v and %v", 2*BlockReward+10, chain.TxnCount, chain.Supply)
+	}
+
+	chain.Stop()
+	chain = openTestChain(t, DefaultOptions())
+
+	if chain.TxnCount != 3 || chain.Supply != 2*BlockReward+10 {
+		t.Fatalf("expected the reopened chain to restore its stats, got %v and %v", chain.TxnCount, chain.Supply)
+	}
+
+	if _, err := chain.disconnectTip(); err != nil {
+		t.Fatalf("failed to disconnect block: %v", err)
+	}
+
+	if chain.TxnCount != 1 || chain.Supply != BlockReward {
+		t.Fatalf("expected the disconnected block to be uncounted, got %v and %v", chain.TxnCount, chain.Supply)
+	}
+}
+
+// TestAcceptBlockKnown checks that accepting a Block that is already on the chain is a no-op
+// that reports ErrKnownBlock, without changing the chain state or publishing an event
+func TestAcceptBlockKnown(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+
+	bits, err := chain.nextBits()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block := NewBlock(Transactions{coinbaseOf(chain, TxOutput{Value: BlockReward, PubKey: common.MinerAddress()})}, chain.Head, chain.Height, bits)
+	if err := chain.AcceptBlock(block); err != nil {
+		t.Fatalf("failed to accept block: %v", err)
+	}
+
+	sub := chain.Subscribe(1)
+	defer sub.Unsubscribe()
+
+	head, height, count := chain.Head, chain.Height, chain.TxnCount
+
+	genesis, err := chain.GetBlockByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, known := range []*Block{block, genesis} {
+		if err := chain.AcceptBlock(known); !errors.Is(err, ErrKnownBlock) {
+			t.Fatalf("expected block '%v' to be known, got %v", known.BlockHash, err)
+		}
+	}
+
+	if !chain.Head.Equal(head) || chain.Height != height || chain.TxnCount != count {
+		t.Fatalf("expected the chain state to be unchanged by known blocks")
+	}
+
+	select {
+	case event := <-sub.C:
+		t.Fatalf("expected no event for known blocks, got %+v", event)
+	default:
+	}
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/anee769/essensio/common"
+)
+
+// CompactBlock is a compact representation of a Block for propagation.
+// It contains the header of the Block and the IDs of its Transactions,
+// so that peers can reconstruct the Block from their own Mempool.
+type CompactBlock struct {
+	BlockHeader
+
+	// Number of blocks preceding the block
+	BlockHeight int64
+	// Hash of the block header
+	BlockHash common.Hash
+	// IDs of the Transactions of the block in order
+	TxnIDs []common.Hash
+	// Transactions that are sent in full because peers cannot have them, such as the coinbase
+	Prefilled Transactions
+}
+
+// Compact returns the CompactBlock representation of the Block.
+// Coinbase Transactions are prefilled as they never exist in a Mempool.
+func (block *Block) Compact() *CompactBlock {
+	compact := &CompactBlock{
+		BlockHeader: block.BlockHeader,
+		BlockHeight: block.BlockHeight,
+		BlockHash:   block.BlockHash,
+		TxnIDs:      make([]common.Hash, 0, block.TxnCount()),
+	}
+
+	for _, txn := range block.BlockTxns {
+		compact.TxnIDs = append(compact.TxnIDs, txn.ID)
+
+		if txn.IsCoinbase() {
+			compact.Prefilled = append(compact.Prefilled, txn)
+		}
+	}
+
+	return compact
+}
+
+// ReconstructBlock reconstructs a Block from its CompactBlock representation. Transactions are
+// resolved from the prefilled Transactions, the given fetched Transactions and the Mempool, in that order.
+// If any Transactions cannot be resolved, the Block is nil and their IDs are returned to be fetched from a peer.
+// Returns an error if the reconstructed Block does not match the header of the CompactBlock.
+func ReconstructBlock(compact *CompactBlock, mempool *Mempool, fetched Transactions) (*Block, []common.Hash, error) {
+	// Collect the Transactions that are available without the Mempool
+	available := make(map[common.Hash]*Transaction, len(compact.Prefilled)+len(fetched))
+	for _, txn := range append(append(Transactions{}, compact.Prefilled...), fetched...) {
+		available[txn.ID] = txn
+	}
+
+	txns := make(Transactions, 0, len(compact.TxnIDs))
+	var missing []common.Hash
+
+	for _, id := range compact.TxnIDs {
+		txn, ok := available[id]
+		if !ok {
+			txn, ok = mempool.Get(id)
+		}
+
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+
+		txns = append(txns, txn)
+	}
+
+	if len(missing) > 0 {
+		return nil, missing, nil
+	}
+
+	block := &Block{
+		BlockHeader: compact.BlockHeader,
+		BlockHeight: compact.BlockHeight,
+		BlockTxns:   txns,
+		BlockHash:   compact.BlockHash,
+	}
+
+	if err := block.CheckIntegrity(); err != nil {
+		return nil, nil, fmt.Errorf("reconstructed block is invalid: %w", err)
+	}
+
+	return block, nil, nil
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestReconstructBlock checks that a Block is reconstructed from its CompactBlock when all of its Transactions
+// are in the Mempool, and that the missing Transactions are requested and then resolved once they are fetched
+func TestReconstructBlock(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	parent, child, grandchild := submitFamily(t, chain)
+
+	bits, err := chain.nextBits()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	coinbase := coinbaseOf(chain, TxOutput{Value: BlockReward + 30, PubKey: common.MinerAddress()})
+	block := NewBlock(Transactions{coinbase, parent, child, grandchild}, chain.Head, chain.Height, bits)
+
+	compact := block.Compact()
+	if len(compact.TxnIDs) != 4 || len(compact.Prefilled) != 1 || !compact.Prefilled[0].ID.Equal(coinbase.ID) {
+		t.Fatalf("expected a compact block of 4 transactions with a prefilled coinbase")
+	}
+
+	// All Transactions are available from the Mempool of the chain
+	reconstructed, missing, err := ReconstructBlock(compact, chain.Mempool(), nil)
+	if err != nil || len(missing) != 0 {
+		t.Fatalf("failed to reconstruct block: %v with %v missing", err, len(missing))
+	}
+
+	if !reconstructed.BlockHash.Equal(block.BlockHash) || reconstructed.TxnCount() != block.TxnCount() {
+		t.Fatalf("expected the reconstructed block to match the block")
+	}
+
+	// The child and the grandchild are missing from a Mempool with only the parent
+	pool := NewMempool()
+	if err := pool.Add(parent, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	reconstructed, missing, err = ReconstructBlock(compact, pool, nil)
+	if err != nil || reconstructed != nil {
+		t.Fatalf("expected no block with missing transactions, got %v", err)
+	}
+
+	if !equalHashes(missing, []common.Hash{child.ID, grandchild.ID}) {
+		t.Fatalf("expected the child and the grandchild to be missing, got %v", missing)
+	}
+
+	reconstructed, missing, err = ReconstructBlock(compact, pool, Transactions{grandchild, child})
+	if err != nil || len(missing) != 0 || !reconstructed.BlockHash.Equal(block.BlockHash) {
+		t.Fatalf("expected the block to be reconstructed with the fetched transactions, got %v with %v missing", err, len(missing))
+	}
+
+	for idx, txn := range reconstructed.BlockTxns {
+		if !txn.ID.Equal(compact.TxnIDs[idx]) {
+			t.Fatalf("expected the reconstructed transactions in the order of the compact block")
+		}
+	}
+
+	// Transactions in a different order do not match the summary of the header
+	compact.TxnIDs[2], compact.TxnIDs[3] = compact.TxnIDs[3], compact.TxnIDs[2]
+	if _, _, err := ReconstructBlock(compact, chain.Mempool(), nil); err == nil || !strings.Contains(err.Error(), "reconstructed block is invalid") {
+		t.Fatalf("expected a reordered block to be invalid, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/anee769/essensio/common"
+)
+
+// ConfirmationTrackerBuffer is the buffer size of the Subscription of a ConfirmationTracker
+const ConfirmationTrackerBuffer = 64
+
+// ConfirmationTracker tracks the confirmations of a set of Transactions as the chain changes, such as
+// deposits that are credited once they are stable. It watches the ChainEvents of the chain, so the
+// confirmations of a Transaction drop to 0 if a reorganization removes its Block, and rise again once
+// it is mined on the new chain. It is safe for concurrent use, and must be stopped with Stop.
+type ConfirmationTracker struct {
+	chain *ChainManager
+	sub   *Subscription
+	done  chan struct{}
+
+	mutex sync.RWMutex
+	// Represents the height of the chain head as of the last processed ChainEvent
+	head int64
+	// Represents the height of the Block of each tracked Transaction, or -1 if it is not on the chain
+	tracked map[common.Hash]int64
+}
+
+// NewConfirmationTracker returns a ConfirmationTracker for the chain that watches its ChainEvents
+func NewConfirmationTracker(chain *ChainManager) *ConfirmationTracker {
+	tracker := &ConfirmationTracker{
+		chain:   chain,
+		sub:     chain.Subscribe(ConfirmationTrackerBuffer),
+		done:    make(chan struct{}),
+		head:    chain.Height - 1,
+		tracked: make(map[common.Hash]int64),
+	}
+
+	go tracker.run()
+	return tracker
+}
+
+// run processes the ChainEvents of the Subscription of the ConfirmationTracker until it is stopped
+func (tracker *ConfirmationTracker) run() {
+	defer close(tracker.done)
+
+	for event := range tracker.sub.C {
+		if len(event.Added) == 0 {
+			continue
+		}
+
+		head, err := tracker.chain.GetBlock(event.Added[len(event.Added)-1])
+		if err != nil {
+			tracker.chain.opts.Logger.Infof("Confirmation tracker head retrieve failed: %v.", err)
+			continue
+		}
+
+		tracker.mutex.Lock()
+		tracker.head = head.BlockHeight
+		tracker.refresh()
+		tracker.mutex.Unlock()
+	}
+}
+
+// refresh resolves the Block height of every tracked Transaction with the transaction index.
+// Every Transaction is resolved again, so that the tracker remains accurate if an event is dropped.
+// Must be called with the lock held.
+func (tracker *ConfirmationTracker) refresh() {
+	for id := range tracker.tracked {
+		height, err := tracker.resolve(id)
+		if err != nil {
+			tracker.chain.opts.Logger.Infof("Confirmation tracker lookup of '%v' failed: %v.", id, err)
+			continue
+		}
+
+		tracker.tracked[id] = height
+	}
+}
+
+// resolve returns the height of the Block of the Transaction with the given ID on the chain, or -1 if it is not
+// on the chain. The transaction index only holds Transactions on the chain, as disconnected Blocks are unindexed.
+func (tracker *ConfirmationTracker) resolve(id common.Hash) (int64, error) {
+	exists, err := tracker.chain.HasTransaction(id)
+	if err != nil || !exists {
+		return -1, err
+	}
+
+	block, err := tracker.chain.FindTransactionBlock(id)
+	if err != nil {
+		return -1, err
+	}
+
+	return block.BlockHeight, nil
+}
+
+// Track starts tracking the confirmations of the Transaction with the given ID, which need not be on the chain yet
+func (tracker *ConfirmationTracker) Track(id common.Hash) error {
+	height, err := tracker.resolve(id)
+	if err != nil {
+		return fmt.Errorf("transaction lookup failed: %w", err)
+	}
+
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	tracker.tracked[id] = height
+	return nil
+}
+
+// Untrack stops tracking the confirmations of the Transaction with the given ID
+func (tracker *ConfirmationTracker) Untrack(id common.Hash) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	delete(tracker.tracked, id)
+}
+
+// Confirmations returns the number of confirmations of a tracked Transaction, and whether it is tracked.
+// A Transaction in the chain head has 1 confirmation, and a Transaction that is not on the chain has 0.
+func (tracker *ConfirmationTracker) Confirmations(id common.Hash) (int64, bool) {
+	tracker.mutex.RLock()
+	defer tracker.mutex.RUnlock()
+
+	height, tracked := tracker.tracked[id]
+	if !tracked || height < 0 || height > tracker.head {
+		return 0, tracked
+	}
+
+	return tracker.head - height + 1, true
+}
+
+// Stop stops watching the chain and waits for the ConfirmationTracker to finish processing events
+func (tracker *ConfirmationTracker) Stop() {
+	tracker.sub.Unsubscribe()
+	<-tracker.done
+}
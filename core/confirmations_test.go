@@ -0,0 +1,92 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anee769/essensio/common"
+)
+
+// waitConfirmations waits for the ConfirmationTracker to report the given number of confirmations
+// for a tracked Transaction, which it updates asynchronously from the ChainEvents of the chain
+func waitConfirmations(t *testing.T, tracker *ConfirmationTracker, id common.Hash, want int64) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		confirmations, tracked := tracker.Confirmations(id)
+		if !tracked {
+			t.Fatalf("expected transaction '%v' to be tracked", id)
+		}
+
+		if confirmations == want {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %v confirmations of transaction '%v', got %v", want, id, confirmations)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestConfirmationTracker checks that the confirmations of a tracked Transaction rise as Blocks are mined on top of it,
+// drop to 0 once a reorganization removes its Block and rise again once it is mined on the new chain, and that untracked Transactions are not reported
+func TestConfirmationTracker(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+
+	tracker := NewConfirmationTracker(chain)
+	defer tracker.Stop()
+
+	genesis, err := chain.GetBlockByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The Transaction is tracked before it is mined
+	spend := signedTransaction(t, common.MinerAddress(), []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: 90, PubKey: "alice"})
+	if err := tracker.Track(spend.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	waitConfirmations(t, tracker, spend.ID, 0)
+
+	if err := chain.MineBlock(Transactions{spend}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	waitConfirmations(t, tracker, spend.ID, 1)
+
+	for idx := 0; idx < 2; idx++ {
+		if err := chain.MineBlock(nil, ""); err != nil {
+			t.Fatalf("failed to mine block: %v", err)
+		}
+	}
+
+	waitConfirmations(t, tracker, spend.ID, 3)
+
+	// A longer branch from the Genesis Block removes the Block of the Transaction
+	branch := []*Block{branchBlock(t, chain, genesis, "branch")}
+	for len(branch) < 4 {
+		branch = append(branch, branchBlock(t, chain, branch[len(branch)-1], "branch"))
+	}
+
+	if err := chain.Reorganize(branch); err != nil {
+		t.Fatalf("failed to reorganize chain: %v", err)
+	}
+
+	waitConfirmations(t, tracker, spend.ID, 0)
+
+	// The Transaction is returned to the Mempool by the reorganization and mined on the new chain
+	if _, err := chain.MinePending(""); err != nil {
+		t.Fatalf("failed to mine pending transactions: %v", err)
+	}
+
+	waitConfirmations(t, tracker, spend.ID, 1)
+
+	tracker.Untrack(spend.ID)
+	if _, tracked := tracker.Confirmations(spend.ID); tracked {
+		t.Fatalf("expected the untracked transaction not to be reported")
+	}
+}
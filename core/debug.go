@@ -0,0 +1,131 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/anee769/essensio/common"
+)
+
+// DebugPrefixes are the key prefixes of the database entries counted by DebugState
+var DebugPrefixes = [][]byte{
+	HeaderPrefix, HeightIndexPrefix, TxIndexPrefix, SpentIndexPrefix,
+	UTXOPrefix, UndoPrefix, SnapshotPrefix, LabelPrefix,
+}
+
+// DebugState represents the raw chain state entries of the database, decoded for diagnostics.
+// It reflects what is stored on disk, which may differ from the state held in memory by the chain.
+type DebugState struct {
+	// Head, Height and Work are the ChainState stored at ChainStateKey
+	Head   common.Hash
+	Height int64
+	Work   *big.Int
+
+	// Legacy is whether the legacy ChainHeadKey and ChainHeightKey entries exist,
+	// in which case LegacyHead and LegacyHeight are their decoded values
+	Legacy       bool
+	LegacyHead   common.Hash
+	LegacyHeight int64
+
+	// TxnCount and Supply are the chain statistics stored at ChainTxnCountKey and ChainSupplyKey
+	TxnCount int64
+	Supply   int
+
+	// Blocks is the number of Blocks stored in the database, including those not on the chain
+	Blocks int
+	// Entries is the number of database entries for each of the DebugPrefixes
+	Entries map[string]int
+}
+
+// DebugState reads and decodes the raw chain state entries of the database, and counts the
+// entries of each of the DebugPrefixes. Entries that do not exist are reported as zero values.
+// Returns an error if an entry exists but cannot be decoded.
+func (chain *ChainManager) DebugState() (*DebugState, error) {
+	state := &DebugState{Work: new(big.Int), Entries: make(map[string]int, len(DebugPrefixes))}
+
+	// Decode the ChainState
+	if data, exists, err := chain.debugEntry(ChainStateKey); err != nil {
+		return nil, err
+	} else if exists {
+		object, err := common.GobDecode(data, new(ChainState))
+		if err != nil {
+			return nil, fmt.Errorf("chain state deserialize failed: %w", err)
+		}
+
+		stored := object.(*ChainState)
+		state.Head, state.Height = stored.Head, stored.Height
+		if stored.Work != nil {
+			state.Work = stored.Work
+		}
+	}
+
+	// Decode the legacy chain head and height
+	if data, exists, err := chain.debugEntry(ChainHeadKey); err != nil {
+		return nil, err
+	} else if exists {
+		state.Legacy, state.LegacyHead = true, common.BytesToHash(data)
+	}
+
+	if data, exists, err := chain.debugEntry(ChainHeightKey); err != nil {
+		return nil, err
+	} else if exists {
+		height, err := common.GobDecode(data, new(int64))
+		if err != nil {
+			return nil, fmt.Errorf("error deserializing chain height: %w", err)
+		}
+
+		state.Legacy, state.LegacyHeight = true, *height.(*int64)
+	}
+
+	// Decode the chain statistics
+	if data, exists, err := chain.debugEntry(ChainTxnCountKey); err != nil {
+		return nil, err
+	} else if exists {
+		count, err := common.GobDecode(data, new(int64))
+		if err != nil {
+			return nil, fmt.Errorf("error deserializing chain transaction count: %w", err)
+		}
+
+		state.TxnCount = *count.(*int64)
+	}
+
+	if data, exists, err := chain.debugEntry(ChainSupplyKey); err != nil {
+		return nil, err
+	} else if exists {
+		supply, err := common.GobDecode(data, new(int))
+		if err != nil {
+			return nil, fmt.Errorf("error deserializing chain supply: %w", err)
+		}
+
+		state.Supply = *supply.(*int)
+	}
+
+	// Count the entries of each prefix
+	for _, prefix := range DebugPrefixes {
+		count, err := chain.db.CountPrefix(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("entry count for prefix '%s' failed: %w", prefix, err)
+		}
+
+		state.Entries[string(prefix)] = count
+	}
+
+	// Every stored Block has a stored BlockHeader
+	state.Blocks = state.Entries[string(HeaderPrefix)]
+	return state, nil
+}
+
+// debugEntry returns the value at a key of the database and whether it exists
+func (chain *ChainManager) debugEntry(key []byte) ([]byte, bool, error) {
+	exists, err := chain.db.HasEntry(key)
+	if err != nil || !exists {
+		return nil, false, err
+	}
+
+	data, err := chain.db.GetEntry(key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
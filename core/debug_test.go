@@ -0,0 +1,48 @@
+package core
+
+import (
+	"testing"
+)
+
+// TestDebugState checks that DebugState reports the stored chain state and entry counts of the chain
+// after Blocks are added, and the legacy chain head once it is stored
+func TestDebugState(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(3))
+
+	state, err := chain.DebugState()
+	if err != nil {
+		t.Fatalf("failed to read debug state: %v", err)
+	}
+
+	if !state.Head.Equal(chain.Head) || state.Height != chain.Height || state.Work.Cmp(chain.Work) != 0 {
+		t.Fatalf("expected the stored state of the chain, got head '%v' at height %v", state.Head, state.Height)
+	}
+
+	if state.TxnCount != chain.TxnCount || state.Supply != chain.Supply {
+		t.Fatalf("expected the stored stats of the chain, got %v and %v", state.TxnCount, state.Supply)
+	}
+
+	if state.Blocks != int(chain.Height) || state.Entries[string(HeightIndexPrefix)] != int(chain.Height) {
+		t.Fatalf("expected %v blocks and height index entries, got %v and %v", chain.Height, state.Blocks, state.Entries[string(HeightIndexPrefix)])
+	}
+
+	if len(state.Entries) != len(DebugPrefixes) {
+		t.Fatalf("expected an entry count for each of the %v prefixes, got %v", len(DebugPrefixes), len(state.Entries))
+	}
+
+	if state.Legacy {
+		t.Fatalf("expected no legacy chain state entries")
+	}
+
+	if err := chain.db.SetEntry(ChainHeadKey, chain.Head.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	if state, err = chain.DebugState(); err != nil {
+		t.Fatalf("failed to read debug state: %v", err)
+	}
+
+	if !state.Legacy || !state.LegacyHead.Equal(chain.Head) {
+		t.Fatalf("expected the legacy chain head '%v', got '%v'", chain.Head, state.LegacyHead)
+	}
+}
@@ -0,0 +1,34 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/anee769/essensio/common"
+)
+
+// BlockDeltas returns the net change in balance that a Block causes for each Address.
+// The change of an Address is the value of the outputs it receives minus the value of
+// the outputs it spends, with the spent values resolved from the referenced outputs on the chain.
+// The deltas of all Addresses sum to the value issued by the coinbase Transaction of the Block.
+func BlockDeltas(block *Block, chain *ChainManager) (map[common.Address]int, error) {
+	deltas := make(map[common.Address]int)
+
+	for _, txn := range block.BlockTxns {
+		if !txn.IsCoinbase() {
+			for idx, input := range txn.Inputs {
+				output, err := chain.FindOutput(Outpoint{input.ID, input.Out})
+				if err != nil {
+					return nil, fmt.Errorf("transaction '%v': input %v: %w", txn.ID, idx, err)
+				}
+
+				deltas[output.PubKey] -= output.Value
+			}
+		}
+
+		for _, output := range txn.Outputs {
+			deltas[output.PubKey] += output.Value
+		}
+	}
+
+	return deltas, nil
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestBlockDeltasIssuance checks that the deltas of every Block of the chain sum to the value
+// issued by its coinbase Transaction
+func TestBlockDeltasIssuance(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(10))
+
+	for height := int64(0); height < chain.Height; height++ {
+		block, err := chain.GetBlockByHeight(height)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		deltas, err := BlockDeltas(block, chain)
+		if err != nil {
+			t.Fatalf("failed to compute deltas at height %v: %v", height, err)
+		}
+
+		sum, issued := 0, 0
+		for _, delta := range deltas {
+			sum += delta
+		}
+
+		for _, output := range block.BlockTxns[0].Outputs {
+			issued += output.Value
+		}
+
+		if sum != issued {
+			t.Fatalf("expected the deltas at height %v to sum to the issued value %v, got %v", height, issued, sum)
+		}
+	}
+
+}
+
+// TestBlockDeltas checks the net balance changes of a Block that spends an output
+func TestBlockDeltas(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+
+	// The miner spends the genesis reward to alice with a fee of 10, which is paid back to the miner by the coinbase
+	miner := common.MinerAddress()
+	spend := signedTransaction(t, miner, []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: 60, PubKey: "alice"}, TxOutput{Value: 30, PubKey: miner})
+	if err := chain.MineBlock(Transactions{spend}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	block, err := chain.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deltas, err := BlockDeltas(block, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(deltas) != 2 || deltas["alice"] != 60 || deltas[miner] != 40 {
+		t.Fatalf("expected deltas of 60 for alice and 40 for the miner, got %v", deltas)
+	}
+}
@@ -0,0 +1,134 @@
+package core
+
+import "math/big"
+
+// DifficultyAdjuster is the algorithm that retargets the Proof of Work difficulty of the chain.
+// The target of each Block must be computed solely from the headers of the preceding Blocks,
+// so that every node running the same algorithm derives the same target for a Block.
+type DifficultyAdjuster interface {
+	// Window returns the number of most recent headers required to compute the next target
+	Window() int
+
+	// NextBits returns the compact PoW target of the Block at the given height.
+	// The headers are those of the preceding Blocks, ordered from oldest to the chain head,
+	// and are fewer than Window near the start of the chain. The target must never be
+	// easier than the given compact limit.
+	NextBits(height int64, headers []*BlockHeader, limit uint32) uint32
+}
+
+// DefaultTargetSpacing is the default target time between Blocks, in seconds
+const DefaultTargetSpacing = 10
+
+// WindowAdjuster is a DifficultyAdjuster that retargets once every Interval Blocks.
+// The target is scaled by the ratio of the actual time taken by the Blocks of the window
+// to the expected time, with the ratio clamped to a factor of 4 in either direction.
+type WindowAdjuster struct {
+	// Interval is the number of Blocks between retargets
+	Interval int64
+	// Spacing is the target time between Blocks, in seconds
+	Spacing int64
+}
+
+// DefaultWindowAdjuster returns the default DifficultyAdjuster of a chain
+func DefaultWindowAdjuster() WindowAdjuster {
+	return WindowAdjuster{Interval: 60, Spacing: DefaultTargetSpacing}
+}
+
+// Window implements the DifficultyAdjuster interface for WindowAdjuster
+func (adjuster WindowAdjuster) Window() int {
+	return int(adjuster.Interval)
+}
+
+// NextBits implements the DifficultyAdjuster interface for WindowAdjuster
+func (adjuster WindowAdjuster) NextBits(height int64, headers []*BlockHeader, limit uint32) uint32 {
+	head := headers[len(headers)-1]
+
+	// The target only changes at the end of each interval
+	if adjuster.Interval <= 0 || height%adjuster.Interval != 0 || len(headers) < 2 {
+		return head.Bits
+	}
+
+	// Clamp the actual timespan of the window to a factor of the expected timespan
+	expected := int64(len(headers)-1) * adjuster.Spacing
+	actual := head.Timestamp - headers[0].Timestamp
+
+	if actual < expected/4 {
+		actual = expected / 4
+	}
+
+	if actual > expected*4 {
+		actual = expected * 4
+	}
+
+	target := head.Target()
+	target.Mul(target, big.NewInt(actual))
+	target.Div(target, big.NewInt(expected))
+
+	return limitTarget(target, limit)
+}
+
+// LWMAAdjuster is a DifficultyAdjuster that retargets on every Block with a linearly weighted
+// moving average of the solve times of the Blocks in the window. Recent solve times have the
+// largest weight, so the target responds quickly to changes in hash rate.
+type LWMAAdjuster struct {
+	// Blocks is the number of solve times in the averaging window
+	Blocks int
+	// Spacing is the target time between Blocks, in seconds
+	Spacing int64
+}
+
+// Window implements the DifficultyAdjuster interface for LWMAAdjuster.
+// A solve time requires the headers of two Blocks, so the window has one more header than Blocks.
+func (adjuster LWMAAdjuster) Window() int {
+	return adjuster.Blocks + 1
+}
+
+// NextBits implements the DifficultyAdjuster interface for LWMAAdjuster
+func (adjuster LWMAAdjuster) NextBits(height int64, headers []*BlockHeader, limit uint32) uint32 {
+	head := headers[len(headers)-1]
+
+	count := int64(len(headers) - 1)
+	if count < 1 || adjuster.Spacing <= 0 {
+		return head.Bits
+	}
+
+	// Accumulate the weighted solve times and the targets of the window
+	var weighted int64
+	sum := new(big.Int)
+
+	for idx := int64(1); idx <= count; idx++ {
+		// Clamp the solve time to limit the effect of manipulated timestamps
+		solvetime := headers[idx].Timestamp - headers[idx-1].Timestamp
+		if solvetime < 1 {
+			solvetime = 1
+		}
+
+		if solvetime > 6*adjuster.Spacing {
+			solvetime = 6 * adjuster.Spacing
+		}
+
+		weighted += solvetime * idx
+		sum.Add(sum, headers[idx].Target())
+	}
+
+	// Scale the average target by the ratio of the weighted solve time to its expected value
+	target := sum.Div(sum, big.NewInt(count))
+	target.Mul(target, big.NewInt(weighted))
+	target.Div(target, big.NewInt(count*(count+1)/2*adjuster.Spacing))
+
+	return limitTarget(target, limit)
+}
+
+// limitTarget returns the compact representation of a target, capped at the given compact limit
+func limitTarget(target *big.Int, limit uint32) uint32 {
+	if maximum := CompactToTarget(limit); target.Cmp(maximum) > 0 {
+		return limit
+	}
+
+	// A target of zero can never be met
+	if target.Sign() <= 0 {
+		target.SetInt64(1)
+	}
+
+	return TargetToCompact(target)
+}
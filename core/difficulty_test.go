@@ -0,0 +1,101 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+)
+
+// simulateAdjuster simulates a chain of Blocks retargeted by the DifficultyAdjuster, starting from a Genesis Block
+// at the initial compact target. The hash rate of the network changes to rate times the initial hash rate at height
+// step, and each Block is solved in the time expected for its target at the hash rate. Returns the headers of the chain.
+func simulateAdjuster(adjuster DifficultyAdjuster, bits, limit uint32, spacing int64, blocks, step int64, rate float64) []*BlockHeader {
+	initial := new(big.Float).SetInt(CompactToTarget(bits))
+	headers := []*BlockHeader{{Timestamp: 0, Bits: bits}}
+
+	for height := int64(1); height < blocks; height++ {
+		window := adjuster.Window()
+		if window > len(headers) {
+			window = len(headers)
+		}
+
+		next := adjuster.NextBits(height, headers[len(headers)-window:], limit)
+
+		// The solve time scales with the difficulty of the target relative to the initial target
+		ratio, _ := new(big.Float).Quo(initial, new(big.Float).SetInt(CompactToTarget(next))).Float64()
+		if height >= step {
+			ratio /= rate
+		}
+
+		solvetime := int64(ratio * float64(spacing))
+		if solvetime < 1 {
+			solvetime = 1
+		}
+
+		headers = append(headers, &BlockHeader{Timestamp: headers[len(headers)-1].Timestamp + solvetime, Bits: next})
+	}
+
+	return headers
+}
+
+// targetRatio returns the ratio of the target of a header to the given compact target
+func targetRatio(header *BlockHeader, bits uint32) float64 {
+	ratio, _ := new(big.Float).Quo(new(big.Float).SetInt(header.Target()), new(big.Float).SetInt(CompactToTarget(bits))).Float64()
+	return ratio
+}
+
+// TestDifficultyAdjusterStep checks that both DifficultyAdjusters keep the target under a constant hash rate
+// and converge to half the target after the hash rate doubles, deterministically for the same headers
+func TestDifficultyAdjusterStep(t *testing.T) {
+	const spacing = 60
+
+	bits, limit := DifficultyToBits(12), DifficultyToBits(4)
+
+	tests := []struct {
+		name      string
+		adjuster  DifficultyAdjuster
+		blocks    int64
+		tolerance float64
+	}{
+		{"window", WindowAdjuster{Interval: 10, Spacing: spacing}, 400, 0.1},
+		{"lwma", LWMAAdjuster{Blocks: 20, Spacing: spacing}, 400, 0.1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The hash rate doubles halfway through the chain
+			headers := simulateAdjuster(test.adjuster, bits, limit, spacing, test.blocks, test.blocks/2, 2)
+
+			if ratio := targetRatio(headers[test.blocks/2-1], bits); ratio < 1-test.tolerance || ratio > 1+test.tolerance {
+				t.Fatalf("expected the target to be steady under a constant hash rate, got a ratio of %v", ratio)
+			}
+
+			if ratio := targetRatio(headers[len(headers)-1], bits); ratio < 0.5-test.tolerance || ratio > 0.5+test.tolerance {
+				t.Fatalf("expected the target to halve after the hash rate doubles, got a ratio of %v", ratio)
+			}
+
+			// Every node derives the same target from the same headers
+			window := headers[len(headers)-test.adjuster.Window():]
+			if first, second := test.adjuster.NextBits(test.blocks, window, limit), test.adjuster.NextBits(test.blocks, window, limit); first != second {
+				t.Fatalf("expected the same bits for the same headers, got %08x and %08x", first, second)
+			}
+		})
+	}
+}
+
+// TestDifficultyAdjusterLimit checks that both DifficultyAdjusters never retarget easier than the limit
+func TestDifficultyAdjusterLimit(t *testing.T) {
+	const spacing = 60
+
+	bits := DifficultyToBits(8)
+
+	for _, adjuster := range []DifficultyAdjuster{WindowAdjuster{Interval: 10, Spacing: spacing}, LWMAAdjuster{Blocks: 20, Spacing: spacing}} {
+		// The hash rate drops to a tenth of the initial hash rate, at the limit of the initial target
+		headers := simulateAdjuster(adjuster, bits, bits, spacing, 100, 1, 0.1)
+
+		for height, header := range headers {
+			if header.Target().Cmp(CompactToTarget(bits)) > 0 {
+				t.Fatalf("%T: expected the target at height %v not to exceed the limit", adjuster, height)
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"sync"
+
+	"github.com/anee769/essensio/common"
+)
+
+// ChainEvent represents a change to the Blocks of the chain.
+// Appending a Block produces an event with only Added set, while a reorganization
+// produces a single event with both the Removed and Added Blocks.
+type ChainEvent struct {
+	// Represents the hashes of the Blocks removed from the chain, starting from the old chain head
+	Removed []common.Hash
+	// Represents the hashes of the Blocks added to the chain, ending at the new chain head
+	Added []common.Hash
+}
+
+// IsReorg returns whether the ChainEvent represents a reorganization of the chain
+func (event ChainEvent) IsReorg() bool {
+	return len(event.Removed) > 0
+}
+
+// Subscription represents a subscription to the ChainEvents of a chain
+type Subscription struct {
+	// C is the channel on which ChainEvents are delivered
+	C <-chan ChainEvent
+
+	events chan ChainEvent
+	chain  *ChainManager
+	once   sync.Once
+}
+
+// Unsubscribe stops the delivery of ChainEvents to the Subscription and closes its channel
+func (sub *Subscription) Unsubscribe() {
+	sub.once.Do(func() {
+		sub.chain.subsMutex.Lock()
+		defer sub.chain.subsMutex.Unlock()
+
+		delete(sub.chain.subs, sub)
+		close(sub.events)
+	})
+}
+
+// Subscribe returns a new Subscription to the ChainEvents of the chain, with a channel of the given buffer size.
+// Events are delivered without blocking the chain, so events are dropped if the buffer of the Subscription is full.
+func (chain *ChainManager) Subscribe(buffer int) *Subscription {
+	events := make(chan ChainEvent, buffer)
+	sub := &Subscription{C: events, events: events, chain: chain}
+
+	chain.subsMutex.Lock()
+	defer chain.subsMutex.Unlock()
+
+	chain.subs[sub] = struct{}{}
+	return sub
+}
+
+// publish delivers a ChainEvent to all the Subscriptions of the chain, after which orphan Transactions
+// waiting on the added Blocks are promoted and the OnFinalized callbacks are invoked for any Blocks that
+// have become final
+func (chain *ChainManager) publish(event ChainEvent) {
+	// Deferred first so that they run after the subscriptions are unlocked
+	defer chain.finalize()
+	defer chain.promoteOrphans(event.Added)
+
+	chain.subsMutex.Lock()
+	defer chain.subsMutex.Unlock()
+
+	for sub := range chain.subs {
+		select {
+		case sub.events <- event:
+		default:
+			chain.opts.Logger.Infof("Dropped chain event for subscription with a full buffer.")
+		}
+	}
+}
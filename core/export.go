@@ -0,0 +1,116 @@
+package core
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxFrameSize is the maximum size of a serialized Block in an export stream
+const MaxFrameSize = MaxBlockDataSize
+
+// Export writes every Block of the chain into w, starting from the Genesis Block.
+// Each Block is serialized and written with a 4 byte big-endian length prefix.
+// Blocks are streamed one at a time, so the chain is never held in memory.
+func (chain *ChainManager) Export(w io.Writer) error {
+	for height := int64(0); height < chain.Height; height++ {
+		block, err := chain.GetBlockByHeight(height)
+		if err != nil {
+			return fmt.Errorf("block at height %v retrieve failed: %w", height, err)
+		}
+
+		if err := writeFrame(w, block); err != nil {
+			return fmt.Errorf("export write failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeFrame serializes a Block and writes it into w with a 4 byte big-endian length prefix
+func writeFrame(w io.Writer, block *Block) error {
+	data, err := block.Serialize()
+	if err != nil {
+		return fmt.Errorf("block serialize failed: %w", err)
+	}
+
+	// Write the length prefix followed by the Block data
+	frame := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	copy(frame[4:], data)
+
+	_, err = w.Write(frame)
+	return err
+}
+
+// readFrame reads a Block written by writeFrame from r.
+// Returns io.EOF if r has no more frames and io.ErrUnexpectedEOF if the frame is truncated.
+func readFrame(r io.Reader) (*Block, error) {
+	// Read the length prefix of the next Block
+	var prefix [4]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(prefix[:])
+	if size > MaxFrameSize {
+		return nil, fmt.Errorf("block size %v exceeds maximum frame size %v", size, MaxFrameSize)
+	}
+
+	// Read and deserialize the Block data
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	block := new(Block)
+	if err := block.Deserialize(data); err != nil {
+		return nil, fmt.Errorf("block deserialize failed: %w", err)
+	}
+
+	return block, nil
+}
+
+// Import reads a stream of Blocks written by Export from r and appends them to the chain.
+// Blocks are read and verified one at a time. Blocks that already exist on the chain are
+// skipped, which requires the stream to have the same Genesis Block as the chain.
+// Returns the number of Blocks that were appended to the chain.
+func (chain *ChainManager) Import(r io.Reader) (int, error) {
+	imported := 0
+
+	for {
+		block, err := readFrame(r)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return imported, nil
+			}
+
+			return imported, fmt.Errorf("import read failed: %w", err)
+		}
+
+		// Skip Blocks that already exist on the chain
+		if block.BlockHeight < chain.Height {
+			existing, err := chain.BlockHashAtHeight(block.BlockHeight)
+			if err != nil {
+				return imported, err
+			}
+
+			if !existing.Equal(block.BlockHash) {
+				return imported, fmt.Errorf("block at height %v does not match the chain", block.BlockHeight)
+			}
+
+			continue
+		}
+
+		if err := chain.AcceptBlock(block); err != nil {
+			return imported, fmt.Errorf("block at height %v: %w", block.BlockHeight, err)
+		}
+
+		imported++
+	}
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+// frameWriter records the Writes of an export stream into a buffer
+type frameWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+// Write implements the io.Writer interface for frameWriter
+func (w *frameWriter) Write(data []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(data)
+}
+
+// TestExportImport checks that a chain exported one Block at a time is imported back into the same chain,
+// after its Blocks are disconnected, when the stream is read incrementally
+func TestExportImport(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(100))
+	height, head := chain.Height, chain.Head
+
+	var stream frameWriter
+	if err := chain.Export(&stream); err != nil {
+		t.Fatalf("failed to export chain: %v", err)
+	}
+
+	// Every Block is written as a single frame
+	if stream.writes != int(height) {
+		t.Fatalf("expected %v frames, got %v writes", height, stream.writes)
+	}
+
+	data := stream.Bytes()
+
+	for chain.Height > 1 {
+		if _, err := chain.disconnectTip(); err != nil {
+			t.Fatalf("failed to disconnect block: %v", err)
+		}
+	}
+
+	// The genesis Block is skipped as it already exists on the chain
+	imported, err := chain.Import(iotest.OneByteReader(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+
+	if imported != int(height)-1 || chain.Height != height || !chain.Head.Equal(head) {
+		t.Fatalf("expected %v blocks to be imported up to head '%v', got %v up to '%v'", height-1, head, imported, chain.Head)
+	}
+
+	// Importing the stream again skips every Block
+	if imported, err := chain.Import(bytes.NewReader(data)); err != nil || imported != 0 {
+		t.Fatalf("expected no blocks to be imported again, got %v: %v", imported, err)
+	}
+}
+
+// TestImportTruncated checks that Import reports a truncated frame of the stream
+func TestImportTruncated(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(2))
+
+	var stream bytes.Buffer
+	if err := chain.Export(&stream); err != nil {
+		t.Fatalf("failed to export chain: %v", err)
+	}
+
+	data := stream.Bytes()
+	if _, err := chain.Import(bytes.NewReader(data[:len(data)-1])); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected a truncated stream to be rejected, got %v", err)
+	}
+}
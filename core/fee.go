@@ -0,0 +1,67 @@
+package core
+
+import "fmt"
+
+// TransactionFee returns the fee paid by a Transaction, which is the value of its inputs not spent by its outputs.
+// The value of each input is resolved from its referenced output with the transaction index, so the fee
+// can be computed for both pending and confirmed Transactions. Coinbase Transactions pay no fee.
+// Returns an error if the output referenced by any input cannot be resolved.
+func (chain *ChainManager) TransactionFee(txn *Transaction) (int, error) {
+	if txn.IsCoinbase() {
+		return 0, nil
+	}
+
+	fee := 0
+	for idx, input := range txn.Inputs {
+		output, err := chain.FindOutput(Outpoint{input.ID, input.Out})
+		if err != nil {
+			return 0, fmt.Errorf("input %v: %w", idx, err)
+		}
+
+		fee += output.Value
+	}
+
+	for _, output := range txn.Outputs {
+		fee -= output.Value
+	}
+
+	return fee, nil
+}
+
+// TransactionFees returns the total fee paid by a sequence of Transactions, such as those of a Block.
+// The value of each input is resolved like TransactionFee, or from the outputs of an earlier Transaction
+// of the sequence. Returns an error if the output referenced by any input cannot be resolved.
+func (chain *ChainManager) TransactionFees(txns Transactions) (int, error) {
+	created := make(map[Outpoint]int)
+
+	fees := 0
+	for _, txn := range txns {
+		if !txn.IsCoinbase() {
+			for idx, input := range txn.Inputs {
+				outpoint := Outpoint{input.ID, input.Out}
+
+				value, exists := created[outpoint]
+				if !exists {
+					output, err := chain.FindOutput(outpoint)
+					if err != nil {
+						return 0, fmt.Errorf("transaction '%v': input %v: %w", txn.ID, idx, err)
+					}
+
+					value = output.Value
+				}
+
+				fees += value
+			}
+
+			for _, output := range txn.Outputs {
+				fees -= output.Value
+			}
+		}
+
+		for idx, output := range txn.Outputs {
+			created[Outpoint{txn.ID, idx}] = output.Value
+		}
+	}
+
+	return fees, nil
+}
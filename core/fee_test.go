@@ -0,0 +1,53 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestTransactionFee checks that the fee of a Transaction is the value of its resolved inputs
+// not spent by its outputs, both before and after the spent outputs are confirmed
+func TestTransactionFee(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	miner := common.MinerAddress()
+
+	// A fee of 7 on the genesis reward, followed by a fee of 3 on the outputs of the spend
+	spend := signedTransaction(t, miner, []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: 60, PubKey: "alice"}, TxOutput{Value: 33, PubKey: miner})
+	child := signedTransaction(t, "alice", []Outpoint{{spend.ID, 0}}, TxOutput{Value: 57, PubKey: "bob"})
+
+	if fee, err := chain.TransactionFee(spend); err != nil || fee != 7 {
+		t.Fatalf("expected a fee of 7, got %v: %v", fee, err)
+	}
+
+	// The outputs of the spend are only resolved from an earlier Transaction of the sequence
+	if fees, err := chain.TransactionFees(Transactions{spend, child}); err != nil || fees != 10 {
+		t.Fatalf("expected fees of 10, got %v: %v", fees, err)
+	}
+
+	if _, err := chain.TransactionFee(child); err == nil {
+		t.Fatalf("expected the fee of a transaction with an unresolved input to fail")
+	}
+
+	if err := chain.MineBlock(Transactions{spend}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	// Spent outputs are resolved with the transaction index once they are confirmed
+	if fee, err := chain.TransactionFee(spend); err != nil || fee != 7 {
+		t.Fatalf("expected a fee of 7 for the confirmed spend, got %v: %v", fee, err)
+	}
+
+	if fee, err := chain.TransactionFee(child); err != nil || fee != 3 {
+		t.Fatalf("expected a fee of 3, got %v: %v", fee, err)
+	}
+
+	tip, err := chain.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fee, err := chain.TransactionFee(tip.BlockTxns[0]); err != nil || fee != 0 {
+		t.Fatalf("expected a coinbase to pay no fee, got %v: %v", fee, err)
+	}
+}
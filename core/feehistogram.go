@@ -0,0 +1,92 @@
+package core
+
+import (
+	"fmt"
+	"math"
+)
+
+// DefaultFeeRateEdges are the lower edges of the buckets of a fee histogram, in fee per byte
+var DefaultFeeRateEdges = []float64{0, 0.01, 0.02, 0.05, 0.1, 0.2, 0.5, 1, 2, 5}
+
+// FeeRate returns the fee rate of a Transaction that pays the given fee, in fee per byte of its size
+func FeeRate(fee, size int) float64 {
+	if size <= 0 {
+		return 0
+	}
+
+	return float64(fee) / float64(size)
+}
+
+// FeeBucket represents a bucket of a fee histogram of the Mempool, which holds the pending
+// Transactions with a fee rate in the range [MinRate, MaxRate). MaxRate is +Inf for the highest bucket.
+type FeeBucket struct {
+	MinRate float64
+	MaxRate float64
+
+	// Count is the number of pending Transactions in the bucket
+	Count int
+	// Size is the total size in bytes of the pending Transactions in the bucket
+	Size int
+	// CumulativeSize is the total size in bytes of the pending Transactions
+	// in the bucket and every bucket with a higher fee rate
+	CumulativeSize int
+}
+
+// FeeHistogram returns the distribution of the fee rates of the pending Transactions in the Mempool,
+// bucketed by the given strictly increasing lower edges. Buckets are ordered from the highest fee rate
+// to the lowest, so the CumulativeSize of a bucket is the space in Blocks taken by the Transactions
+// that would be mined before a Transaction paying its MinRate. Transactions with a fee rate below
+// the first edge are not counted. Returns an error if the edges are empty, negative or not increasing.
+func (pool *Mempool) FeeHistogram(edges []float64) ([]FeeBucket, error) {
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("no fee rate edges")
+	}
+
+	for idx, edge := range edges {
+		if edge < 0 || math.IsNaN(edge) {
+			return nil, fmt.Errorf("fee rate edge %v: %v is negative", idx, edge)
+		}
+
+		if idx > 0 && edge <= edges[idx-1] {
+			return nil, fmt.Errorf("fee rate edge %v: %v is not greater than %v", idx, edge, edges[idx-1])
+		}
+	}
+
+	buckets := make([]FeeBucket, len(edges))
+	for idx, edge := range edges {
+		buckets[idx] = FeeBucket{MinRate: edge, MaxRate: math.Inf(1)}
+		if idx+1 < len(edges) {
+			buckets[idx].MaxRate = edges[idx+1]
+		}
+	}
+
+	pool.mutex.RLock()
+	for id, txn := range pool.txns {
+		size := txn.Size()
+		rate := FeeRate(pool.fees[id], size)
+
+		// Find the highest bucket whose lower edge does not exceed the rate
+		for idx := len(buckets) - 1; idx >= 0; idx-- {
+			if rate >= buckets[idx].MinRate {
+				buckets[idx].Count++
+				buckets[idx].Size += size
+				break
+			}
+		}
+	}
+	pool.mutex.RUnlock()
+
+	// Order the buckets from the highest fee rate and accumulate their sizes
+	histogram := make([]FeeBucket, 0, len(buckets))
+	cumulative := 0
+
+	for idx := len(buckets) - 1; idx >= 0; idx-- {
+		bucket := buckets[idx]
+		cumulative += bucket.Size
+		bucket.CumulativeSize = cumulative
+
+		histogram = append(histogram, bucket)
+	}
+
+	return histogram, nil
+}
@@ -0,0 +1,75 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// submitFeeRates mines a Block that pays an output of 30 to alice for each fee, then submits a Transaction
+// for each fee that spends one of the outputs. Returns the pending Transactions in the order of the fees.
+func submitFeeRates(t *testing.T, chain *ChainManager, fees ...int) Transactions {
+	t.Helper()
+
+	outputs := make([]TxOutput, len(fees))
+	for idx := range outputs {
+		outputs[idx] = TxOutput{Value: 30, PubKey: "alice"}
+	}
+
+	funding := signedTransaction(t, common.MinerAddress(), []Outpoint{genesisOutpoint(t, chain)}, outputs...)
+	if err := chain.MineBlock(Transactions{funding}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	pending := make(Transactions, len(fees))
+	for idx, fee := range fees {
+		pending[idx] = signedTransaction(t, "alice", []Outpoint{{funding.ID, idx}}, TxOutput{Value: 30 - fee, PubKey: "bob"})
+		if err := chain.SubmitTransaction(pending[idx]); err != nil {
+			t.Fatalf("failed to submit transaction: %v", err)
+		}
+	}
+
+	return pending
+}
+
+// TestFeeHistogram checks that each pending Transaction is counted in the bucket of its fee rate,
+// and that the buckets accumulate their sizes from the highest fee rate
+func TestFeeHistogram(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	fees := []int{1, 10, 25}
+	pending := submitFeeRates(t, chain, fees...)
+
+	rates := make([]float64, len(pending))
+	for idx, fee := range fees {
+		rates[idx] = FeeRate(fee, pending[idx].Size())
+	}
+
+	// Place each Transaction in its own bucket
+	edges := []float64{0, (rates[0] + rates[1]) / 2, (rates[1] + rates[2]) / 2}
+
+	histogram, err := chain.Mempool().FeeHistogram(edges)
+	if err != nil {
+		t.Fatalf("failed to compute fee histogram: %v", err)
+	}
+
+	if len(histogram) != 3 || !math.IsInf(histogram[0].MaxRate, 1) || histogram[2].MinRate != 0 {
+		t.Fatalf("expected 3 buckets from the highest fee rate, got %+v", histogram)
+	}
+
+	cumulative := 0
+	for idx, bucket := range histogram {
+		txn := pending[len(pending)-1-idx]
+		cumulative += txn.Size()
+
+		if bucket.Count != 1 || bucket.Size != txn.Size() || bucket.CumulativeSize != cumulative {
+			t.Fatalf("expected bucket %v to hold the transaction '%v' with a cumulative size of %v, got %+v", idx, txn.ID, cumulative, bucket)
+		}
+	}
+
+	for _, invalid := range [][]float64{nil, {-1, 0}, {0, 1, 1}} {
+		if _, err := chain.Mempool().FeeHistogram(invalid); err == nil {
+			t.Fatalf("expected the edges %v to be rejected", invalid)
+		}
+	}
+}
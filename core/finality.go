@@ -0,0 +1,53 @@
+package core
+
+// finalizer is a callback registered with OnFinalized
+type finalizer struct {
+	depth int64
+	fn    func(*Block)
+
+	// Represents the height of the next Block for which the callback is invoked
+	next int64
+}
+
+// OnFinalized registers a callback that is invoked for each Block of the chain once depth Blocks are built
+// on top of it, at which point the Block is considered final. Callbacks are invoked in order of height, only
+// for Blocks that become final after registration, on the goroutine that appends the Block that finalizes them.
+// Blocks removed by a reorganization before reaching the depth are never finalized. A reorganization deeper
+// than the depth replaces finalized Blocks, and the callback is not invoked again for their heights.
+// The callback must not modify the chain. A negative depth is treated as 0.
+func (chain *ChainManager) OnFinalized(depth int, fn func(*Block)) {
+	if depth < 0 {
+		depth = 0
+	}
+
+	final := &finalizer{depth: int64(depth), fn: fn, next: chain.Height - int64(depth)}
+	if final.next < 0 {
+		final.next = 0
+	}
+
+	chain.finalMutex.Lock()
+	defer chain.finalMutex.Unlock()
+
+	chain.finalizers = append(chain.finalizers, final)
+}
+
+// finalize invokes the OnFinalized callbacks for every Block that has reached their depth since the last call
+func (chain *ChainManager) finalize() {
+	chain.finalMutex.Lock()
+	defer chain.finalMutex.Unlock()
+
+	for _, final := range chain.finalizers {
+		// The last Block with depth Blocks on top of it
+		last := chain.Height - 1 - final.depth
+
+		for ; final.next <= last; final.next++ {
+			block, err := chain.GetBlockByHeight(final.next)
+			if err != nil {
+				chain.opts.Logger.Infof("Finalized block at height %v retrieve failed: %v", final.next, err)
+				break
+			}
+
+			final.fn(block)
+		}
+	}
+}
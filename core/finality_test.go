@@ -0,0 +1,54 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestOnFinalized checks that the callback is invoked in order for each Block once the configured depth of Blocks
+// is built on top of it, and never for a Block that is removed by a reorganization before reaching the depth
+func TestOnFinalized(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+
+	var finalized []common.Hash
+	chain.OnFinalized(2, func(block *Block) { finalized = append(finalized, block.BlockHash) })
+
+	genesis, err := chain.GetBlockByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.MineBlock(nil, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	if len(finalized) != 0 {
+		t.Fatalf("expected no block to be final at a depth of 1, got %v", finalized)
+	}
+
+	branch := branchBlock(t, chain, genesis, "branch")
+	if err := chain.Reorganize([]*Block{branch}); err != nil {
+		t.Fatalf("failed to reorganize chain: %v", err)
+	}
+
+	if len(finalized) != 0 {
+		t.Fatalf("expected no block to be final after the reorganization, got %v", finalized)
+	}
+
+	if err := chain.MineBlock(nil, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	if !equalHashes(finalized, []common.Hash{genesis.BlockHash}) {
+		t.Fatalf("expected only the genesis block to be final, got %v", finalized)
+	}
+
+	if err := chain.MineBlock(nil, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	if !equalHashes(finalized, []common.Hash{genesis.BlockHash, branch.BlockHash}) {
+		t.Fatalf("expected the genesis and branch blocks to be final in order, without the removed block, got %v", finalized)
+	}
+}
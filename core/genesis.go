@@ -0,0 +1,141 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/anee769/essensio/common"
+)
+
+// GenesisMode represents the kind of coinbase Transaction in the Genesis Block
+type GenesisMode int
+
+const (
+	// GenesisMarker is the mode of a Genesis Block whose coinbase only marks the start of the chain,
+	// paying the standard block reward to the Recipient of the GenesisConfig
+	GenesisMarker GenesisMode = iota
+	// GenesisFunded is the mode of a Genesis Block whose coinbase pre-funds a set of Addresses,
+	// with an output for each GenesisAllocation of the GenesisConfig
+	GenesisFunded
+)
+
+// String implements the Stringer interface for GenesisMode
+func (mode GenesisMode) String() string {
+	switch mode {
+	case GenesisMarker:
+		return "marker"
+	case GenesisFunded:
+		return "funded"
+	default:
+		return fmt.Sprintf("GenesisMode(%d)", int(mode))
+	}
+}
+
+// GenesisAllocation represents the value pre-funded to an Address by a funded Genesis Block
+type GenesisAllocation struct {
+	Address common.Address
+	Value   int
+}
+
+// GenesisConfig represents the configuration of the Genesis Block of a chain.
+// The Genesis Block is fully determined by its GenesisConfig and the initial PoW target,
+// so chains created with the same configuration share the same Genesis Block hash.
+type GenesisConfig struct {
+	// Timestamp is the timestamp of the Genesis Block header
+	Timestamp int64
+	// Data is the data tagged in the coinbase Transaction of the Genesis Block
+	Data string
+	// Mode is the kind of coinbase Transaction in the Genesis Block
+	Mode GenesisMode
+	// Recipient is the Address paid by the coinbase Transaction in GenesisMarker mode. It is part of the
+	// config rather than the miner Address of the node, so that every node creates the same Genesis Block.
+	// It is unused in GenesisFunded mode.
+	Recipient common.Address
+	// Allocations are the outputs of the coinbase Transaction in GenesisFunded mode, in order.
+	// Must be empty in GenesisMarker mode.
+	Allocations []GenesisAllocation
+}
+
+// DefaultGenesisConfig returns the default GenesisConfig
+func DefaultGenesisConfig() GenesisConfig {
+	return GenesisConfig{
+		Timestamp: 1672531200,
+		Data:      "Genesis Block Coinbase Transaction",
+		Recipient: common.MinerAddress(),
+	}
+}
+
+// genesisCoinbase generates the coinbase Transaction of the Genesis Block for the mode of a GenesisConfig.
+// Returns an error if the mode is unknown or the allocations of the config are invalid for it.
+func genesisCoinbase(config GenesisConfig) (*Transaction, error) {
+	switch config.Mode {
+	case GenesisMarker:
+		if len(config.Allocations) != 0 {
+			return nil, fmt.Errorf("marker genesis cannot have allocations")
+		}
+
+		if config.Recipient == common.NullAddress() {
+			return nil, fmt.Errorf("marker genesis has no recipient")
+		}
+
+		return CoinbaseTxn(config.Recipient, config.Data, 0), nil
+
+	case GenesisFunded:
+		if len(config.Allocations) == 0 {
+			return nil, fmt.Errorf("funded genesis has no allocations")
+		}
+
+		outputs := make([]TxOutput, 0, len(config.Allocations))
+		for idx, allocation := range config.Allocations {
+			if allocation.Value <= 0 {
+				return nil, fmt.Errorf("genesis allocation %v: value %v is not positive", idx, allocation.Value)
+			}
+
+			outputs = append(outputs, TxOutput{Value: allocation.Value, PubKey: allocation.Address})
+		}
+
+		txnIn := TxInput{common.NullHash(), -1, common.Address(config.Data)}
+
+		tx := Transaction{common.NullHash(), []TxInput{txnIn}, outputs, 0}
+		if err := tx.SetID(); err != nil {
+			return nil, fmt.Errorf("transaction id generation failed: %w", err)
+		}
+
+		return &tx, nil
+
+	default:
+		return nil, fmt.Errorf("unknown genesis mode: %v", config.Mode)
+	}
+}
+
+// NewGenesisBlock generates the Genesis Block for a given GenesisConfig and compact PoW target.
+// Returns an error if the GenesisConfig is invalid for its mode.
+func NewGenesisBlock(config GenesisConfig, bits uint32) (*Block, error) {
+	coinbase, err := genesisCoinbase(config)
+	if err != nil {
+		return nil, err
+	}
+
+	txns := Transactions{coinbase}
+	block := &Block{BlockTxns: txns, BlockHeight: 0}
+
+	// Create a BlockHeader with the fixed timestamp of the config
+	block.BlockHeader = NewBlockHeader(common.NullHash(), GenerateSummary(txns), bits)
+	block.Timestamp = config.Timestamp
+
+	// Mine the Block & set the block hash
+	block.BlockHash = block.BlockHeader.Mint()
+
+	return block, nil
+}
+
+// NetworkID returns the identifier of the network of the chain, which is the hash of its Genesis Block.
+// Chains with different Genesis Blocks can never share Blocks, so nodes can compare their
+// NetworkID to ensure that they belong to the same network.
+func (chain *ChainManager) NetworkID() (common.Hash, error) {
+	hash, err := chain.BlockHashAtHeight(0)
+	if err != nil {
+		return common.NullHash(), fmt.Errorf("genesis block hash retrieve failed: %w", err)
+	}
+
+	return hash, nil
+}
@@ -0,0 +1,226 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestNetworkID checks that chains with the same GenesisConfig report the same NetworkID,
+// while changes to any part of the config or the initial target change it
+func TestNetworkID(t *testing.T) {
+	base := DefaultOptions()
+	base.InitialBits = DifficultyToBits(8)
+
+	// Each chain is stopped before the next one is created in the same database directory
+	networkID := func(opts Options) common.Hash {
+		resetDatabase(t)
+		t.Cleanup(func() { resetDatabase(t) })
+
+		chain, err := NewChainManager(opts)
+		if err != nil {
+			t.Fatalf("failed to open chain: %v", err)
+		}
+
+		defer chain.Stop()
+
+		id, err := chain.NetworkID()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return id
+	}
+
+	id := networkID(base)
+	if again := networkID(base); !again.Equal(id) {
+		t.Fatalf("expected chains with the same genesis config to share network id '%v', got '%v'", id, again)
+	}
+
+	variants := map[string]func(opts *Options){
+		"timestamp": func(opts *Options) { opts.Genesis.Timestamp++ },
+		"data":      func(opts *Options) { opts.Genesis.Data = "other genesis" },
+		"recipient": func(opts *Options) { opts.Genesis.Recipient = "other" },
+		"bits":      func(opts *Options) { opts.InitialBits = DifficultyToBits(9) },
+		"funded": func(opts *Options) {
+			opts.Genesis.Mode = GenesisFunded
+			opts.Genesis.Allocations = []GenesisAllocation{{Address: "alice", Value: 500}}
+		},
+	}
+
+	for name, variant := range variants {
+		t.Run(name, func(t *testing.T) {
+			opts := base
+			variant(&opts)
+
+			if other := networkID(opts); other.Equal(id) {
+				t.Fatalf("expected a different network id for a different genesis config")
+			}
+		})
+	}
+}
+
+// TestGenesisMarker checks that the marker Genesis Block pays the block reward to the miner Address
+func TestGenesisMarker(t *testing.T) {
+	chain := newChain(t, DefaultOptions())
+
+	balances, err := chain.AllBalances()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(balances) != 1 || balances[common.MinerAddress()] != BlockReward {
+		t.Fatalf("expected only the block reward of the miner in the genesis utxo set, got %v", balances)
+	}
+}
+
+// TestGenesisRecipient checks that two nodes initialized with the same GenesisConfig produce byte-identical
+// Genesis Blocks, whose coinbase pays the recipient of the config rather than the miner Address
+func TestGenesisRecipient(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Genesis.Recipient = "network-owner"
+
+	// Each node is stopped before the next one is created in the same database directory
+	var encoded [][]byte
+	for node := 0; node < 2; node++ {
+		chain := generateTestChain(t, TestChainOptions{Options: opts, NoPoW: true})
+
+		genesis, err := chain.GetBlockByHeight(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := genesis.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		balances, err := chain.AllBalances()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(balances) != 1 || balances["network-owner"] != BlockReward {
+			t.Fatalf("expected only the block reward of the recipient in the genesis utxo set, got %v", balances)
+		}
+
+		encoded = append(encoded, data)
+		chain.Stop()
+	}
+
+	if !bytes.Equal(encoded[0], encoded[1]) {
+		t.Fatalf("expected byte-identical genesis blocks for the same genesis config")
+	}
+
+	opts.Genesis.Recipient = common.NullAddress()
+	if _, err := NewGenesisBlock(opts.Genesis, opts.InitialBits); err == nil {
+		t.Fatalf("expected a marker genesis without a recipient to be rejected")
+	}
+}
+
+// TestGenesisFunded checks that the funded Genesis Block has an output for each allocation, which can be spent
+func TestGenesisFunded(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Genesis.Mode = GenesisFunded
+	opts.Genesis.Allocations = []GenesisAllocation{{"alice", 500}, {"bob", 300}, {"carol", 200}}
+
+	chain := newChain(t, opts)
+
+	balances, err := chain.AllBalances()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(balances) != 3 || balances["alice"] != 500 || balances["bob"] != 300 || balances["carol"] != 200 {
+		t.Fatalf("expected the allocations in the genesis utxo set, got %v", balances)
+	}
+
+	genesis, err := chain.GetBlockByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spend := signedTransaction(t, "bob", []Outpoint{{genesis.BlockTxns[0].ID, 1}}, TxOutput{Value: 300, PubKey: "dave"})
+	if err := chain.VerifyTransaction(spend); err != nil {
+		t.Fatalf("expected the allocation of bob to be spendable, got %v", err)
+	}
+}
+
+// TestGenesisModeInvalid checks that GenesisConfigs with allocations that are invalid for their mode are rejected
+func TestGenesisModeInvalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        GenesisMode
+		allocations []GenesisAllocation
+	}{
+		{"marker with allocations", GenesisMarker, []GenesisAllocation{{"alice", 100}}},
+		{"funded without allocations", GenesisFunded, nil},
+		{"funded with zero value", GenesisFunded, []GenesisAllocation{{"alice", 0}}},
+		{"unknown mode", GenesisMode(7), nil},
+	}
+
+	for _, test := range tests {
+		config := DefaultGenesisConfig()
+		config.Mode, config.Allocations = test.mode, test.allocations
+
+		if _, err := NewGenesisBlock(config, DifficultyToBits(1)); err == nil {
+			t.Errorf("%v: expected the genesis config to be rejected", test.name)
+		}
+	}
+}
+
+// TestInitInterrupted checks that a chain whose initialization crashed after the Genesis Block was written,
+// but before the chain state was synced, is initialized again from scratch when it is reopened
+func TestInitInterrupted(t *testing.T) {
+	resetDatabase(t)
+	t.Cleanup(func() { resetDatabase(t) })
+
+	opts := DefaultOptions()
+	genesis, err := NewGenesisBlock(opts.Genesis, opts.InitialBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A crash within the Batch of init discards the Genesis Block that was written
+	partial := &ChainManager{opts: opts}
+	if err := partial.openDB(); err != nil {
+		t.Fatal(err)
+	}
+
+	crash := errors.New("crash")
+	if err := partial.db.Batch(func() error {
+		if err := partial.storeBlock(genesis); err != nil {
+			return err
+		}
+
+		return crash
+	}); !errors.Is(err, crash) {
+		t.Fatalf("expected the batch to fail with the crash, got %v", err)
+	}
+
+	if exists, err := partial.db.HasEntry(genesis.BlockHash.Bytes()); err != nil || exists {
+		t.Fatalf("expected the genesis block of the crashed batch not to be stored, got %v", err)
+	}
+
+	// A Genesis Block written without a chain state, as by an init that crashed before this database Batch
+	if err := partial.storeBlock(genesis); err != nil {
+		t.Fatal(err)
+	}
+
+	partial.db.Close()
+
+	chain := openTestChain(t, opts)
+	if chain.Height != 1 || !chain.Head.Equal(genesis.BlockHash) {
+		t.Fatalf("expected a chain of height 1 at the genesis block, got height %v at '%v'", chain.Height, chain.Head)
+	}
+
+	if chain.TxnCount != 1 || chain.Supply != BlockReward {
+		t.Fatalf("expected the stats of the genesis block, got %v and %v", chain.TxnCount, chain.Supply)
+	}
+
+	if err := chain.VerifyChain(); err != nil {
+		t.Fatalf("expected the reinitialized chain to be valid, got %v", err)
+	}
+}
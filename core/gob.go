@@ -0,0 +1,16 @@
+package core
+
+import "github.com/anee769/essensio/common"
+
+// init registers the concrete types of the core package that are serialized with gob
+func init() {
+	common.RegisterTypes(
+		Block{},
+		BlockHeader{},
+		Transaction{},
+		Transactions{},
+		TxInput{},
+		TxOutput{},
+		Outpoint{},
+	)
+}
@@ -0,0 +1,43 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestGobInterfaceFields checks that Transactions and Blocks with every field set are decoded cleanly
+// when they are serialized through interface fields, by decoders that have not seen any of their types
+func TestGobInterfaceFields(t *testing.T) {
+	txn := &Transaction{
+		Inputs: []TxInput{{common.Hash256([]byte("parent")), 1, "signature"}},
+		Outputs: []TxOutput{
+			{Value: 60, PubKey: "alice", Lock: OutputLock{Height: 10, Time: 1672531200}},
+			{Value: 40, PubKey: "bob"},
+		},
+		Height: 7,
+	}
+
+	txn.SetID()
+
+	block := NewBlock(Transactions{CoinbaseTxn("miner", "coinbase", 7), txn}, common.Hash256([]byte("priori")), 7, DifficultyToBits(1))
+
+	// Interface fields hold the registered concrete types, which are values rather than pointers
+	for _, value := range []any{*txn, Transactions{txn}, *block, block.BlockHeader, Outpoint{txn.ID, 1}} {
+		// Each value is encoded and decoded with a new encoder and decoder
+		data, err := common.GobEncode(struct{ Value any }{value})
+		if err != nil {
+			t.Fatalf("%T: encode failed: %v", value, err)
+		}
+
+		decoded := new(struct{ Value any })
+		if _, err := common.GobDecode(data, decoded); err != nil {
+			t.Fatalf("%T: decode failed: %v", value, err)
+		}
+
+		if !reflect.DeepEqual(decoded.Value, value) {
+			t.Fatalf("%T: expected %+v, got %+v", value, value, decoded.Value)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/anee769/essensio/common"
+)
+
+// The gob encoding of an object depends on the order in which types are first used by the running
+// process, so it is not suitable for hashing. Objects that are hashed are instead encoded into a
+// canonical representation that is identical across processes.
+
+// writeInt writes an integer as 8 big-endian bytes into the buffer
+func writeInt(buffer *bytes.Buffer, value int64) {
+	var data [8]byte
+	binary.BigEndian.PutUint64(data[:], uint64(value))
+	buffer.Write(data[:])
+}
+
+// writeBytes writes a length-prefixed stream of bytes into the buffer
+func writeBytes(buffer *bytes.Buffer, data []byte) {
+	writeInt(buffer, int64(len(data)))
+	buffer.Write(data)
+}
+
+// hashData returns the canonical representation of the BlockHeader for hashing
+func (header *BlockHeader) hashData() []byte {
+	var buffer bytes.Buffer
+
+	buffer.Write(header.Priori.Bytes())
+	buffer.Write(header.Summary.Bytes())
+	writeInt(&buffer, header.Timestamp)
+	writeInt(&buffer, int64(header.Bits))
+	writeInt(&buffer, header.Nonce)
+
+	return buffer.Bytes()
+}
+
+// Hash returns the hash of the BlockHeader
+func (header *BlockHeader) Hash() common.Hash {
+	return common.Hash256(header.hashData())
+}
+
+// hashData returns the canonical representation of the Transaction for hashing.
+// The ID of the Transaction is not included in the representation.
+func (txn *Transaction) hashData() []byte {
+	return txn.canonicalData(true)
+}
+
+// canonicalData returns the canonical representation of the Transaction, excluding its ID.
+// If signatures is not set, the signatures of the inputs are encoded as empty.
+func (txn *Transaction) canonicalData(signatures bool) []byte {
+	var buffer bytes.Buffer
+
+	writeInt(&buffer, int64(len(txn.Inputs)))
+	for _, input := range txn.Inputs {
+		buffer.Write(input.ID.Bytes())
+		writeInt(&buffer, int64(input.Out))
+
+		if signatures {
+			writeBytes(&buffer, input.Sig.Bytes())
+		} else {
+			writeBytes(&buffer, nil)
+		}
+	}
+
+	writeInt(&buffer, int64(len(txn.Outputs)))
+	for _, output := range txn.Outputs {
+		writeInt(&buffer, int64(output.Value))
+		writeBytes(&buffer, output.PubKey.Bytes())
+	}
+
+	writeInt(&buffer, txn.Height)
+
+	// The locks of the outputs follow the height only if any output is locked, which keeps the
+	// representation of Transactions without locks unchanged. The representation remains unambiguous,
+	// as the locks are the only data that can follow the height.
+	if txn.hasLockedOutputs() {
+		for _, output := range txn.Outputs {
+			writeInt(&buffer, output.Lock.Height)
+			writeInt(&buffer, output.Lock.Time)
+		}
+	}
+
+	return buffer.Bytes()
+}
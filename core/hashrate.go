@@ -0,0 +1,60 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// DefaultHashRateBlocks is the default number of Blocks over which NetworkHashRate is estimated
+const DefaultHashRateBlocks = 120
+
+// NetworkHashRate estimates the hash rate of the network in hashes per second over the given number of the
+// most recent Blocks. It is the total work of those Blocks divided by the time from the timestamp of the Block
+// before them to that of the chain head. The window is bounded by the Blocks after the Genesis Block, and the
+// estimate is 0 for a chain of only the Genesis Block, or if the timestamps of the window do not advance.
+func (chain *ChainManager) NetworkHashRate(blocks int64) (float64, error) {
+	if blocks <= 0 {
+		return 0, fmt.Errorf("invalid hash rate window: %v", blocks)
+	}
+
+	if blocks > chain.Height-1 {
+		blocks = chain.Height - 1
+	}
+
+	if blocks <= 0 {
+		return 0, nil
+	}
+
+	iter := chain.NewIterator()
+
+	// Sum the work of the window, from the chain head backwards
+	work := new(big.Int)
+	var end int64
+
+	for count := int64(0); count < blocks; count++ {
+		header, err := iter.NextHeader()
+		if err != nil {
+			return 0, fmt.Errorf("chain header retrieve failed: %w", err)
+		}
+
+		if count == 0 {
+			end = header.Timestamp
+		}
+
+		work.Add(work, BlockWork(header.Bits))
+	}
+
+	// The window starts at the timestamp of the Block before it
+	start, err := iter.NextHeader()
+	if err != nil {
+		return 0, fmt.Errorf("chain header retrieve failed: %w", err)
+	}
+
+	elapsed := end - start.Timestamp
+	if elapsed <= 0 {
+		return 0, nil
+	}
+
+	rate, _ := new(big.Float).Quo(new(big.Float).SetInt(work), big.NewFloat(float64(elapsed))).Float64()
+	return rate, nil
+}
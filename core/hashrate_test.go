@@ -0,0 +1,74 @@
+package core
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+// TestNetworkHashRate checks that NetworkHashRate on a chain with known difficulty and timestamps matches
+// the work of its window divided by the time it spans, with the window bounded by the Genesis Block
+func TestNetworkHashRate(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	genesis := DefaultGenesisConfig().Timestamp
+
+	if rate, err := chain.NetworkHashRate(10); err != nil || rate != 0 {
+		t.Fatalf("expected no hash rate for a chain of only the genesis block, got %v and %v", rate, err)
+	}
+
+	// The Blocks are mined a minute apart with the same difficulty
+	var bits uint32
+	for idx := int64(1); idx <= 4; idx++ {
+		block := timedBlock(t, chain, genesis+60*idx)
+		if err := chain.appendBlock(block); err != nil {
+			t.Fatalf("failed to append block: %v", err)
+		}
+
+		bits = block.Bits
+	}
+
+	tests := []struct {
+		blocks  int64
+		elapsed int64
+		count   int64
+	}{
+		{3, 180, 3},
+		{4, 240, 4},
+		// The window is bounded by the Blocks after the Genesis Block
+		{10, 240, 4},
+	}
+
+	for _, test := range tests {
+		work := new(big.Float).SetInt(new(big.Int).Mul(BlockWork(bits), big.NewInt(test.count)))
+		want, _ := new(big.Float).Quo(work, big.NewFloat(float64(test.elapsed))).Float64()
+
+		rate, err := chain.NetworkHashRate(test.blocks)
+		if err != nil {
+			t.Fatalf("failed to estimate hash rate over %v blocks: %v", test.blocks, err)
+		}
+
+		if math.Abs(rate-want) > want*1e-9 {
+			t.Errorf("expected a hash rate of %v over %v blocks, got %v", want, test.blocks, rate)
+		}
+	}
+
+	if _, err := chain.NetworkHashRate(0); err == nil {
+		t.Fatalf("expected an error for an empty window")
+	}
+}
+
+// TestNetworkHashRateStalled checks that NetworkHashRate is 0 for a window whose timestamps do not advance
+func TestNetworkHashRateStalled(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	genesis := DefaultGenesisConfig().Timestamp
+
+	for idx := 0; idx < 2; idx++ {
+		if err := chain.appendBlock(timedBlock(t, chain, genesis)); err != nil {
+			t.Fatalf("failed to append block: %v", err)
+		}
+	}
+
+	if rate, err := chain.NetworkHashRate(2); err != nil || rate != 0 {
+		t.Fatalf("expected no hash rate for a stalled window, got %v and %v", rate, err)
+	}
+}
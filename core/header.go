@@ -18,23 +18,37 @@ type BlockHeader struct {
 	// Timestamp at the time of block creation
 	Timestamp int64
 
-	// Proof of Work Target Hash
-	Target *big.Int
+	// Proof of Work Target Hash in its compact representation
+	Bits uint32
 	// Proof of Work Nonce
 	Nonce int64
 }
 
-// NewBlockHeader returns a new BlockHeader for a given priori and summary hash
-func NewBlockHeader(priori, summary common.Hash) BlockHeader {
+// HeaderPrefix is the key prefix for the BlockHeader of each Block in the database.
+// Headers are stored separately from their Blocks so that they can be loaded without the Block body.
+var HeaderPrefix = []byte("header-")
+
+// headerKey returns the database key for the BlockHeader of the Block with the given hash
+func headerKey(hash common.Hash) []byte {
+	return append(append([]byte{}, HeaderPrefix...), hash.Bytes()...)
+}
+
+// NewBlockHeader returns a new BlockHeader for a given priori and summary hash and compact PoW target
+func NewBlockHeader(priori, summary common.Hash, bits uint32) BlockHeader {
 	return BlockHeader{
 		priori,
 		summary,
 		time.Now().Unix(),
-		GenerateTarget(),
+		bits,
 		0,
 	}
 }
 
+// Target returns the Proof of Work Target Hash of the BlockHeader as a big.Int
+func (header *BlockHeader) Target() *big.Int {
+	return CompactToTarget(header.Bits)
+}
+
 // Serialize implements the common.Serializable interface for BlockHeader.
 // Converts the BlockHeader into a stream of bytes encoded using common.GobEncode.
 func (header *BlockHeader) Serialize() ([]byte, error) {
@@ -42,10 +56,11 @@ func (header *BlockHeader) Serialize() ([]byte, error) {
 }
 
 // Deserialize implements the common.Serializable interface for BlockHeader.
-// Converts the given data into BlockHeader and sets it the method's receiver using common.GobDecode.
+// Converts the given data into BlockHeader and sets it the method's receiver using common.GobDecodeLimit.
+// Returns an error without decoding if the data is longer than MaxHeaderDataSize.
 func (header *BlockHeader) Deserialize(data []byte) error {
 	// Decode the data into a *BlockHeader
-	object, err := common.GobDecode(data, new(BlockHeader))
+	object, err := common.GobDecodeLimit(data, new(BlockHeader), MaxHeaderDataSize)
 	if err != nil {
 		return err
 	}
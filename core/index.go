@@ -0,0 +1,132 @@
+package core
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/anee769/essensio/common"
+)
+
+// HeightIndexPrefix is the key prefix for the entries of the height index in the database.
+// The height index maps the height of each Block on the chain to its hash.
+var HeightIndexPrefix = []byte("index-height-")
+
+// TxIndexPrefix is the key prefix for the entries of the transaction index in the database.
+// The transaction index maps the ID of each Transaction on the chain to the hash of its Block.
+var TxIndexPrefix = []byte("index-txn-")
+
+// SpentIndexPrefix is the key prefix for the entries of the spent index in the database.
+// The spent index maps each spent output on the chain to the ID of the Transaction that spent it.
+var SpentIndexPrefix = []byte("index-spent-")
+
+// txnKey returns the database key for the given Transaction ID in the transaction index
+func txnKey(id common.Hash) []byte {
+	return append(append([]byte{}, TxIndexPrefix...), id.Bytes()...)
+}
+
+// HasTransaction returns whether a Transaction with the given ID exists on the chain
+func (chain *ChainManager) HasTransaction(id common.Hash) (bool, error) {
+	return chain.db.HasEntry(txnKey(id))
+}
+
+// FindTransactionBlock returns the Block containing the Transaction
+// with the given ID, located with the transaction index.
+func (chain *ChainManager) FindTransactionBlock(id common.Hash) (*Block, error) {
+	exists, err := chain.HasTransaction(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("transaction '%v' not found", id)
+	}
+
+	hash, err := chain.db.GetEntry(txnKey(id))
+	if err != nil {
+		return nil, fmt.Errorf("transaction index retrieve failed: %w", err)
+	}
+
+	return chain.GetBlock(common.BytesToHash(hash))
+}
+
+// heightKey returns the database key for the given height in the height index
+func heightKey(height int64) []byte {
+	key := make([]byte, len(HeightIndexPrefix)+8)
+	copy(key, HeightIndexPrefix)
+	binary.BigEndian.PutUint64(key[len(HeightIndexPrefix):], uint64(height))
+
+	return key
+}
+
+// BlockHashAtHeight returns the hash of the Block at the given height from the height index
+func (chain *ChainManager) BlockHashAtHeight(height int64) (common.Hash, error) {
+	if height < 0 || height >= chain.Height {
+		return common.NullHash(), fmt.Errorf("height %v out of range for chain height %v", height, chain.Height)
+	}
+
+	hash, err := chain.db.GetEntry(heightKey(height))
+	if err != nil {
+		return common.NullHash(), fmt.Errorf("height index retrieve failed: %w", err)
+	}
+
+	return common.BytesToHash(hash), nil
+}
+
+// GetBlockByHeight returns the Block at the given height, resolved with the height index
+func (chain *ChainManager) GetBlockByHeight(height int64) (*Block, error) {
+	hash, err := chain.BlockHashAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	return chain.GetBlock(hash)
+}
+
+// indexBlock adds the given Block to the height index and its Transactions to the transaction index
+func (chain *ChainManager) indexBlock(block *Block) error {
+	if err := chain.db.SetEntry(heightKey(block.BlockHeight), block.BlockHash.Bytes()); err != nil {
+		return fmt.Errorf("height index update failed: %w", err)
+	}
+
+	return chain.indexTransactions(block)
+}
+
+// indexTransactions adds the Transactions of the given Block to the transaction
+// index and the outputs spent by them to the spent index
+func (chain *ChainManager) indexTransactions(block *Block) error {
+	for _, txn := range block.BlockTxns {
+		if err := chain.db.SetEntry(txnKey(txn.ID), block.BlockHash.Bytes()); err != nil {
+			return fmt.Errorf("transaction index update failed: %w", err)
+		}
+
+		if txn.IsCoinbase() {
+			continue
+		}
+
+		for _, input := range txn.Inputs {
+			if err := chain.db.SetEntry(Outpoint{input.ID, input.Out}.prefixedKey(SpentIndexPrefix), txn.ID.Bytes()); err != nil {
+				return fmt.Errorf("spent index update failed: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// FindSpender returns the ID of the Transaction on the chain that spent the output referenced by
+// the Outpoint, located with the spent index, and whether the output is spent.
+func (chain *ChainManager) FindSpender(outpoint Outpoint) (common.Hash, bool, error) {
+	key := outpoint.prefixedKey(SpentIndexPrefix)
+
+	spent, err := chain.db.HasEntry(key)
+	if err != nil || !spent {
+		return common.NullHash(), false, err
+	}
+
+	id, err := chain.db.GetEntry(key)
+	if err != nil {
+		return common.NullHash(), false, fmt.Errorf("spent index retrieve failed: %w", err)
+	}
+
+	return common.BytesToHash(id), true, nil
+}
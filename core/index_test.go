@@ -0,0 +1,36 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestFindSpender checks that the spent index records the spender of an output when its Block is appended,
+// and removes it when the Block is disconnected
+func TestFindSpender(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	genesis := genesisOutpoint(t, chain)
+
+	if _, spent, err := chain.FindSpender(genesis); err != nil || spent {
+		t.Fatalf("expected the genesis output to be unspent, got %v", err)
+	}
+
+	spend := signedTransaction(t, common.MinerAddress(), []Outpoint{genesis}, TxOutput{Value: BlockReward, PubKey: "alice"})
+	if err := chain.MineBlock(Transactions{spend}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	spender, spent, err := chain.FindSpender(genesis)
+	if err != nil || !spent || !spender.Equal(spend.ID) {
+		t.Fatalf("expected the genesis output to be spent by '%v', got '%v': %v", spend.ID, spender, err)
+	}
+
+	if _, err := chain.disconnectTip(); err != nil {
+		t.Fatalf("failed to disconnect block: %v", err)
+	}
+
+	if _, spent, err := chain.FindSpender(genesis); err != nil || spent {
+		t.Fatalf("expected the genesis output to be unspent after the block is disconnected, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/anee769/essensio/common"
+)
+
+// LabelPrefix is the key prefix for the entries of the label index in the database.
+// Each entry maps a user label to the hash of a Block. Labels are a convenience for
+// operators and are not part of consensus, so they are kept across reindexing.
+var LabelPrefix = []byte("label-")
+
+// labelKey returns the database key for the label index entry of a label
+func labelKey(label string) []byte {
+	return append(append([]byte{}, LabelPrefix...), label...)
+}
+
+// LabelBlock assigns a label to the Block with the given hash, which must be on the chain.
+// Each label refers to a single Block, so labeling another Block with an existing label
+// moves the label to it. A Block may have any number of labels.
+func (chain *ChainManager) LabelBlock(hash common.Hash, label string) error {
+	if label == "" {
+		return fmt.Errorf("empty label")
+	}
+
+	exists, err := chain.HasBlock(hash)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("block '%v' is not on the chain", hash)
+	}
+
+	if err := chain.db.SetEntry(labelKey(label), hash.Bytes()); err != nil {
+		return fmt.Errorf("label index update failed: %w", err)
+	}
+
+	return nil
+}
+
+// GetBlockByLabel returns the Block assigned to a label with LabelBlock
+func (chain *ChainManager) GetBlockByLabel(label string) (*Block, error) {
+	key := labelKey(label)
+
+	exists, err := chain.db.HasEntry(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("no block with label '%v'", label)
+	}
+
+	hash, err := chain.db.GetEntry(key)
+	if err != nil {
+		return nil, fmt.Errorf("label index retrieve failed: %w", err)
+	}
+
+	return chain.GetBlock(common.BytesToHash(hash))
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestLabelBlock checks that a labeled Block is retrieved by its label, that relabeling moves the label
+// to another Block, and that only Blocks on the chain can be labeled
+func TestLabelBlock(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(2))
+
+	first, err := chain.BlockHashAtHeight(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.LabelBlock(first, "pre-upgrade"); err != nil {
+		t.Fatalf("failed to label block: %v", err)
+	}
+
+	block, err := chain.GetBlockByLabel("pre-upgrade")
+	if err != nil {
+		t.Fatalf("failed to get block by label: %v", err)
+	}
+
+	if !block.BlockHash.Equal(first) {
+		t.Fatalf("expected the labeled block '%v', got '%v'", first, block.BlockHash)
+	}
+
+	if err := chain.LabelBlock(chain.Head, "pre-upgrade"); err != nil {
+		t.Fatalf("failed to relabel block: %v", err)
+	}
+
+	block, err = chain.GetBlockByLabel("pre-upgrade")
+	if err != nil {
+		t.Fatalf("failed to get block by label: %v", err)
+	}
+
+	if !block.BlockHash.Equal(chain.Head) {
+		t.Fatalf("expected the label to move to the chain head, got '%v'", block.BlockHash)
+	}
+
+	if _, err := chain.GetBlockByLabel("unknown"); err == nil {
+		t.Fatalf("expected no block for an unknown label")
+	}
+
+	if err := chain.LabelBlock(common.Hash256([]byte("unknown")), "unknown"); err == nil {
+		t.Fatalf("expected an unknown block not to be labeled")
+	}
+
+	if err := chain.LabelBlock(first, ""); err == nil {
+		t.Fatalf("expected an empty label to be rejected")
+	}
+}
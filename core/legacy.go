@@ -0,0 +1,157 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/anee769/essensio/common"
+)
+
+// legacyBlockHeader represents the BlockHeader of the legacy block format, which stored the full PoW target
+// and was hashed from its gob encoding. Gob encodings depend on the type ids of the process that wrote them,
+// so legacy Blocks cannot be verified and must be rehashed with the canonical encoding to be migrated.
+type legacyBlockHeader struct {
+	Priori    common.Hash
+	Summary   common.Hash
+	Timestamp int64
+	Target    *big.Int
+	Nonce     int64
+}
+
+// legacyBlock represents a Block of the legacy block format, which is stored without a height index,
+// headers or a UTXO set. Its Transactions decode as Transactions with a zero height and no output locks.
+type legacyBlock struct {
+	BlockHeader legacyBlockHeader
+	BlockHeight int64
+	BlockTxns   Transactions
+	BlockHash   common.Hash
+}
+
+// hasLegacyBlocks returns whether the chain is stored in the legacy block format, which has no height index.
+// Only databases with a chain state at the legacy keys can be in the legacy block format.
+func (chain *ChainManager) hasLegacyBlocks() (bool, error) {
+	indexed, err := chain.db.HasEntry(heightKey(0))
+	if err != nil {
+		return false, err
+	}
+
+	return !indexed, nil
+}
+
+// readLegacyChain returns the legacy Blocks of the chain from the Genesis Block to the chain head,
+// which are walked from the chain head by their priori hashes
+func (chain *ChainManager) readLegacyChain() ([]*legacyBlock, error) {
+	blocks := make([]*legacyBlock, chain.Height)
+
+	hash := chain.Head
+	for height := chain.Height - 1; height >= 0; height-- {
+		data, err := chain.db.GetEntry(hash.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("cannot find legacy block '%v': %w", hash, err)
+		}
+
+		object, err := common.GobDecode(data, new(legacyBlock))
+		if err != nil {
+			return nil, fmt.Errorf("legacy block '%v' deserialize failed: %w", hash, err)
+		}
+
+		block := object.(*legacyBlock)
+		if block.BlockHeight != height {
+			return nil, fmt.Errorf("legacy block '%v' at chain height %v has height %v", hash, height, block.BlockHeight)
+		}
+
+		blocks[height], hash = block, block.BlockHeader.Priori
+	}
+
+	if !hash.IsZero() {
+		return nil, fmt.Errorf("legacy genesis block has priori '%v'", hash)
+	}
+
+	return blocks, nil
+}
+
+// migrateLegacyBlocks rewrites a chain stored in the legacy block format into the current block format.
+// Every Block is rehashed from the Genesis Block with its Transactions, whose IDs are regenerated and
+// whose inputs are remapped to the regenerated IDs, and is mined again for its original target. The
+// legacy Blocks are replaced by the rewritten Blocks and the height index in a single database Batch,
+// along with the chain head at the legacy key, so a crash during the migration leaves the legacy chain.
+// The UTXO set and the other indexes are rebuilt by finishMigration. Does nothing for the current format.
+func (chain *ChainManager) migrateLegacyBlocks() error {
+	legacy, err := chain.hasLegacyBlocks()
+	if err != nil || !legacy {
+		return err
+	}
+
+	blocks, err := chain.readLegacyChain()
+	if err != nil {
+		return err
+	}
+
+	chain.opts.Logger.Infof("Migrating %v blocks from the legacy block format.", len(blocks))
+
+	if err := chain.db.Batch(func() error {
+		// Map the legacy IDs of the Transactions to their regenerated IDs
+		ids := make(map[common.Hash]common.Hash)
+		priori := common.NullHash()
+
+		for _, old := range blocks {
+			block := migrateLegacyBlock(old, priori, ids, chain.opts.InitialBits)
+
+			if err := chain.db.DeleteEntry(old.BlockHash.Bytes()); err != nil {
+				return fmt.Errorf("legacy block remove failed: %w", err)
+			}
+
+			if err := chain.storeBlock(block); err != nil {
+				return err
+			}
+
+			if err := chain.indexBlock(block); err != nil {
+				return err
+			}
+
+			priori = block.BlockHash
+		}
+
+		chain.Head, chain.tip = priori, nil
+		return chain.db.SetEntry(ChainHeadKey, chain.Head.Bytes())
+	}); err != nil {
+		return fmt.Errorf("legacy block migration failed: %w", err)
+	}
+
+	chain.opts.Logger.Infof("Migrated legacy blocks to head '%v'.", chain.Head)
+	return nil
+}
+
+// migrateLegacyBlock returns the Block of the current format for a legacy Block that extends the given priori hash.
+// The IDs of its Transactions are regenerated after their inputs are remapped with ids, which is updated with them.
+// Legacy Blocks without a target are mined for the given compact target.
+func migrateLegacyBlock(old *legacyBlock, priori common.Hash, ids map[common.Hash]common.Hash, bits uint32) *Block {
+	for _, txn := range old.BlockTxns {
+		if txn.IsCoinbase() {
+			txn.Height = old.BlockHeight
+		} else {
+			for idx, input := range txn.Inputs {
+				if id, exists := ids[input.ID]; exists {
+					txn.Inputs[idx].ID = id
+				}
+			}
+		}
+
+		id := txn.ID
+		txn.SetID()
+		ids[id] = txn.ID
+	}
+
+	if old.BlockHeader.Target != nil && old.BlockHeader.Target.Sign() > 0 {
+		bits = TargetToCompact(old.BlockHeader.Target)
+	}
+
+	block := &Block{
+		BlockHeader: BlockHeader{priori, GenerateSummary(old.BlockTxns), old.BlockHeader.Timestamp, bits, 0},
+		BlockHeight: old.BlockHeight,
+		BlockTxns:   old.BlockTxns,
+	}
+
+	block.BlockHash = block.BlockHeader.Mint()
+	return block
+}
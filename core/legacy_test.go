@@ -0,0 +1,173 @@
+package core
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/db"
+)
+
+// legacyTransaction returns a Transaction of the legacy block format, whose ID is the hash of its gob encoding
+func legacyTransaction(t *testing.T, inputs []TxInput, outputs ...TxOutput) *Transaction {
+	t.Helper()
+
+	txn := &Transaction{ID: common.NullHash(), Inputs: inputs, Outputs: outputs}
+
+	data, err := common.GobEncode(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txn.ID = sha256.Sum256(data)
+	return txn
+}
+
+// writeLegacyChain stores a chain of the legacy block format in an empty database directory, as it was written
+// before the height index: gob encoded Blocks stored under their hashes, with the chain head and height stored
+// at the legacy keys. The genesis coinbase pays the miner address, which pays "alice" in the second Block,
+// who pays "bob" in the third Block. Returns the legacy Blocks from the Genesis Block.
+func writeLegacyChain(t *testing.T) []*legacyBlock {
+	t.Helper()
+	resetDatabase(t)
+	t.Cleanup(func() { resetDatabase(t) })
+
+	miner := common.MinerAddress()
+	coinbase := legacyTransaction(t, []TxInput{{common.NullHash(), -1, "Genesis Block Coinbase Transaction"}}, TxOutput{Value: 100, PubKey: miner})
+	pay := legacyTransaction(t, []TxInput{{coinbase.ID, 0, miner}}, TxOutput{Value: 60, PubKey: "alice"}, TxOutput{Value: 40, PubKey: miner})
+	spend := legacyTransaction(t, []TxInput{{pay.ID, 0, "alice"}}, TxOutput{Value: 60, PubKey: "bob"})
+
+	// The legacy Blocks are given an easy target so that they are migrated quickly
+	target := new(big.Int).Lsh(big.NewInt(1), 255)
+
+	var blocks []*legacyBlock
+	priori := common.NullHash()
+
+	for height, txns := range []Transactions{{coinbase}, {pay}, {spend}} {
+		header := legacyBlockHeader{priori, common.Hash256([]byte{byte(height)}), 1600000000 + int64(height)*10, target, 0}
+
+		data, err := common.GobEncode(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		block := &legacyBlock{header, int64(height), txns, common.Hash256(data)}
+		blocks, priori = append(blocks, block), block.BlockHash
+	}
+
+	database, err := db.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer database.Close()
+
+	for _, block := range blocks {
+		data, err := common.GobEncode(block)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := database.SetEntry(block.BlockHash.Bytes(), data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	height, err := common.GobEncode(int64(len(blocks)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := database.SetEntry(ChainHeadKey, priori.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := database.SetEntry(ChainHeightKey, height); err != nil {
+		t.Fatal(err)
+	}
+
+	return blocks
+}
+
+// TestMigrateLegacyBlocks checks that a chain of the legacy block format is rewritten into the current
+// block format when it is loaded, with its Transactions, balances and indexes intact
+func TestMigrateLegacyBlocks(t *testing.T) {
+	legacy := writeLegacyChain(t)
+	chain := openTestChain(t, DefaultOptions())
+
+	if chain.Height != int64(len(legacy)) {
+		t.Fatalf("expected the migrated chain height to be %v, got %v", len(legacy), chain.Height)
+	}
+
+	if err := chain.VerifyChain(); err != nil {
+		t.Fatalf("expected the migrated chain to be valid, got %v", err)
+	}
+
+	// The migrated Blocks keep their heights, timestamps and targets, and are linked by their new hashes
+	for height, old := range legacy {
+		block, err := chain.GetBlockByHeight(int64(height))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if block.Timestamp != old.BlockHeader.Timestamp || block.Bits != TargetToCompact(old.BlockHeader.Target) {
+			t.Fatalf("expected the block at height %v to keep its timestamp and target", height)
+		}
+
+		if exists, err := chain.HasBlock(old.BlockHash); err != nil || exists {
+			t.Fatalf("expected the legacy block at height %v to be removed, got %v", height, err)
+		}
+	}
+
+	// The inputs of the migrated Transactions refer to the new IDs of the Transactions they spend
+	tip, err := chain.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parent, err := chain.FindTransaction(tip.BlockTxns[0].Inputs[0].ID)
+	if err != nil {
+		t.Fatalf("expected the spent transaction to be indexed by its new id, got %v", err)
+	}
+
+	if parent.Outputs[0].PubKey != "alice" {
+		t.Fatalf("expected the spent transaction to pay alice, got %v", parent.Outputs[0].PubKey)
+	}
+
+	balances, err := chain.AllBalances()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if balances["bob"] != 60 || balances[common.MinerAddress()] != 40 || len(balances) != 2 {
+		t.Fatalf("expected the migrated balances of bob and the miner to be 60 and 40, got %v", balances)
+	}
+
+	audit, err := chain.AuditUTXO()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !audit.Consistent() {
+		t.Fatalf("expected the utxo set of the migrated chain to be consistent, got %v discrepancies", len(audit.Discrepancies))
+	}
+}
+
+// TestMigrateLegacyBlocksReopen checks that a migrated chain loads again without another migration
+func TestMigrateLegacyBlocksReopen(t *testing.T) {
+	writeLegacyChain(t)
+
+	chain, err := NewChainManager(DefaultOptions())
+	if err != nil {
+		t.Fatalf("failed to open legacy chain: %v", err)
+	}
+
+	state := chain.State()
+	chain.Stop()
+
+	chain = openTestChain(t, DefaultOptions())
+	if reopened := chain.State(); !reopened.Head.Equal(state.Head) || reopened.Height != state.Height || reopened.Work.Cmp(state.Work) != 0 {
+		t.Fatalf("expected the reopened chain state %+v to equal the migrated chain state %+v", reopened, state)
+	}
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"io"
+	"log"
+)
+
+// LogLevel represents the verbosity of a Logger
+type LogLevel uint8
+
+const (
+	// LogSilent disables all logging
+	LogSilent LogLevel = iota
+	// LogInfo enables logging of significant chain events
+	LogInfo
+	// LogDebug enables logging of all chain events
+	LogDebug
+)
+
+// Logger is an interface for the logging of chain events by a ChainManager
+type Logger interface {
+	// Infof logs a significant chain event
+	Infof(format string, args ...any)
+
+	// Debugf logs a chain event for debugging
+	Debugf(format string, args ...any)
+}
+
+// NewLogger returns a Logger that writes to out, for events up to the given LogLevel
+func NewLogger(out io.Writer, level LogLevel) Logger {
+	return &levelLogger{log.New(out, "", log.LstdFlags), level}
+}
+
+// NopLogger returns a Logger that discards all events
+func NopLogger() Logger {
+	return &levelLogger{log.New(io.Discard, "", 0), LogSilent}
+}
+
+// levelLogger is a Logger that filters events by a LogLevel
+type levelLogger struct {
+	logger *log.Logger
+	level  LogLevel
+}
+
+// Infof implements the Logger interface for levelLogger
+func (logger *levelLogger) Infof(format string, args ...any) {
+	if logger.level >= LogInfo {
+		logger.logger.Printf(format, args...)
+	}
+}
+
+// Debugf implements the Logger interface for levelLogger
+func (logger *levelLogger) Debugf(format string, args ...any) {
+	if logger.level >= LogDebug {
+		logger.logger.Printf(format, args...)
+	}
+}
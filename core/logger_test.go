@@ -0,0 +1,69 @@
+package core
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestNoStdoutOutput checks that a chain without a Logger writes nothing to the standard output
+// when it is initialized and when Blocks are added to it
+func TestNoStdoutOutput(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = writer
+
+	func() {
+		defer func() { os.Stdout = stdout }()
+
+		opts := DefaultOptions()
+		opts.Logger = nil
+
+		chain := newChain(t, opts)
+		if err := chain.MineBlock(nil, ""); err != nil {
+			t.Errorf("failed to mine block: %v", err)
+		}
+	}()
+
+	writer.Close()
+
+	output, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(output) != 0 {
+		t.Fatalf("expected no output on stdout, got %q", output)
+	}
+}
+
+// TestLoggerLevels checks that a Logger only writes the events up to its LogLevel
+func TestLoggerLevels(t *testing.T) {
+	tests := []struct {
+		level       LogLevel
+		info, debug bool
+	}{
+		{LogSilent, false, false},
+		{LogInfo, true, false},
+		{LogDebug, true, true},
+	}
+
+	for _, test := range tests {
+		var buffer bytes.Buffer
+
+		logger := NewLogger(&buffer, test.level)
+		logger.Infof("info event")
+		logger.Debugf("debug event")
+
+		output := buffer.String()
+		if strings.Contains(output, "info event") != test.info || strings.Contains(output, "debug event") != test.debug {
+			t.Fatalf("unexpected output for log level %v: %q", test.level, output)
+		}
+	}
+}
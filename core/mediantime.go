@@ -0,0 +1,37 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MedianTimeBlocks is the number of Blocks over which the median time past is computed for Block validation
+const MedianTimeBlocks = 11
+
+// MedianTimePast returns the median timestamp of the last n Blocks of the chain, ending at the chain head.
+// Fewer Blocks are used if the chain is shorter than n. The upper median is used for an even number of Blocks.
+func (chain *ChainManager) MedianTimePast(n int) (int64, error) {
+	if n < 1 {
+		return 0, fmt.Errorf("invalid block count %v", n)
+	}
+
+	// Collect the timestamps of the last n Blocks
+	timestamps := make([]int64, 0, n)
+
+	iter := chain.NewIterator()
+	for len(timestamps) < n && !iter.Done() {
+		header, err := iter.NextHeader()
+		if err != nil {
+			return 0, fmt.Errorf("chain header retrieve failed: %w", err)
+		}
+
+		timestamps = append(timestamps, header.Timestamp)
+	}
+
+	if len(timestamps) == 0 {
+		return 0, fmt.Errorf("chain has no blocks")
+	}
+
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+	return timestamps[len(timestamps)/2], nil
+}
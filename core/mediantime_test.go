@@ -0,0 +1,73 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// timedBlock returns a Block with a coinbase that extends the chain head, mined at the given timestamp
+func timedBlock(t *testing.T, chain *ChainManager, timestamp int64) *Block {
+	t.Helper()
+
+	bits, err := chain.nextBits()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block := NewBlock(Transactions{coinbaseOf(chain, TxOutput{Value: BlockReward, PubKey: common.MinerAddress()})}, chain.Head, chain.Height, bits)
+	block.Timestamp = timestamp
+	block.BlockHash = block.BlockHeader.Mint()
+
+	return block
+}
+
+// TestMedianTimePast checks the median timestamp of the last Blocks of the chain,
+// and that Blocks older than the median time past are rejected even if they are newer than their parent
+func TestMedianTimePast(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	genesis := DefaultGenesisConfig().Timestamp
+
+	// Append Blocks directly so that the chain head is older than the median time past
+	for _, offset := range []int64{1000, 2000, 3000, 4000, 100} {
+		if err := chain.appendBlock(timedBlock(t, chain, genesis+offset)); err != nil {
+			t.Fatalf("failed to append block: %v", err)
+		}
+	}
+
+	tests := []struct {
+		blocks int
+		want   int64
+	}{
+		{1, genesis + 100},
+		{2, genesis + 4000},
+		{3, genesis + 3000},
+		{MedianTimeBlocks, genesis + 2000},
+	}
+
+	for _, test := range tests {
+		median, err := chain.MedianTimePast(test.blocks)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if median != test.want {
+			t.Fatalf("expected the median time past of %v blocks to be %v, got %v", test.blocks, test.want, median)
+		}
+	}
+
+	if _, err := chain.MedianTimePast(0); err == nil {
+		t.Fatalf("expected an invalid block count to be rejected")
+	}
+
+	// The Block is newer than its parent but older than the median time past
+	early := timedBlock(t, chain, genesis+500)
+	if err := chain.AcceptBlock(early); err == nil || !strings.Contains(err.Error(), "before median time past") {
+		t.Fatalf("expected the block to be before the median time past, got %v", err)
+	}
+
+	if err := chain.AcceptBlock(timedBlock(t, chain, genesis+2000)); err != nil {
+		t.Fatalf("expected the block at the median time past to be accepted, got %v", err)
+	}
+}
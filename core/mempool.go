@@ -0,0 +1,356 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/anee769/essensio/common"
+)
+
+// Mempool is a pool of verified Transactions pending inclusion in a Block.
+// It is safe for concurrent use.
+type Mempool struct {
+	mutex sync.RWMutex
+
+	// Represents the pending Transactions indexed by their ID
+	txns map[common.Hash]*Transaction
+	// Represents the IDs of the pending Transactions in order of arrival
+	order []common.Hash
+	// Represents the outputs spent by pending Transactions mapped to the ID of the spender
+	spent map[Outpoint]common.Hash
+	// Represents the fees paid by the pending Transactions indexed by their ID
+	fees map[common.Hash]int
+}
+
+// NewMempool returns a new empty Mempool
+func NewMempool() *Mempool {
+	return &Mempool{
+		txns:  make(map[common.Hash]*Transaction),
+		spent: make(map[Outpoint]common.Hash),
+		fees:  make(map[common.Hash]int),
+	}
+}
+
+// Size returns the number of pending Transactions in the Mempool
+func (pool *Mempool) Size() int {
+	pool.mutex.RLock()
+	defer pool.mutex.RUnlock()
+
+	return len(pool.txns)
+}
+
+// Get returns the pending Transaction with the given ID and whether it exists in the Mempool
+func (pool *Mempool) Get(id common.Hash) (*Transaction, bool) {
+	pool.mutex.RLock()
+	defer pool.mutex.RUnlock()
+
+	txn, ok := pool.txns[id]
+	return txn, ok
+}
+
+// Transactions returns all the pending Transactions in the Mempool in order of arrival
+func (pool *Mempool) Transactions() Transactions {
+	pool.mutex.RLock()
+	defer pool.mutex.RUnlock()
+
+	txns := make(Transactions, 0, len(pool.order))
+	for _, id := range pool.order {
+		txns = append(txns, pool.txns[id])
+	}
+
+	return txns
+}
+
+// Spender returns the ID of the pending Transaction that spends the output referenced by the Outpoint,
+// and whether the output is spent by any pending Transaction
+func (pool *Mempool) Spender(outpoint Outpoint) (common.Hash, bool) {
+	pool.mutex.RLock()
+	defer pool.mutex.RUnlock()
+
+	spender, spent := pool.spent[outpoint]
+	return spender, spent
+}
+
+// Fee returns the fee paid by the pending Transaction with the given ID and whether it exists in the Mempool
+func (pool *Mempool) Fee(id common.Hash) (int, bool) {
+	pool.mutex.RLock()
+	defer pool.mutex.RUnlock()
+
+	fee, ok := pool.fees[id]
+	return fee, ok
+}
+
+// parentOutputs returns the outputs of the pending Transactions that are spent from by a Transaction,
+// as the pendingOutputs of a Block at the given height, which would include them before the Transaction
+func (pool *Mempool) parentOutputs(txn *Transaction, height int64) pendingOutputs {
+	pool.mutex.RLock()
+	defer pool.mutex.RUnlock()
+
+	pending := make(pendingOutputs)
+	for _, input := range txn.Inputs {
+		if parent, exists := pool.txns[input.ID]; exists {
+			pending.add(parent, height)
+		}
+	}
+
+	return pending
+}
+
+// Descendants returns the IDs of the pending Transactions that depend on the pending Transaction with the
+// given ID, by spending its outputs or those of another descendant. Descendants are ordered so that every
+// Transaction follows the Transactions it spends from. The Transaction itself is not included.
+func (pool *Mempool) Descendants(id common.Hash) []common.Hash {
+	pool.mutex.RLock()
+	defer pool.mutex.RUnlock()
+
+	return pool.descendants(id)
+}
+
+// descendants returns the IDs of the pending Transactions that depend on any of the Transactions with the given
+// IDs, excluding the given Transactions. Must be called with the lock held.
+func (pool *Mempool) descendants(ids ...common.Hash) []common.Hash {
+	seen := make(map[common.Hash]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+
+	var found []common.Hash
+	queue := append([]common.Hash{}, ids...)
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		txn, exists := pool.txns[id]
+		if !exists {
+			continue
+		}
+
+		for idx := range txn.Outputs {
+			child, spent := pool.spent[Outpoint{id, idx}]
+			if !spent || seen[child] {
+				continue
+			}
+
+			seen[child] = true
+			found = append(found, child)
+			queue = append(queue, child)
+		}
+	}
+
+	return found
+}
+
+// Add adds a Transaction that pays the given fee to the Mempool. The Transaction is expected to be verified.
+// Returns an error if the Transaction is already pending or spends
+// an output that is already spent by a pending Transaction.
+func (pool *Mempool) Add(txn *Transaction, fee int) error {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	conflicts, err := pool.conflicts(txn)
+	if err != nil {
+		return err
+	}
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("output already spent by pending transaction '%v'", conflicts[0])
+	}
+
+	pool.add(txn, fee)
+	return nil
+}
+
+// Replace adds a Transaction that pays the given fee to the Mempool, replacing any pending Transactions
+// that spend the same outputs, along with their Descendants which would otherwise spend missing outputs.
+// The fee must exceed the total fee of the replaced Transactions by at least minBump, and by at least 1,
+// and the Transaction must not spend an output of a replaced Transaction.
+// Returns the IDs of the replaced Transactions.
+func (pool *Mempool) Replace(txn *Transaction, fee, minBump int) ([]common.Hash, error) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	conflicts, err := pool.conflicts(txn)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts = append(conflicts, pool.descendants(conflicts...)...)
+
+	// Check that the Transaction does not spend from a replaced Transaction, whose outputs would be missing
+	replacedIDs := make(map[common.Hash]bool, len(conflicts))
+	for _, id := range conflicts {
+		replacedIDs[id] = true
+	}
+
+	for idx, input := range txn.Inputs {
+		if replacedIDs[input.ID] {
+			return nil, fmt.Errorf("input %v spends an output of replaced pending transaction '%v'", idx, input.ID)
+		}
+	}
+
+	// Check that the fee is sufficiently higher than that of the replaced Transactions
+	replaced := 0
+	for _, id := range conflicts {
+		replaced += pool.fees[id]
+	}
+
+	if minBump < 1 {
+		minBump = 1
+	}
+
+	if len(conflicts) > 0 && fee < replaced+minBump {
+		return nil, fmt.Errorf("fee %v does not exceed the fee %v of conflicting pending transactions by the minimum bump %v", fee, replaced, minBump)
+	}
+
+	pool.remove(conflicts...)
+	pool.add(txn, fee)
+
+	return conflicts, nil
+}
+
+// conflicts returns the IDs of the pending Transactions that spend the same outputs as a Transaction.
+// Returns an error if the Transaction is already pending. Must be called with the lock held.
+func (pool *Mempool) conflicts(txn *Transaction) ([]common.Hash, error) {
+	if _, exists := pool.txns[txn.ID]; exists {
+		return nil, fmt.Errorf("transaction '%v' already in mempool", txn.ID)
+	}
+
+	var conflicts []common.Hash
+	seen := make(map[common.Hash]bool)
+
+	for _, input := range txn.Inputs {
+		if spender, conflict := pool.spent[Outpoint{input.ID, input.Out}]; conflict && !seen[spender] {
+			conflicts = append(conflicts, spender)
+			seen[spender] = true
+		}
+	}
+
+	return conflicts, nil
+}
+
+// add adds a Transaction without any conflicts to the Mempool. Must be called with the lock held.
+func (pool *Mempool) add(txn *Transaction, fee int) {
+	for _, input := range txn.Inputs {
+		pool.spent[Outpoint{input.ID, input.Out}] = txn.ID
+	}
+
+	pool.txns[txn.ID] = txn
+	pool.fees[txn.ID] = fee
+	pool.order = append(pool.order, txn.ID)
+}
+
+// Remove removes the Transactions with the given IDs from the Mempool, if they exist
+func (pool *Mempool) Remove(ids ...common.Hash) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	pool.remove(ids...)
+}
+
+// remove removes the Transactions with the given IDs. Must be called with the lock held.
+func (pool *Mempool) remove(ids ...common.Hash) {
+	removed := 0
+	for _, id := range ids {
+		txn, exists := pool.txns[id]
+		if !exists {
+			continue
+		}
+
+		for _, input := range txn.Inputs {
+			delete(pool.spent, Outpoint{input.ID, input.Out})
+		}
+
+		delete(pool.txns, id)
+		delete(pool.fees, id)
+		removed++
+	}
+
+	if removed == 0 {
+		return
+	}
+
+	// Filter the removed Transactions from the arrival order
+	order := pool.order[:0]
+	for _, id := range pool.order {
+		if _, exists := pool.txns[id]; exists {
+			order = append(order, id)
+		}
+	}
+
+	pool.order = order
+}
+
+// removeBlock removes the Transactions of a Block from the Mempool, along with any pending
+// Transactions that spend the same outputs as them and the Descendants of those conflicts.
+// Pending Transactions that spend the outputs of the Block remain valid and are kept.
+func (pool *Mempool) removeBlock(block *Block) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	var ids, conflicts []common.Hash
+	for _, txn := range block.BlockTxns {
+		ids = append(ids, txn.ID)
+
+		for _, input := range txn.Inputs {
+			if spender, conflict := pool.spent[Outpoint{input.ID, input.Out}]; conflict && !spender.Equal(txn.ID) {
+				conflicts = append(conflicts, spender)
+			}
+		}
+	}
+
+	ids = append(ids, conflicts...)
+	ids = append(ids, pool.descendants(conflicts...)...)
+
+	pool.remove(ids...)
+}
+
+// Mempool returns the Mempool of pending Transactions for the chain
+func (chain *ChainManager) Mempool() *Mempool {
+	return chain.mempool
+}
+
+// SubmitTransaction checks a Transaction against the chain and adds it to the Mempool.
+// The Transaction may spend the outputs of pending Transactions, which must be mined before it.
+// If Options.ReplaceByFee is set, a Transaction that spends the same outputs as pending
+// Transactions replaces them if it pays a sufficiently higher fee. A Transaction that spends
+// outputs of Transactions that are neither on the chain nor pending is held in the OrphanPool
+// until they are mined or submitted, when it is submitted again.
+func (chain *ChainManager) SubmitTransaction(txn *Transaction) error {
+	// Hold Transactions that spend outputs of unknown Transactions as orphans
+	if !txn.IsCoinbase() {
+		missing, err := chain.missingParents(txn)
+		if err != nil {
+			return fmt.Errorf("parent transaction lookup failed: %w", err)
+		}
+
+		if len(missing) > 0 {
+			return chain.holdOrphan(txn, missing)
+		}
+	}
+
+	// Resolve the inputs that spend outputs of pending Transactions from the Mempool
+	fee, err := chain.checkTransaction(txn, chain.mempool.parentOutputs(txn, chain.Height))
+	if err != nil {
+		return fmt.Errorf("transaction check failed: %w", err)
+	}
+
+	if !chain.opts.ReplaceByFee {
+		if err := chain.mempool.Add(txn, fee); err != nil {
+			return err
+		}
+	} else {
+		replaced, err := chain.mempool.Replace(txn, fee, chain.opts.MinFeeBump)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range replaced {
+			chain.opts.Logger.Debugf("Replaced pending transaction '%v' with '%v'.", id, txn.ID)
+		}
+	}
+
+	// Submit the orphans that were waiting on the Transaction
+	chain.promoteOrphansOf(txn.ID)
+	return nil
+}
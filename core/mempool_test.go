@@ -0,0 +1,269 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// equalHashes returns whether two sets of hashes are equal and in the same order
+func equalHashes(a, b []common.Hash) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for idx := range a {
+		if !a[idx].Equal(b[idx]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// submitFamily submits a parent Transaction that spends the genesis reward,
+// a child that spends the parent and a grandchild that spends the child
+func submitFamily(t *testing.T, chain *ChainManager) (parent, child, grandchild *Transaction) {
+	t.Helper()
+
+	parent = signedTransaction(t, common.MinerAddress(), []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: 90, PubKey: "child"})
+	child = signedTransaction(t, "child", []Outpoint{{parent.ID, 0}}, TxOutput{Value: 80, PubKey: "grandchild"})
+	grandchild = signedTransaction(t, "grandchild", []Outpoint{{child.ID, 0}}, TxOutput{Value: 70, PubKey: "recipient"})
+
+	for _, txn := range []*Transaction{parent, child, grandchild} {
+		if err := chain.SubmitTransaction(txn); err != nil {
+			t.Fatalf("failed to submit transaction '%v': %v", txn.ID, err)
+		}
+	}
+
+	return parent, child, grandchild
+}
+
+// TestMempoolDescendants checks that the Descendants of a pending Transaction are
+// the pending Transactions that spend from it, directly or through another descendant
+func TestMempoolDescendants(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	parent, child, grandchild := submitFamily(t, chain)
+
+	if size := chain.Mempool().Size(); size != 3 {
+		t.Fatalf("expected 3 pending transactions, got %v", size)
+	}
+
+	if fee, _ := chain.Mempool().Fee(grandchild.ID); fee != 10 {
+		t.Fatalf("expected a fee of 10 for the grandchild, got %v", fee)
+	}
+
+	tests := []struct {
+		name        string
+		id          common.Hash
+		descendants []common.Hash
+	}{
+		{"parent", parent.ID, []common.Hash{child.ID, grandchild.ID}},
+		{"child", child.ID, []common.Hash{grandchild.ID}},
+		{"grandchild", grandchild.ID, nil},
+		{"unknown", common.NullHash(), nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if descendants := chain.Mempool().Descendants(test.id); !equalHashes(descendants, test.descendants) {
+				t.Fatalf("expected descendants %v, got %v", test.descendants, descendants)
+			}
+		})
+	}
+}
+
+// TestMinePendingFamily checks that pending Transactions that spend each other are mined in a single Block,
+// with the fees of all of them paid to the coinbase
+func TestMinePendingFamily(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	parent, child, grandchild := submitFamily(t, chain)
+
+	assembly, err := chain.MinePending("")
+	if err != nil {
+		t.Fatalf("failed to mine pending transactions: %v", err)
+	}
+
+	if expected := []common.Hash{parent.ID, child.ID, grandchild.ID}; !equalHashes(assembly.Included, expected) {
+		t.Fatalf("expected included transactions %v, got %v", expected, assembly.Included)
+	}
+
+	if value := assembly.Block.BlockTxns[0].Outputs[0].Value; value != BlockReward+30 {
+		t.Fatalf("expected a coinbase value of %v, got %v", BlockReward+30, value)
+	}
+
+	if size := chain.Mempool().Size(); size != 0 {
+		t.Fatalf("expected an empty mempool after mining, got %v pending transactions", size)
+	}
+}
+
+// TestMinePendingMissingParent checks that a pending Transaction whose parent is rejected from the Block
+// is also rejected, rather than failing the Block
+func TestMinePendingMissingParent(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	parent, child, grandchild := submitFamily(t, chain)
+
+	// Drop the parent from the Mempool without its descendants
+	chain.Mempool().Remove(parent.ID)
+
+	assembly, err := chain.MinePending("")
+	if err != nil {
+		t.Fatalf("failed to mine pending transactions: %v", err)
+	}
+
+	if len(assembly.Included) != 0 || len(assembly.Rejected) != 2 {
+		t.Fatalf("expected the child and grandchild to be rejected, got %v included and %v rejected", len(assembly.Included), len(assembly.Rejected))
+	}
+
+	if !assembly.Rejected[0].ID.Equal(child.ID) || !assembly.Rejected[1].ID.Equal(grandchild.ID) {
+		t.Fatalf("expected the child and grandchild to be rejected in order")
+	}
+}
+
+// TestSubmitTransactionPromotesOrphan checks that an orphan Transaction is submitted to the Mempool
+// once its parent is submitted
+func TestSubmitTransactionPromotesOrphan(t *testing.T) {
+	opts := testChainOptions(0)
+	opts.Options.MaxOrphans = 10
+
+	chain := newTestChain(t, opts)
+
+	parent := signedTransaction(t, common.MinerAddress(), []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: 90, PubKey: "child"})
+	child := signedTransaction(t, "child", []Outpoint{{parent.ID, 0}}, TxOutput{Value: 80, PubKey: "recipient"})
+
+	if err := chain.SubmitTransaction(child); err != nil {
+		t.Fatalf("failed to submit orphan: %v", err)
+	}
+
+	if !chain.Orphans().Has(child.ID) {
+		t.Fatalf("expected the child to be held as an orphan")
+	}
+
+	if err := chain.SubmitTransaction(parent); err != nil {
+		t.Fatalf("failed to submit parent: %v", err)
+	}
+
+	if chain.Orphans().Has(child.ID) {
+		t.Fatalf("expected the child to be promoted from the orphan pool")
+	}
+
+	if _, pending := chain.Mempool().Get(child.ID); !pending {
+		t.Fatalf("expected the child to be pending")
+	}
+}
+
+// rbfChain returns a test chain with replace-by-fee enabled and a minimum fee bump of 5
+func rbfChain(t *testing.T) *ChainManager {
+	opts := testChainOptions(0)
+	opts.Options.ReplaceByFee = true
+	opts.Options.MinFeeBump = 5
+
+	return newTestChain(t, opts)
+}
+
+// TestReplaceByFee checks that a pending Transaction is replaced by a Transaction that spends the same output
+// with a sufficiently higher fee, and that the descendants of the replaced Transaction are evicted with it
+func TestReplaceByFee(t *testing.T) {
+	chain := rbfChain(t)
+	parent, child, grandchild := submitFamily(t, chain)
+
+	// The family pays a total fee of 30, which the replacement must exceed by the minimum bump
+	replacement := signedTransaction(t, common.MinerAddress(), []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: 65, PubKey: "recipient"})
+	if err := chain.SubmitTransaction(replacement); err != nil {
+		t.Fatalf("failed to replace pending transaction: %v", err)
+	}
+
+	for _, txn := range []*Transaction{parent, child, grandchild} {
+		if _, pending := chain.Mempool().Get(txn.ID); pending {
+			t.Fatalf("expected transaction '%v' to be replaced", txn.ID)
+		}
+	}
+
+	if _, pending := chain.Mempool().Get(replacement.ID); !pending || chain.Mempool().Size() != 1 {
+		t.Fatalf("expected only the replacement to be pending")
+	}
+
+	if spender, _ := chain.Mempool().Spender(genesisOutpoint(t, chain)); !spender.Equal(replacement.ID) {
+		t.Fatalf("expected the replacement to spend the genesis output, got '%v'", spender)
+	}
+}
+
+// TestReplaceByFeeInsufficientBump checks that a conflicting Transaction that does not raise the fee
+// by the minimum bump is rejected, and the pending Transaction is kept
+func TestReplaceByFeeInsufficientBump(t *testing.T) {
+	chain := rbfChain(t)
+	genesis := genesisOutpoint(t, chain)
+
+	original := signedTransaction(t, common.MinerAddress(), []Outpoint{genesis}, TxOutput{Value: 90, PubKey: "recipient"})
+	if err := chain.SubmitTransaction(original); err != nil {
+		t.Fatalf("failed to submit transaction: %v", err)
+	}
+
+	// A fee of 14 exceeds the fee of 10 by less than the minimum bump of 5
+	bump := signedTransaction(t, common.MinerAddress(), []Outpoint{genesis}, TxOutput{Value: 86, PubKey: "recipient"})
+	if err := chain.SubmitTransaction(bump); err == nil {
+		t.Fatalf("expected a replacement with an insufficient fee bump to be rejected")
+	}
+
+	if _, pending := chain.Mempool().Get(original.ID); !pending || chain.Mempool().Size() != 1 {
+		t.Fatalf("expected only the original transaction to be pending")
+	}
+
+	// A fee of 15 meets the minimum bump
+	bump = signedTransaction(t, common.MinerAddress(), []Outpoint{genesis}, TxOutput{Value: 85, PubKey: "recipient"})
+	if err := chain.SubmitTransaction(bump); err != nil {
+		t.Fatalf("failed to replace pending transaction: %v", err)
+	}
+
+	if _, pending := chain.Mempool().Get(original.ID); pending {
+		t.Fatalf("expected the original transaction to be replaced")
+	}
+}
+
+// TestReplaceByFeeSpendsReplaced checks that a replacement that spends an output of a Transaction it would replace
+// is rejected, so that no pending Transaction is left spending from a Transaction that is no longer pending
+func TestReplaceByFeeSpendsReplaced(t *testing.T) {
+	chain := rbfChain(t)
+
+	parent := signedTransaction(t, common.MinerAddress(), []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: 40, PubKey: "fund"}, TxOutput{Value: 50, PubKey: "fund"})
+	child := signedTransaction(t, "fund", []Outpoint{{parent.ID, 0}}, TxOutput{Value: 30, PubKey: "fund"})
+
+	for _, txn := range []*Transaction{parent, child} {
+		if err := chain.SubmitTransaction(txn); err != nil {
+			t.Fatalf("failed to submit transaction '%v': %v", txn.ID, err)
+		}
+	}
+
+	// The replacement conflicts with the child over the output of the parent, while spending the output of the child
+	replacement := signedTransaction(t, "fund", []Outpoint{{parent.ID, 0}, {child.ID, 0}}, TxOutput{Value: 10, PubKey: "recipient"})
+	if err := chain.SubmitTransaction(replacement); err == nil {
+		t.Fatalf("expected a replacement spending from a replaced transaction to be rejected")
+	}
+
+	for _, txn := range []*Transaction{parent, child} {
+		if _, pending := chain.Mempool().Get(txn.ID); !pending {
+			t.Fatalf("expected transaction '%v' to remain pending", txn.ID)
+		}
+	}
+
+	if _, pending := chain.Mempool().Get(replacement.ID); pending || chain.Mempool().Size() != 2 {
+		t.Fatalf("expected the replacement not to be pending")
+	}
+}
+
+// TestReplaceByFeeDisabled checks that a conflicting Transaction is rejected as a double spend
+// without replace-by-fee, regardless of its fee
+func TestReplaceByFeeDisabled(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	genesis := genesisOutpoint(t, chain)
+
+	original := signedTransaction(t, common.MinerAddress(), []Outpoint{genesis}, TxOutput{Value: 99, PubKey: "recipient"})
+	if err := chain.SubmitTransaction(original); err != nil {
+		t.Fatalf("failed to submit transaction: %v", err)
+	}
+
+	conflict := signedTransaction(t, common.MinerAddress(), []Outpoint{genesis}, TxOutput{Value: 50, PubKey: "recipient"})
+	if err := chain.SubmitTransaction(conflict); err == nil {
+		t.Fatalf("expected a conflicting transaction to be rejected without replace-by-fee")
+	}
+}
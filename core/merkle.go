@@ -0,0 +1,120 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/anee769/essensio/common"
+)
+
+// MerkleStep represents a single step of a Merkle proof, which
+// is the sibling hash to combine with at one level of the tree.
+type MerkleStep struct {
+	// Hash is the hash of the sibling node
+	Hash common.Hash
+	// Left is whether the sibling node is on the left
+	Left bool
+}
+
+// merkleNode returns the hash of a parent node in the Merkle tree for its child nodes
+func merkleNode(left, right common.Hash) common.Hash {
+	return common.Hash256(append(append([]byte{}, left.Bytes()...), right.Bytes()...))
+}
+
+// merkleLevel returns the parent level of a level of the Merkle tree.
+// Nodes are paired from the left and an unpaired last node is carried up unchanged.
+func merkleLevel(level []common.Hash) []common.Hash {
+	parents := make([]common.Hash, 0, (len(level)+1)/2)
+
+	for idx := 0; idx < len(level); idx += 2 {
+		if idx+1 == len(level) {
+			parents = append(parents, level[idx])
+			break
+		}
+
+		parents = append(parents, merkleNode(level[idx], level[idx+1]))
+	}
+
+	return parents
+}
+
+// MerkleRoot returns the root of the Merkle tree over a set of leaf hashes.
+// The root of an empty set is the EmptySummary, which is the hash of no data.
+func MerkleRoot(leaves []common.Hash) common.Hash {
+	if len(leaves) == 0 {
+		return EmptySummary
+	}
+
+	level := leaves
+	for len(level) > 1 {
+		level = merkleLevel(level)
+	}
+
+	return level[0]
+}
+
+// BuildMerkleProof returns the Merkle proof for the leaf at the given index in a set of leaf hashes.
+// The proof is the path of sibling hashes from the leaf to the root.
+func BuildMerkleProof(leaves []common.Hash, index int) ([]MerkleStep, error) {
+	if index < 0 || index >= len(leaves) {
+		return nil, fmt.Errorf("leaf index %v out of range for %v leaves", index, len(leaves))
+	}
+
+	proof := make([]MerkleStep, 0)
+
+	level := leaves
+	for len(level) > 1 {
+		// An unpaired last node has no sibling at this level
+		if sibling := index ^ 1; sibling < len(level) {
+			proof = append(proof, MerkleStep{Hash: level[sibling], Left: sibling < index})
+		}
+
+		level, index = merkleLevel(level), index/2
+	}
+
+	return proof, nil
+}
+
+// VerifyMerkleProof returns whether a Merkle proof proves the inclusion of a leaf hash in the tree with the given root
+func VerifyMerkleProof(leaf common.Hash, proof []MerkleStep, root common.Hash) bool {
+	hash := leaf
+	for _, step := range proof {
+		if step.Left {
+			hash = merkleNode(step.Hash, hash)
+		} else {
+			hash = merkleNode(hash, step.Hash)
+		}
+	}
+
+	return hash.Equal(root)
+}
+
+// GetMerkleProof returns the Merkle proof for the inclusion of the Transaction with the given ID in its Block,
+// located with the transaction index. Also returns the Block and the index of the Transaction in it.
+// The proof can be verified with VerifyMerkleProof against the Block summary.
+func (chain *ChainManager) GetMerkleProof(id common.Hash) (*Block, int, []MerkleStep, error) {
+	block, err := chain.FindTransactionBlock(id)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	leaves := make([]common.Hash, len(block.BlockTxns))
+	index := -1
+
+	for idx, txn := range block.BlockTxns {
+		leaves[idx] = txn.Hash()
+		if txn.ID.Equal(id) {
+			index = idx
+		}
+	}
+
+	if index < 0 {
+		return nil, 0, nil, fmt.Errorf("transaction '%v' not found in block '%v'", id, block.BlockHash)
+	}
+
+	proof, err := BuildMerkleProof(leaves, index)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	return block, index, proof, nil
+}
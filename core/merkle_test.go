@@ -0,0 +1,83 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// testLeaves returns count distinct leaf hashes
+func testLeaves(count int) []common.Hash {
+	leaves := make([]common.Hash, count)
+	for idx := range leaves {
+		leaves[idx] = common.Hash256([]byte(fmt.Sprintf("leaf-%v", idx)))
+	}
+
+	return leaves
+}
+
+// TestMerkleProof checks that the proof of every leaf of trees with even and odd numbers of leaves
+// verifies against the root, and that it does not verify for another leaf or root
+func TestMerkleProof(t *testing.T) {
+	for count := 1; count <= 9; count++ {
+		leaves := testLeaves(count)
+		root := MerkleRoot(leaves)
+
+		for index := range leaves {
+			proof, err := BuildMerkleProof(leaves, index)
+			if err != nil {
+				t.Fatalf("%v leaves: failed to build proof for leaf %v: %v", count, index, err)
+			}
+
+			if !VerifyMerkleProof(leaves[index], proof, root) {
+				t.Fatalf("%v leaves: expected the proof of leaf %v to verify", count, index)
+			}
+
+			if VerifyMerkleProof(common.Hash256([]byte("other")), proof, root) {
+				t.Fatalf("%v leaves: expected the proof of leaf %v not to verify another leaf", count, index)
+			}
+
+			if VerifyMerkleProof(leaves[index], proof, MerkleRoot(testLeaves(count+1))) {
+				t.Fatalf("%v leaves: expected the proof of leaf %v not to verify another root", count, index)
+			}
+		}
+
+		if _, err := BuildMerkleProof(leaves, count); err == nil {
+			t.Fatalf("%v leaves: expected an out of range index to be rejected", count)
+		}
+	}
+
+	if !MerkleRoot(nil).Equal(EmptySummary) {
+		t.Fatalf("expected the root of no leaves to be the empty summary")
+	}
+}
+
+// TestGetMerkleProof checks that the proof of a Transaction on the chain verifies against the summary of its Block
+func TestGetMerkleProof(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(3))
+
+	block, err := chain.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for idx, txn := range block.BlockTxns {
+		found, index, proof, err := chain.GetMerkleProof(txn.ID)
+		if err != nil {
+			t.Fatalf("failed to get proof of transaction '%v': %v", txn.ID, err)
+		}
+
+		if !found.BlockHash.Equal(block.BlockHash) || index != idx {
+			t.Fatalf("expected transaction '%v' at index %v of the chain head, got %v of '%v'", txn.ID, idx, index, found.BlockHash)
+		}
+
+		if !VerifyMerkleProof(txn.Hash(), proof, block.Summary) {
+			t.Fatalf("expected the proof of transaction '%v' to verify against the block summary", txn.ID)
+		}
+	}
+
+	if _, _, _, err := chain.GetMerkleProof(common.Hash256([]byte("unknown"))); err == nil {
+		t.Fatalf("expected no proof for an unknown transaction")
+	}
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"time"
+
+	"github.com/anee769/essensio/common"
+)
+
+const (
+	// DefaultWriteRetries is the default number of retries of a failed database write
+	DefaultWriteRetries = 3
+	// DefaultWriteRetryDelay is the default delay before the first retry of a failed database write
+	DefaultWriteRetryDelay = 10 * time.Millisecond
+)
+
+// Options represents the configuration of a ChainManager
+type Options struct {
+	// InitialBits is the compact Proof of Work target used for the Genesis Block.
+	// It remains in effect until the first difficulty adjustment of the chain.
+	InitialBits uint32
+
+	// DifficultyAdjuster is the algorithm that retargets the PoW difficulty of the chain.
+	// Targets are never easier than InitialBits. The DefaultWindowAdjuster is used if it is nil.
+	DifficultyAdjuster DifficultyAdjuster
+
+	// Genesis is the configuration of the Genesis Block, used when a new chain is initialized
+	Genesis GenesisConfig
+
+	// MaxOutputValue is the maximum value of any Transaction output, including coinbase outputs.
+	// Outputs are not capped if it is 0.
+	MaxOutputValue int
+
+	// DustThreshold is the minimum value of any non-coinbase Transaction output.
+	// Outputs of any value are allowed if it is 0.
+	DustThreshold int
+
+	// MaxTxSize is the maximum serialized Size of any non-coinbase Transaction in bytes.
+	// Transactions of any size up to MaxTxDataSize are allowed if it is 0.
+	MaxTxSize int
+
+	// MaxAddressLength is the maximum length in bytes of the Addresses of Transaction outputs.
+	// Addresses of any length are allowed if it is 0, but their characters are always checked.
+	MaxAddressLength int
+
+	// CoinbaseMaturity is the number of confirmations a coinbase output requires before it can be spent.
+	// Coinbase outputs can be spent immediately if it is 0.
+	CoinbaseMaturity int64
+
+	// CoinbaseData is the default data tagged in the coinbase Transaction of mined Blocks.
+	// Must not be longer than MaxCoinbaseDataLength.
+	CoinbaseData string
+
+	// CoinbaseShares is the set of Addresses across which the block reward and fees of mined Blocks are split.
+	// They are paid to the miner address if it is empty.
+	CoinbaseShares []CoinbaseShare
+
+	// AllowEmptyBlocks is whether MinePending mines a Block with only the coinbase Transaction when no pending
+	// Transactions can be included. MinePending fails instead if it is false. Empty Blocks are always valid.
+	AllowEmptyBlocks bool
+
+	// ReplaceByFee is whether a Transaction submitted to the Mempool can replace pending Transactions
+	// that spend the same outputs by paying a higher fee. Such Transactions are rejected if it is false.
+	ReplaceByFee bool
+
+	// MinFeeBump is the minimum amount by which the fee of a replacement Transaction must exceed
+	// the total fee of the Transactions it replaces. A bump of at least 1 is always required.
+	MinFeeBump int
+
+	// MaxOrphans is the maximum number of orphan Transactions held until their parents are mined or submitted.
+	// Transactions that spend outputs of unknown Transactions are rejected if it is 0.
+	MaxOrphans int
+
+	// TxValidator is the hook for custom Transaction policy, applied in VerifyTransaction.
+	// Only the consensus checks are applied if it is nil.
+	TxValidator TxValidator
+
+	// SnapshotInterval is the number of Blocks between the balance snapshots used by StateAt.
+	// Balances are not snapshotted if it is 0.
+	SnapshotInterval int64
+
+	// Durability is how chain updates are persisted to disk. Batched durability is faster
+	// for bulk appends such as Import, at the cost of replaying the write-ahead log after a crash.
+	Durability Durability
+
+	// FlushBlocks is the number of Blocks appended between flushes with batched durability.
+	// DefaultFlushBlocks is used if it is 0.
+	FlushBlocks int
+
+	// FlushInterval is the maximum time between flushes with batched durability.
+	// DefaultFlushInterval is used if it is 0.
+	FlushInterval time.Duration
+
+	// WriteRetries is the maximum number of times a database write that fails with a transient error is retried.
+	// Failed writes are not retried if it is 0. Writes that fail with any other error are never retried.
+	WriteRetries int
+
+	// WriteRetryDelay is the delay before the first retry of a failed database write, doubling for each retry
+	WriteRetryDelay time.Duration
+
+	// UndoRetention is the number of most recent Blocks whose undo data is kept to disconnect them in a
+	// reorganization. Undo data of older Blocks is pruned as Blocks are appended, so reorganizations deeper
+	// than the retention are rejected. Undo data is kept for every Block if it is 0.
+	UndoRetention int64
+
+	// MaxClockSkew is the maximum time by which the timestamp of a Block may be ahead of the local clock.
+	// Nodes with inaccurate clocks can raise it to accept each other's Blocks. DefaultMaxClockSkew is used if it is 0.
+	MaxClockSkew time.Duration
+
+	// VerifyBlockHashes is whether Blocks and headers read from the database by hash, with GetBlock,
+	// GetHeader and ChainIterator, are checked by recomputing their header hash and comparing it
+	// to the hash they were read with. Blocks are not checked if it is false.
+	VerifyBlockHashes bool
+
+	// Logger is the Logger for chain events. All events are discarded if it is nil.
+	Logger Logger
+}
+
+// DefaultOptions returns the default Options for a ChainManager
+func DefaultOptions() Options {
+	return Options{
+		InitialBits: DifficultyToBits(Difficulty),
+		Genesis:     DefaultGenesisConfig(),
+
+		AllowEmptyBlocks: true,
+		MaxAddressLength: common.DefaultMaxAddressLength,
+
+		WriteRetries:    DefaultWriteRetries,
+		WriteRetryDelay: DefaultWriteRetryDelay,
+
+		DifficultyAdjuster: DefaultWindowAdjuster(),
+	}
+}
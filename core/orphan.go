@@ -0,0 +1,227 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/anee769/essensio/common"
+)
+
+// ErrOrphanTransaction is returned by SubmitTransaction for a Transaction that spends outputs of
+// Transactions that are not on the chain, if the OrphanPool of the chain is disabled
+var ErrOrphanTransaction = errors.New("transaction spends outputs of unknown transactions")
+
+// OrphanPool is a bounded pool of orphan Transactions, which spend outputs of Transactions that are not
+// yet on the chain. Orphans are held until their parents are mined and evicted in order of arrival when
+// the pool is full. It is safe for concurrent use.
+type OrphanPool struct {
+	mutex sync.Mutex
+	limit int
+
+	// Represents the orphan Transactions indexed by their ID
+	txns map[common.Hash]*Transaction
+	// Represents the IDs of the orphan Transactions in order of arrival
+	order []common.Hash
+	// Represents the IDs of the orphan Transactions waiting on each missing parent
+	waiting map[common.Hash][]common.Hash
+}
+
+// NewOrphanPool returns a new empty OrphanPool that holds up to limit Transactions
+func NewOrphanPool(limit int) *OrphanPool {
+	return &OrphanPool{
+		limit:   limit,
+		txns:    make(map[common.Hash]*Transaction),
+		waiting: make(map[common.Hash][]common.Hash),
+	}
+}
+
+// Size returns the number of orphan Transactions in the OrphanPool
+func (pool *OrphanPool) Size() int {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	return len(pool.txns)
+}
+
+// Has returns whether the Transaction with the given ID is held in the OrphanPool
+func (pool *OrphanPool) Has(id common.Hash) bool {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	_, exists := pool.txns[id]
+	return exists
+}
+
+// add holds a Transaction until the given missing parents are mined.
+// The oldest orphans are evicted to make room and their IDs are returned.
+func (pool *OrphanPool) add(txn *Transaction, parents []common.Hash) ([]common.Hash, error) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	if _, exists := pool.txns[txn.ID]; exists {
+		return nil, fmt.Errorf("transaction '%v' already in orphan pool", txn.ID)
+	}
+
+	var evicted []common.Hash
+	for len(pool.order) >= pool.limit && len(pool.order) > 0 {
+		oldest := pool.order[0]
+		pool.remove(oldest)
+		evicted = append(evicted, oldest)
+	}
+
+	pool.txns[txn.ID] = txn
+	pool.order = append(pool.order, txn.ID)
+
+	for _, parent := range parents {
+		pool.waiting[parent] = append(pool.waiting[parent], txn.ID)
+	}
+
+	return evicted, nil
+}
+
+// take removes and returns the orphan Transactions waiting on the given parent, in order of arrival
+func (pool *OrphanPool) take(parent common.Hash) Transactions {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	var txns Transactions
+	for _, id := range pool.waiting[parent] {
+		if txn, exists := pool.txns[id]; exists {
+			txns = append(txns, txn)
+			pool.remove(id)
+		}
+	}
+
+	delete(pool.waiting, parent)
+	return txns
+}
+
+// remove removes the orphan Transaction with the given ID. Must be called with the lock held.
+func (pool *OrphanPool) remove(id common.Hash) {
+	txn, exists := pool.txns[id]
+	if !exists {
+		return
+	}
+
+	delete(pool.txns, id)
+
+	for idx, orphan := range pool.order {
+		if orphan == id {
+			pool.order = append(pool.order[:idx], pool.order[idx+1:]...)
+			break
+		}
+	}
+
+	// Remove the Transaction from the orphans waiting on each of its parents
+	for _, input := range txn.Inputs {
+		waiting := pool.waiting[input.ID]
+		for idx, orphan := range waiting {
+			if orphan == id {
+				waiting = append(waiting[:idx], waiting[idx+1:]...)
+				break
+			}
+		}
+
+		if len(waiting) == 0 {
+			delete(pool.waiting, input.ID)
+		} else {
+			pool.waiting[input.ID] = waiting
+		}
+	}
+}
+
+// Orphans returns the OrphanPool of orphan Transactions for the chain
+func (chain *ChainManager) Orphans() *OrphanPool {
+	return chain.orphans
+}
+
+// missingParents returns the IDs of the Transactions that are spent by the inputs
+// of a non-coinbase Transaction but do not exist on the chain or in the Mempool
+func (chain *ChainManager) missingParents(txn *Transaction) ([]common.Hash, error) {
+	var missing []common.Hash
+	seen := make(map[common.Hash]bool)
+
+	for _, input := range txn.Inputs {
+		if seen[input.ID] {
+			continue
+		}
+
+		seen[input.ID] = true
+
+		if _, pending := chain.mempool.Get(input.ID); pending {
+			continue
+		}
+
+		exists, err := chain.HasTransaction(input.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !exists {
+			missing = append(missing, input.ID)
+		}
+	}
+
+	return missing, nil
+}
+
+// holdOrphan adds a Transaction with missing parents to the OrphanPool of the chain.
+// Returns an error wrapping ErrOrphanTransaction if Options.MaxOrphans is 0.
+func (chain *ChainManager) holdOrphan(txn *Transaction, parents []common.Hash) error {
+	if chain.opts.MaxOrphans <= 0 {
+		return fmt.Errorf("parent transaction '%v' not found: %w", parents[0], ErrOrphanTransaction)
+	}
+
+	// Check the ID of the Transaction, which is all that can be verified without its parents
+	verify := *txn
+	if err := verify.SetID(); err != nil {
+		return fmt.Errorf("transaction id generation failed: %w", err)
+	}
+
+	if !verify.ID.Equal(txn.ID) {
+		return fmt.Errorf("transaction id mismatch: %v", txn.ID)
+	}
+
+	evicted, err := chain.orphans.add(txn, parents)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range evicted {
+		chain.opts.Logger.Debugf("Evicted orphan transaction '%v'.", id)
+	}
+
+	chain.opts.Logger.Debugf("Holding orphan transaction '%v' for %v missing parents.", txn.ID, len(parents))
+	return nil
+}
+
+// promoteOrphans submits the orphan Transactions waiting on the Transactions of the Blocks with the
+// given hashes to the Mempool. Orphans that still have missing parents are held again.
+func (chain *ChainManager) promoteOrphans(hashes []common.Hash) {
+	if chain.orphans.Size() == 0 {
+		return
+	}
+
+	for _, hash := range hashes {
+		block, err := chain.GetBlock(hash)
+		if err != nil {
+			chain.opts.Logger.Infof("Orphan promotion for block '%v' failed: %v", hash, err)
+			continue
+		}
+
+		for _, parent := range block.BlockTxns {
+			chain.promoteOrphansOf(parent.ID)
+		}
+	}
+}
+
+// promoteOrphansOf submits the orphan Transactions waiting on the Transaction with the given ID to the Mempool.
+// Orphans that still have missing parents are held again.
+func (chain *ChainManager) promoteOrphansOf(parent common.Hash) {
+	for _, orphan := range chain.orphans.take(parent) {
+		if err := chain.SubmitTransaction(orphan); err != nil {
+			chain.opts.Logger.Debugf("Dropped orphan transaction '%v': %v", orphan.ID, err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestOrphanTransaction checks that a Transaction spending the output of an unknown Transaction is held in
+// the OrphanPool, and is accepted into the Mempool once its parent is mined
+func TestOrphanTransaction(t *testing.T) {
+	opts := testChainOptions(0)
+	opts.Options.MaxOrphans = 2
+
+	chain := newTestChain(t, opts)
+
+	parent := signedTransaction(t, common.MinerAddress(), []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: 90, PubKey: "alice"})
+	orphan := signedTransaction(t, "alice", []Outpoint{{parent.ID, 0}}, TxOutput{Value: 80, PubKey: "bob"})
+
+	if err := chain.SubmitTransaction(orphan); err != nil {
+		t.Fatalf("failed to submit orphan transaction: %v", err)
+	}
+
+	if !chain.Orphans().Has(orphan.ID) || chain.Mempool().Size() != 0 {
+		t.Fatalf("expected the orphan transaction to be held outside the mempool")
+	}
+
+	if err := chain.MineBlock(Transactions{parent}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	if chain.Orphans().Size() != 0 {
+		t.Fatalf("expected the orphan transaction to leave the orphan pool")
+	}
+
+	if _, pending := chain.Mempool().Get(orphan.ID); !pending {
+		t.Fatalf("expected the orphan transaction to be accepted once its parent is mined")
+	}
+}
+
+// TestOrphanPoolLimit checks that the oldest orphans are evicted when the OrphanPool is full
+func TestOrphanPoolLimit(t *testing.T) {
+	opts := testChainOptions(0)
+	opts.Options.MaxOrphans = 2
+
+	chain := newTestChain(t, opts)
+
+	outputs := []TxOutput{{Value: 30, PubKey: "alice"}, {Value: 30, PubKey: "alice"}, {Value: 30, PubKey: "alice"}}
+	parent := signedTransaction(t, common.MinerAddress(), []Outpoint{genesisOutpoint(t, chain)}, outputs...)
+
+	orphans := make(Transactions, len(outputs))
+	for idx := range orphans {
+		orphans[idx] = signedTransaction(t, "alice", []Outpoint{{parent.ID, idx}}, TxOutput{Value: 20, PubKey: "bob"})
+		if err := chain.SubmitTransaction(orphans[idx]); err != nil {
+			t.Fatalf("failed to submit orphan transaction: %v", err)
+		}
+	}
+
+	if chain.Orphans().Size() != 2 || chain.Orphans().Has(orphans[0].ID) {
+		t.Fatalf("expected the oldest orphan to be evicted from the full orphan pool")
+	}
+}
+
+// TestOrphanPoolDisabled checks that orphans are rejected with ErrOrphanTransaction when the OrphanPool is disabled
+func TestOrphanPoolDisabled(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+
+	parent := signedTransaction(t, common.MinerAddress(), []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: 90, PubKey: "alice"})
+	orphan := signedTransaction(t, "alice", []Outpoint{{parent.ID, 0}}, TxOutput{Value: 80, PubKey: "bob"})
+
+	if err := chain.SubmitTransaction(orphan); !errors.Is(err, ErrOrphanTransaction) {
+		t.Fatalf("expected the orphan transaction to be rejected, got %v", err)
+	}
+
+	if chain.Orphans().Size() != 0 || chain.Mempool().Size() != 0 {
+		t.Fatalf("expected the rejected orphan transaction not to be held")
+	}
+}
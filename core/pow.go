@@ -2,50 +2,117 @@ package core
 
 import (
 	"fmt"
-	"log"
 	"math"
 	"math/big"
 
 	"github.com/anee769/essensio/common"
 )
 
-// Difficulty represents the number of bits that need to be 0 for the Proof Of Work Algorithm.
-// Currently Static, but can eventually be adjusted based on the total hash rate of the network,
-// to achieve a block time of n minutes.
+// Difficulty represents the default number of bits that need to be 0 for the Proof Of Work Algorithm.
+// Can be overridden for a chain with Options.InitialBits and can eventually be adjusted
+// based on the total hash rate of the network, to achieve a block time of n minutes.
 const Difficulty uint8 = 18
 
-// GenerateTarget returns a big.Int with the target hash value for the current difficulty
-func GenerateTarget() *big.Int {
+// GenerateTarget returns a big.Int with the target hash value for a given difficulty
+func GenerateTarget(difficulty uint8) *big.Int {
 	// Generate a new big Integer and left shift to match difficulty
 	target := big.NewInt(1)
-	target.Lsh(target, 256-uint(Difficulty))
+	target.Lsh(target, 256-uint(difficulty))
 
 	return target
 }
 
+// DifficultyToBits returns the compact representation of the target hash value for a given difficulty
+func DifficultyToBits(difficulty uint8) uint32 {
+	return TargetToCompact(GenerateTarget(difficulty))
+}
+
+// CompactToTarget converts a compact representation of a target into a big.Int.
+// The compact representation is the same as the 'bits' of Bitcoin. The most significant byte
+// is the size of the target in bytes and the lower 3 bytes are its most significant bytes.
+// The sign bit (0x00800000) is not supported and results in a zero target.
+func CompactToTarget(bits uint32) *big.Int {
+	mantissa := bits & 0x007fffff
+	exponent := uint(bits >> 24)
+
+	if bits&0x00800000 != 0 {
+		return new(big.Int)
+	}
+
+	if exponent <= 3 {
+		mantissa >>= 8 * (3 - exponent)
+		return big.NewInt(int64(mantissa))
+	}
+
+	target := big.NewInt(int64(mantissa))
+	return target.Lsh(target, 8*(exponent-3))
+}
+
+// TargetToCompact converts a target into its compact representation.
+// Precision beyond the 3 most significant bytes of the target is lost.
+func TargetToCompact(target *big.Int) uint32 {
+	size := uint32(len(target.Bytes()))
+
+	var mantissa uint32
+	if size <= 3 {
+		mantissa = uint32(target.Uint64()) << (8 * (3 - size))
+	} else {
+		mantissa = uint32(new(big.Int).Rsh(target, uint(8*(size-3))).Uint64())
+	}
+
+	// Shift the mantissa if it would set the sign bit
+	if mantissa&0x00800000 != 0 {
+		mantissa >>= 8
+		size++
+	}
+
+	return size<<24 | mantissa
+}
+
+// nextBits returns the compact Proof of Work target for the next Block on the chain,
+// computed by the DifficultyAdjuster of the chain from the headers of its most recent Blocks.
+func (chain *ChainManager) nextBits() (uint32, error) {
+	window := chain.opts.DifficultyAdjuster.Window()
+	if window < 1 {
+		window = 1
+	}
+
+	// Collect the headers of the window, from the chain head backwards
+	headers := make([]*BlockHeader, 0, window)
+
+	iter := chain.NewIterator()
+	for len(headers) < window && !iter.Done() {
+		header, err := iter.NextHeader()
+		if err != nil {
+			return 0, fmt.Errorf("chain header retrieve failed: %w", err)
+		}
+
+		headers = append(headers, header)
+	}
+
+	// Order the headers from oldest to the chain head
+	for i, j := 0, len(headers)-1; i < j; i, j = i+1, j-1 {
+		headers[i], headers[j] = headers[j], headers[i]
+	}
+
+	return chain.opts.DifficultyAdjuster.NextBits(chain.Height, headers, chain.opts.InitialBits), nil
+}
+
 // Mint is the Proof of Work routine that generates a nonce
 // that is valid for the Target difficulty of the header.
 func (header *BlockHeader) Mint() common.Hash {
 	var hash common.Hash
+	target := header.Target()
 
 	// Reset Nonce
 	header.Nonce = 0
 
 	for header.Nonce < math.MaxInt64 {
-		// Serialize the Header
-		data, err := header.Serialize()
-		if err != nil {
-			log.Fatalln("header serialization failed during PoW:", err)
-		}
-
-		// Hash the Header data
-		hash = common.Hash256(data)
-
-		// Print the hash mining process
-		fmt.Printf("\rMining Block [%v]: %v", header.Nonce, hash.Hex())
+		// Hash the Header
+		hash = header.Hash()
 
 		// Compare the hash with target
-		if hash.Big().Cmp(header.Target) == -1 {
+		if hash.Big().Cmp(target) == -1 {
 			break // Block Mined!
 		} else {
 			// Increment Nonce & Repeat
@@ -53,22 +120,15 @@ func (header *BlockHeader) Mint() common.Hash {
 		}
 	}
 
-	fmt.Println()
 	return hash
 }
 
 // Validate is the Proof of Work validation routine.
 // Returns a boolean indicating if the hash of the block is valid for its target.
 func (header *BlockHeader) Validate() bool {
-	// Serialize the Header
-	data, err := header.Serialize()
-	if err != nil {
-		log.Fatalln("header serialization failed during PoW:", err)
-	}
-
-	// Hash the Header data
-	hash := common.Hash256(data)
+	// Hash the Header
+	hash := header.Hash()
 
 	// Compare hash with target
-	return hash.Big().Cmp(header.Target) == -1
+	return hash.Big().Cmp(header.Target()) == -1
 }
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestCompactTarget checks that targets of each difficulty convert to their compact representation and back
+func TestCompactTarget(t *testing.T) {
+	for _, difficulty := range []uint8{1, 8, 18, 24, 200} {
+		target := GenerateTarget(difficulty)
+		if back := CompactToTarget(TargetToCompact(target)); back.Cmp(target) != 0 {
+			t.Fatalf("difficulty %v: expected target %x, got %x", difficulty, target, back)
+		}
+	}
+}
+
+// TestInitialBits checks that the Genesis Block and the Blocks before the first difficulty adjustment
+// of a chain are mined for exactly the target of Options.InitialBits
+func TestInitialBits(t *testing.T) {
+	opts := DefaultOptions()
+	opts.InitialBits = DifficultyToBits(8)
+
+	chain := newChain(t, opts)
+	for idx := 0; idx < 3; idx++ {
+		if err := chain.MineBlock(nil, ""); err != nil {
+			t.Fatalf("failed to mine block: %v", err)
+		}
+	}
+
+	for height := int64(0); height < chain.Height; height++ {
+		block, err := chain.GetBlockByHeight(height)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if block.Bits != opts.InitialBits {
+			t.Fatalf("expected the block at height %v to have bits %x, got %x", height, opts.InitialBits, block.Bits)
+		}
+
+		if !block.Validate() || block.BlockHash.Big().Cmp(GenerateTarget(8)) >= 0 {
+			t.Fatalf("expected the block at height %v to meet its target", height)
+		}
+	}
+}
+
+// TestCompactKnownTargets checks the compact representation against targets of known bits
+func TestCompactKnownTargets(t *testing.T) {
+	tests := []struct {
+		bits   uint32
+		target string
+	}{
+		{0x1d00ffff, "ffff0000000000000000000000000000000000000000000000000000"},
+		{0x1b0404cb, "404cb000000000000000000000000000000000000000000000000"},
+		{0x03123456, "123456"},
+		{0x02123400, "1234"},
+	}
+
+	for _, test := range tests {
+		want, _ := new(big.Int).SetString(test.target, 16)
+
+		if target := CompactToTarget(test.bits); target.Cmp(want) != 0 {
+			t.Fatalf("bits %08x: expected target %x, got %x", test.bits, want, target)
+		}
+
+		if bits := TargetToCompact(want); bits != test.bits {
+			t.Fatalf("target %x: expected bits %08x, got %08x", want, test.bits, bits)
+		}
+	}
+
+	// Targets with the sign bit of the mantissa set are encoded with a larger exponent
+	if bits := TargetToCompact(big.NewInt(0x80)); bits != 0x02008000 {
+		t.Fatalf("expected bits 02008000, got %08x", bits)
+	}
+}
+
+// TestTargetBetweenNibbles checks that a target between two nibble boundaries is represented exactly by its bits,
+// and that Validate compares the hash of a header against the full target rather than its leading zeros
+func TestTargetBetweenNibbles(t *testing.T) {
+	// 3/4 of the target of 8 leading zero bits, which is between the targets of 8 and 12 leading zero bits
+	loose := GenerateTarget(8)
+	target := new(big.Int).Rsh(new(big.Int).Mul(loose, big.NewInt(3)), 2)
+
+	bits := TargetToCompact(target)
+	if CompactToTarget(bits).Cmp(target) != 0 {
+		t.Fatalf("expected bits %08x to represent target %x exactly", bits, target)
+	}
+
+	header := &BlockHeader{Priori: common.NullHash(), Summary: common.NullHash(), Timestamp: 1, Bits: bits}
+	if hash := header.Mint(); hash.Big().Cmp(target) >= 0 || !header.Validate() {
+		t.Fatalf("expected the minted hash '%v' to be below the target %x", hash, target)
+	}
+
+	// Find a nonce whose hash has 8 leading zero bits but is not below the target
+	for header.Nonce = 0; ; header.Nonce++ {
+		hash := header.Hash().Big()
+		if hash.Cmp(target) >= 0 && hash.Cmp(loose) < 0 {
+			break
+		}
+	}
+
+	// The hash has as many leading zeros as the target, which leading zero counting would accept
+	if header.Validate() {
+		t.Fatalf("expected a hash between the target and the next nibble boundary to be invalid")
+	}
+}
@@ -0,0 +1,88 @@
+package core
+
+import "fmt"
+
+// recoverHead verifies the integrity of the Block at the chain head. If it is missing, truncated or invalid,
+// the chain is rolled back to the last valid Block using the height index and the chain is reindexed.
+func (chain *ChainManager) recoverHead() error {
+	hash, height := chain.Head, chain.Height-1
+
+	for {
+		// Load the Block and verify its integrity
+		block, err := chain.GetBlock(hash)
+		if err == nil {
+			if err = block.CheckIntegrity(); err == nil && block.BlockHeight != height {
+				err = fmt.Errorf("block height %v does not match index height %v", block.BlockHeight, height)
+			}
+		}
+
+		if err == nil {
+			break
+		}
+
+		if height == 0 {
+			return fmt.Errorf("no valid block to recover to: %w", err)
+		}
+
+		chain.opts.Logger.Infof("Invalid block '%v' at height %v: %v. Rolling back to parent.", hash, height, err)
+
+		// Remove the invalid Block from the height index and move to its parent
+		if err := chain.db.DeleteEntry(heightKey(height)); err != nil {
+			return fmt.Errorf("height index update failed: %w", err)
+		}
+
+		height--
+		if hash, err = chain.BlockHashAtHeight(height); err != nil {
+			return err
+		}
+	}
+
+	// Chain head is valid, no recovery required
+	if hash.Equal(chain.Head) {
+		return nil
+	}
+
+	chain.Head, chain.Height, chain.tip = hash, height+1, nil
+
+	// Rebuild the UTXO set, transaction index and statistics for the recovered chain
+	if err := chain.reindex(); err != nil {
+		return fmt.Errorf("reindex failed: %w", err)
+	}
+
+	if err := chain.recountStats(); err != nil {
+		return fmt.Errorf("statistics recount failed: %w", err)
+	}
+
+	// Sync the recovered chain state into the DB
+	if err := chain.syncState(); err != nil {
+		return fmt.Errorf("chain state sync failed: %w", err)
+	}
+
+	chain.opts.Logger.Infof("Recovered chain to block '%v' at height %v.", chain.Head, height)
+	return nil
+}
+
+// CheckIntegrity verifies that the Block hash matches its header,
+// that the header satisfies its PoW target and that the
+// header summary matches the Transactions of the Block.
+func (block *Block) CheckIntegrity() error {
+	// Check that the header hashes to the Block hash
+	if !block.BlockHeader.Hash().Equal(block.BlockHash) {
+		return fmt.Errorf("block hash does not match header")
+	}
+
+	if !block.BlockHeader.Validate() {
+		return fmt.Errorf("block header does not satisfy target")
+	}
+
+	if !block.VerifySummary() {
+		return fmt.Errorf("block summary does not match transactions")
+	}
+
+	return nil
+}
+
+// VerifySummary returns whether the header summary of the Block matches its Transactions
+func (block *Block) VerifySummary() bool {
+	return GenerateSummary(block.BlockTxns).Equal(block.Summary)
+}
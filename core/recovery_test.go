@@ -0,0 +1,56 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/anee769/essensio/db"
+)
+
+// TestRecoverTruncatedHead checks that a chain whose head Block was truncated by a crash
+// is rolled back to the parent of the head Block when it is loaded
+func TestRecoverTruncatedHead(t *testing.T) {
+	chain := generateTestChain(t, testChainOptions(3))
+
+	tip, err := chain.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	head, parent, height := tip.BlockHash, tip.Priori, chain.Height
+	chain.Stop()
+
+	// Truncate the stored bytes of the head Block
+	database, err := db.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := database.GetEntry(head.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := database.SetEntry(head.Bytes(), data[:len(data)/2]); err != nil {
+		t.Fatal(err)
+	}
+
+	database.Close()
+
+	chain = openTestChain(t, DefaultOptions())
+	if chain.Height != height-1 || !chain.Head.Equal(parent) {
+		t.Fatalf("expected the chain to recover to the parent at height %v, got '%v' at height %v", height-1, chain.Head, chain.Height)
+	}
+
+	if err := chain.VerifyChain(); err != nil {
+		t.Fatalf("expected the recovered chain to be valid, got %v", err)
+	}
+
+	audit, err := chain.AuditUTXO()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !audit.Consistent() {
+		t.Fatalf("expected the utxo set of the recovered chain to be consistent, got %v discrepancies", len(audit.Discrepancies))
+	}
+}
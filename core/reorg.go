@@ -0,0 +1,269 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/anee769/essensio/common"
+)
+
+// UndoPrefix is the key prefix for the undo data of each Block in the database.
+// The undo data of a Block is the set of UTXO entries spent by its Transactions.
+var UndoPrefix = []byte("undo-")
+
+// UndoEntry represents a UTXO entry spent by a Block, to be restored if the Block is disconnected
+type UndoEntry struct {
+	Outpoint Outpoint
+	Entry    UTXOEntry
+}
+
+// undoKey returns the database key for the undo data of the Block with the given hash
+func undoKey(hash common.Hash) []byte {
+	return append(append([]byte{}, UndoPrefix...), hash.Bytes()...)
+}
+
+// loadUndo returns the undo data of the Block with the given hash
+func (chain *ChainManager) loadUndo(hash common.Hash) ([]UndoEntry, error) {
+	data, err := chain.db.GetEntry(undoKey(hash))
+	if err != nil {
+		return nil, fmt.Errorf("undo data retrieve failed: %w", err)
+	}
+
+	object, err := common.GobDecode(data, new([]UndoEntry))
+	if err != nil {
+		return nil, fmt.Errorf("undo data deserialize failed: %w", err)
+	}
+
+	return *object.(*[]UndoEntry), nil
+}
+
+// pruneUndo removes the undo data of the Block that falls outside Options.UndoRetention
+// once the given Block is appended, so that undo data is only kept for the most recent Blocks
+func (chain *ChainManager) pruneUndo(block *Block) error {
+	retention := chain.opts.UndoRetention
+	if retention <= 0 || block.BlockHeight < retention {
+		return nil
+	}
+
+	hash, err := chain.BlockHashAtHeight(block.BlockHeight - retention)
+	if err != nil {
+		return fmt.Errorf("pruned block lookup failed: %w", err)
+	}
+
+	if err := chain.db.DeleteEntry(undoKey(hash)); err != nil {
+		return fmt.Errorf("undo data prune failed: %w", err)
+	}
+
+	return nil
+}
+
+// Reorganize replaces the Blocks of the chain after a fork point with a branch of Blocks.
+// The first Block of the branch must extend a Block on the chain and the branch must not be shorter
+// than the Blocks it replaces, which must not be more than Options.UndoRetention Blocks if it is set.
+// The Blocks of the branch are verified as they are connected and the original Blocks are restored
+// if any of them is invalid. Transactions of the removed Blocks that are not in the branch are
+// returned to the Mempool, if they are still valid.
+func (chain *ChainManager) Reorganize(branch []*Block) error {
+	if len(branch) == 0 {
+		return fmt.Errorf("empty branch")
+	}
+
+	chain.chainMutex.Lock()
+	event, err := chain.reorganize(branch)
+	chain.chainMutex.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	chain.publish(event)
+	return nil
+}
+
+// reorganize replaces the Blocks of the chain after a fork point with a non-empty branch of Blocks like Reorganize
+// and returns the ChainEvent of the reorganization, without publishing it. Must be called with the chain lock held.
+func (chain *ChainManager) reorganize(branch []*Block) (ChainEvent, error) {
+	// Find the fork point of the branch on the chain
+	fork := branch[0].BlockHeight - 1
+	if fork < 0 {
+		return ChainEvent{}, fmt.Errorf("branch cannot replace the genesis block")
+	}
+
+	forkHash, err := chain.BlockHashAtHeight(fork)
+	if err != nil {
+		return ChainEvent{}, fmt.Errorf("branch fork point not found: %w", err)
+	}
+
+	if !forkHash.Equal(branch[0].Priori) {
+		return ChainEvent{}, fmt.Errorf("branch does not extend the block at height %v", fork)
+	}
+
+	if int64(len(branch)) < chain.Height-1-fork {
+		return ChainEvent{}, fmt.Errorf("branch of %v blocks is shorter than the %v blocks it replaces", len(branch), chain.Height-1-fork)
+	}
+
+	// Blocks beyond the undo retention have no undo data and cannot be disconnected
+	if retention := chain.opts.UndoRetention; retention > 0 && chain.Height-1-fork > retention {
+		return ChainEvent{}, fmt.Errorf("reorganization of %v blocks exceeds the undo retention of %v blocks", chain.Height-1-fork, retention)
+	}
+
+	// Flush the chain before disconnecting any Blocks, since the write-ahead log only records
+	// appended Blocks and cannot restore a disconnected Block after a crash
+	if err := chain.Flush(); err != nil {
+		return ChainEvent{}, fmt.Errorf("chain flush failed: %w", err)
+	}
+
+	// Disconnect the Blocks after the fork point
+	var removed []*Block
+	for chain.Height-1 > fork {
+		block, err := chain.disconnectTip()
+		if err != nil {
+			return ChainEvent{}, fmt.Errorf("block disconnect failed: %w", err)
+		}
+
+		removed = append(removed, block)
+	}
+
+	// Connect the Blocks of the branch
+	for idx, block := range branch {
+		err := chain.VerifyBlock(block)
+		if err == nil {
+			err = chain.appendBlock(block)
+		}
+
+		if err != nil {
+			if restoreErr := chain.restore(idx, removed); restoreErr != nil {
+				return ChainEvent{}, fmt.Errorf("chain restore failed: %v: after branch block %v failed: %w", restoreErr, idx, err)
+			}
+
+			if flushErr := chain.Flush(); flushErr != nil {
+				return ChainEvent{}, fmt.Errorf("chain flush failed: %v: after branch block %v failed: %w", flushErr, idx, err)
+			}
+
+			return ChainEvent{}, fmt.Errorf("branch block %v: %w", idx, err)
+		}
+	}
+
+	event := ChainEvent{}
+	for _, block := range removed {
+		event.Removed = append(event.Removed, block.BlockHash)
+	}
+
+	for _, block := range branch {
+		event.Added = append(event.Added, block.BlockHash)
+	}
+
+	// Return the Transactions of the removed Blocks to the Mempool
+	for _, block := range removed {
+		for _, txn := range block.BlockTxns {
+			if !txn.IsCoinbase() {
+				_ = chain.SubmitTransaction(txn)
+			}
+		}
+	}
+
+	if err := chain.Flush(); err != nil {
+		return ChainEvent{}, fmt.Errorf("chain flush failed: %w", err)
+	}
+
+	chain.opts.Logger.Infof("Reorganized chain at height %v. Removed %v blocks and added %v blocks.", fork, len(removed), len(branch))
+	return event, nil
+}
+
+// restore disconnects the given number of connected branch Blocks and reconnects
+// the removed Blocks of the chain, which are ordered from the old chain head.
+func (chain *ChainManager) restore(connected int, removed []*Block) error {
+	for idx := 0; idx < connected; idx++ {
+		if _, err := chain.disconnectTip(); err != nil {
+			return err
+		}
+	}
+
+	for idx := len(removed) - 1; idx >= 0; idx-- {
+		if err := chain.appendBlock(removed[idx]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// disconnectTip removes the Block at the chain head and reverts its changes to the
+// UTXO set and indexes with its undo data. The Block itself remains in the database.
+func (chain *ChainManager) disconnectTip() (*Block, error) {
+	chain.utxoMutex.Lock()
+	defer chain.utxoMutex.Unlock()
+
+	if chain.Height <= 1 {
+		return nil, fmt.Errorf("cannot disconnect the genesis block")
+	}
+
+	block, err := chain.Tip()
+	if err != nil {
+		return nil, err
+	}
+
+	undo, err := chain.loadUndo(block.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove the outputs created by the Block and its Transactions from the transaction and spent indexes
+	for _, txn := range block.BlockTxns {
+		for idx := range txn.Outputs {
+			if err := chain.db.DeleteEntry(Outpoint{txn.ID, idx}.Key()); err != nil {
+				return nil, fmt.Errorf("utxo remove failed: %w", err)
+			}
+		}
+
+		if err := chain.db.DeleteEntry(txnKey(txn.ID)); err != nil {
+			return nil, fmt.Errorf("transaction index update failed: %w", err)
+		}
+
+		if txn.IsCoinbase() {
+			continue
+		}
+
+		for _, input := range txn.Inputs {
+			if err := chain.db.DeleteEntry(Outpoint{input.ID, input.Out}.prefixedKey(SpentIndexPrefix)); err != nil {
+				return nil, fmt.Errorf("spent index update failed: %w", err)
+			}
+		}
+	}
+
+	// Restore the outputs spent by the Block
+	for _, spent := range undo {
+		data, err := common.GobEncode(spent.Entry)
+		if err != nil {
+			return nil, fmt.Errorf("utxo entry serialize failed: %w", err)
+		}
+
+		if err := chain.db.SetEntry(spent.Outpoint.Key(), data); err != nil {
+			return nil, fmt.Errorf("utxo restore failed: %w", err)
+		}
+	}
+
+	if err := chain.db.DeleteEntry(undoKey(block.BlockHash)); err != nil {
+		return nil, fmt.Errorf("undo data remove failed: %w", err)
+	}
+
+	if err := chain.db.DeleteEntry(snapshotKey(block.BlockHeight)); err != nil {
+		return nil, fmt.Errorf("balance snapshot remove failed: %w", err)
+	}
+
+	if err := chain.db.DeleteEntry(heightKey(block.BlockHeight)); err != nil {
+		return nil, fmt.Errorf("height index update failed: %w", err)
+	}
+
+	// Move the chain head to the parent of the Block
+	chain.Head = block.Priori
+	chain.Height--
+	chain.tip = nil
+	chain.uncountBlock(block)
+
+	if err := chain.syncState(); err != nil {
+		return nil, fmt.Errorf("chain state sync failed: %w", err)
+	}
+
+	chain.opts.Logger.Debugf("Disconnected block '%v' at height %v.", block.BlockHash, block.BlockHeight)
+	return block, nil
+}
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// branchBlock returns a Block with only a coinbase Transaction tagged with data, which extends the given parent Block
+func branchBlock(t *testing.T, chain *ChainManager, parent *Block, data string) *Block {
+	t.Helper()
+
+	bits, err := chain.nextBits()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	height := parent.BlockHeight + 1
+	return NewBlock(Transactions{CoinbaseTxn(common.MinerAddress(), data, height)}, parent.BlockHash, height, bits)
+}
+
+// TestReorganizeEvent checks that a one-block reorganization publishes a single ChainEvent with the removed
+// and the added Block, and that the Transactions of the removed Block are returned to the Mempool
+func TestReorganizeEvent(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	miner := common.MinerAddress()
+
+	sub := chain.Subscribe(4)
+	defer sub.Unsubscribe()
+
+	spend := signedTransaction(t, miner, []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: BlockReward, PubKey: "alice"})
+	if err := chain.MineBlock(Transactions{spend}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	removed := chain.Head
+	if event := <-sub.C; event.IsReorg() || len(event.Added) != 1 || !event.Added[0].Equal(removed) {
+		t.Fatalf("expected an event adding the mined block, got %+v", event)
+	}
+
+	genesis, err := chain.GetBlockByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	branch := branchBlock(t, chain, genesis, "branch")
+	if err := chain.Reorganize([]*Block{branch}); err != nil {
+		t.Fatalf("failed to reorganize chain: %v", err)
+	}
+
+	event := <-sub.C
+	if !event.IsReorg() || !equalHashes(event.Removed, []common.Hash{removed}) || !equalHashes(event.Added, []common.Hash{branch.BlockHash}) {
+		t.Fatalf("expected an event removing '%v' and adding '%v', got %+v", removed, branch.BlockHash, event)
+	}
+
+	if !chain.Head.Equal(branch.BlockHash) || chain.Height != 2 {
+		t.Fatalf("expected the branch block to be the chain head")
+	}
+
+	if _, pending := chain.Mempool().Get(spend.ID); !pending {
+		t.Fatalf("expected the spend of the removed block to be returned to the mempool")
+	}
+
+	select {
+	case event := <-sub.C:
+		t.Fatalf("expected a single event for the reorganization, got %+v", event)
+	default:
+	}
+}
+
+// TestUndoRetention checks that the undo data of Blocks older than Options.UndoRetention is pruned as Blocks are
+// appended, that a reorganization within the retention still succeeds and that a deeper one is rejected
+func TestUndoRetention(t *testing.T) {
+	opts := testChainOptions(0)
+	opts.Options.UndoRetention = 2
+
+	chain := newTestChain(t, opts)
+	for idx := 0; idx < 5; idx++ {
+		if err := chain.MineBlock(nil, ""); err != nil {
+			t.Fatalf("failed to mine block: %v", err)
+		}
+	}
+
+	// Only the Blocks at the last 2 heights keep their undo data
+	blocks := make([]*Block, chain.Height)
+	for height := int64(1); height < chain.Height; height++ {
+		block, err := chain.GetBlockByHeight(height)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exists, err := chain.db.HasEntry(undoKey(block.BlockHash))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if retained := height >= chain.Height-2; exists != retained {
+			t.Fatalf("expected the undo data of the block at height %v to be retained: %v, got %v", height, retained, exists)
+		}
+
+		blocks[height] = block
+	}
+
+	// A reorganization of the 3 Blocks after height 2 exceeds the retention
+	deep := []*Block{branchBlock(t, chain, blocks[2], "deep")}
+	for len(deep) < 3 {
+		deep = append(deep, branchBlock(t, chain, deep[len(deep)-1], "deep"))
+	}
+
+	if err := chain.Reorganize(deep); err == nil || !strings.Contains(err.Error(), "exceeds the undo retention") {
+		t.Fatalf("expected a reorganization beyond the undo retention to be rejected, got %v", err)
+	}
+
+	// A reorganization of the 2 Blocks after height 3 is within the retention
+	branch := []*Block{branchBlock(t, chain, blocks[3], "branch")}
+	branch = append(branch, branchBlock(t, chain, branch[0], "branch"))
+
+	if err := chain.Reorganize(branch); err != nil {
+		t.Fatalf("failed to reorganize within the undo retention: %v", err)
+	}
+
+	if !chain.Head.Equal(branch[1].BlockHash) || chain.Height != 6 {
+		t.Fatalf("expected the branch to be the chain head at height 6, got height %v", chain.Height)
+	}
+
+	if err := chain.VerifyChain(); err != nil {
+		t.Fatalf("expected the reorganized chain to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/anee769/essensio/common"
+)
+
+// Replay is the effect of a Transaction on the chain, computed by ReplayTransaction
+type Replay struct {
+	// Spent is the set of outputs consumed by the Transaction
+	Spent []Outpoint
+	// Created is the set of outputs created by the Transaction, in the order of their index
+	Created []TxOutput
+	// Fee is the value of the inputs not spent by the outputs
+	Fee int
+	// Deltas is the net change in balance of each Address, as computed by BlockDeltas
+	Deltas map[common.Address]int
+}
+
+// ReplayTransaction computes the effect that a standalone non-coinbase Transaction would have if it were
+// included in the next Block of the chain. The Transaction is checked with CheckTransaction and its inputs
+// are resolved against the current UTXO set. Neither the Transaction nor the chain is modified.
+func (chain *ChainManager) ReplayTransaction(txn *Transaction) (*Replay, error) {
+	if err := chain.CheckTransaction(txn); err != nil {
+		return nil, err
+	}
+
+	replay := &Replay{
+		Spent:   make([]Outpoint, 0, len(txn.Inputs)),
+		Created: append([]TxOutput{}, txn.Outputs...),
+		Deltas:  make(map[common.Address]int),
+	}
+
+	for idx, input := range txn.Inputs {
+		outpoint := Outpoint{input.ID, input.Out}
+
+		output, err := chain.GetUnspentOutput(outpoint)
+		if err != nil {
+			return nil, fmt.Errorf("input %v: %w", idx, err)
+		}
+
+		replay.Spent = append(replay.Spent, outpoint)
+		replay.Deltas[output.PubKey] -= output.Value
+		replay.Fee += output.Value
+	}
+
+	for _, output := range txn.Outputs {
+		replay.Deltas[output.PubKey] += output.Value
+		replay.Fee -= output.Value
+	}
+
+	return replay, nil
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestReplayTransaction checks that replaying a Transaction does not modify the chain, and that its deltas
+// match the deltas of the Block that AddBlock appends for the Transaction, excluding its coinbase
+func TestReplayTransaction(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	miner := common.MinerAddress()
+
+	outpoint := genesisOutpoint(t, chain)
+	spend := signedTransaction(t, miner, []Outpoint{outpoint}, TxOutput{Value: 60, PubKey: "alice"}, TxOutput{Value: 30, PubKey: miner})
+
+	replay, err := chain.ReplayTransaction(spend)
+	if err != nil {
+		t.Fatalf("failed to replay transaction: %v", err)
+	}
+
+	if len(replay.Spent) != 1 || replay.Spent[0] != outpoint || len(replay.Created) != 2 || replay.Fee != 10 {
+		t.Fatalf("expected the replay to spend the genesis output with a fee of 10, got %+v", replay)
+	}
+
+	if unspent, err := chain.IsUnspent(outpoint); err != nil || !unspent {
+		t.Fatalf("expected the replay not to spend the genesis output, got %v", err)
+	}
+
+	if chain.Height != 1 || chain.Mempool().Size() != 0 {
+		t.Fatalf("expected the replay not to modify the chain or the mempool")
+	}
+
+	// The coinbase only pays the block reward, to an Address that the Transaction does not pay
+	coinbase := CoinbaseTxn("pool", "", chain.Height)
+	if err := chain.AddBlock(Transactions{coinbase, spend}); err != nil {
+		t.Fatalf("failed to add block: %v", err)
+	}
+
+	block, err := chain.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deltas, err := BlockDeltas(block, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deltas["pool"] -= BlockReward
+	for address, delta := range deltas {
+		if delta != replay.Deltas[address] {
+			t.Fatalf("expected the replay delta of %v to be %v, got %v", address, delta, replay.Deltas[address])
+		}
+	}
+
+	for address, delta := range replay.Deltas {
+		if delta != deltas[address] {
+			t.Fatalf("expected the block delta of %v to be %v, got %v", address, delta, deltas[address])
+		}
+	}
+
+	if _, err := chain.ReplayTransaction(spend); err == nil {
+		t.Fatalf("expected the replay of a spent transaction to fail")
+	}
+}
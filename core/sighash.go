@@ -0,0 +1,194 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anee769/essensio/common"
+)
+
+// SigHashType represents the parts of a Transaction that the signature of an input commits to.
+// It is a base type, which selects the committed outputs, optionally combined with SigHashAnyoneCanPay.
+type SigHashType uint8
+
+const (
+	// SigHashAll commits to every input and output of the Transaction. It is the default SigHashType.
+	SigHashAll SigHashType = 0x01
+	// SigHashNone commits to every input and none of the outputs, so the outputs can be changed by anyone
+	SigHashNone SigHashType = 0x02
+	// SigHashSingle commits to every input and only the output at the index of the signed input
+	SigHashSingle SigHashType = 0x03
+
+	// SigHashAnyoneCanPay is the flag that commits to only the signed input, so inputs can be added by others.
+	// Combined with SigHashAll, the signature commits to every output but only the current input.
+	SigHashAnyoneCanPay SigHashType = 0x80
+)
+
+// sigHashBase is the mask of the base type of a SigHashType
+const sigHashBase = 0x1f
+
+// sigSeparator separates the signer Address of a signature from its signature hash
+const sigSeparator = "#"
+
+// ErrSignatureInvalid is returned by VerifyTransaction for an input whose signature does not commit to the Transaction
+var ErrSignatureInvalid = errors.New("signature does not commit to transaction")
+
+// Valid returns whether the SigHashType has a known base type and no unknown flags
+func (flag SigHashType) Valid() bool {
+	if flag&^(sigHashBase|SigHashAnyoneCanPay) != 0 {
+		return false
+	}
+
+	switch flag & sigHashBase {
+	case SigHashAll, SigHashNone, SigHashSingle:
+		return true
+	default:
+		return false
+	}
+}
+
+// String implements the Stringer interface for SigHashType
+func (flag SigHashType) String() string {
+	var name string
+	switch flag & sigHashBase {
+	case SigHashAll:
+		name = "ALL"
+	case SigHashNone:
+		name = "NONE"
+	case SigHashSingle:
+		name = "SINGLE"
+	default:
+		return fmt.Sprintf("SigHashType(%#02x)", uint8(flag))
+	}
+
+	if flag&SigHashAnyoneCanPay != 0 {
+		name += "|ANYONECANPAY"
+	}
+
+	return name
+}
+
+// SignatureHash returns the hash that the signature of the input at the given index commits to for a SigHashType.
+// It is the hash of the canonical representation of the committed inputs and outputs, along with the SigHashType
+// and Height of the Transaction. The signatures of the inputs are never committed, so inputs can be signed in any order.
+// Returns an error if the SigHashType is invalid, or if it is SigHashSingle and the input has no matching output.
+func (txn *Transaction) SignatureHash(idx int, flag SigHashType) (common.Hash, error) {
+	if idx < 0 || idx >= len(txn.Inputs) {
+		return common.NullHash(), fmt.Errorf("input %v does not exist", idx)
+	}
+
+	if !flag.Valid() {
+		return common.NullHash(), fmt.Errorf("invalid signature hash type: %v", flag)
+	}
+
+	var buffer bytes.Buffer
+	writeInt(&buffer, int64(flag))
+
+	// Commit to the signed input alone with SigHashAnyoneCanPay, or every input otherwise
+	inputs := txn.Inputs
+	if flag&SigHashAnyoneCanPay != 0 {
+		inputs = txn.Inputs[idx : idx+1]
+	}
+
+	writeInt(&buffer, int64(len(inputs)))
+	for _, input := range inputs {
+		buffer.Write(input.ID.Bytes())
+		writeInt(&buffer, int64(input.Out))
+	}
+
+	// Commit to the outputs selected by the base type
+	var outputs []TxOutput
+	switch flag & sigHashBase {
+	case SigHashAll:
+		outputs = txn.Outputs
+	case SigHashSingle:
+		if idx >= len(txn.Outputs) {
+			return common.NullHash(), fmt.Errorf("no output at index %v for %v", idx, flag)
+		}
+
+		outputs = txn.Outputs[idx : idx+1]
+	}
+
+	writeInt(&buffer, int64(len(outputs)))
+	for _, output := range outputs {
+		writeInt(&buffer, int64(output.Value))
+		writeBytes(&buffer, output.PubKey.Bytes())
+		writeInt(&buffer, output.Lock.Height)
+		writeInt(&buffer, output.Lock.Time)
+	}
+
+	writeInt(&buffer, txn.Height)
+
+	return common.Hash256(buffer.Bytes()), nil
+}
+
+// signature represents the parsed signature of a Transaction input. Signatures are placeholders for
+// real signatures, made of the signer Address followed by the signature hash and its SigHashType.
+type signature struct {
+	Signer common.Address
+	Hash   common.Hash
+	Flag   SigHashType
+
+	// Bare is whether the signature is only the signer Address, which commits to nothing.
+	// Bare signatures predate signature hashes and remain valid.
+	Bare bool
+}
+
+// encodeSignature returns the placeholder signature of a signer for a signature hash and SigHashType
+func encodeSignature(signer common.Address, hash common.Hash, flag SigHashType) common.Address {
+	return common.Address(fmt.Sprintf("%s%s%x%02x", signer, sigSeparator, hash.Bytes(), uint8(flag)))
+}
+
+// parseSignature parses the signature of a Transaction input.
+// A signature without a valid signature hash suffix is a bare signature by the whole signature.
+func parseSignature(sig common.Address) signature {
+	raw := string(sig)
+
+	split := strings.LastIndex(raw, sigSeparator)
+	if split < 0 {
+		return signature{Signer: sig, Bare: true}
+	}
+
+	data, err := hex.DecodeString(raw[split+len(sigSeparator):])
+	if err != nil || len(data) != common.HashLength+1 {
+		return signature{Signer: sig, Bare: true}
+	}
+
+	return signature{
+		Signer: common.Address(raw[:split]),
+		Hash:   common.BytesToHash(data[:common.HashLength]),
+		Flag:   SigHashType(data[common.HashLength]),
+	}
+}
+
+// SigHashType returns the SigHashType of the signature of the input, which is 0 for a bare signature
+func (in *TxInput) SigHashType() SigHashType {
+	return parseSignature(in.Sig).Flag
+}
+
+// verifySignature checks that the input at the given index is signed by the owner Address,
+// with a signature that commits to the Transaction for its SigHashType
+func (txn *Transaction) verifySignature(idx int, owner common.Address) error {
+	sig := parseSignature(txn.Inputs[idx].Sig)
+	if sig.Signer != owner {
+		return fmt.Errorf("%w %v", ErrSignatureMismatch, owner)
+	}
+
+	if sig.Bare {
+		return nil
+	}
+
+	hash, err := txn.SignatureHash(idx, sig.Flag)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSignatureInvalid, err)
+	}
+
+	if !hash.Equal(sig.Hash) {
+		return fmt.Errorf("%w for %v", ErrSignatureInvalid, sig.Flag)
+	}
+
+	return nil
+}
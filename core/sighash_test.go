@@ -0,0 +1,135 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestSigHashType checks the validity and names of the SigHashTypes
+func TestSigHashType(t *testing.T) {
+	tests := []struct {
+		flag  SigHashType
+		valid bool
+		name  string
+	}{
+		{SigHashAll, true, "ALL"},
+		{SigHashNone, true, "NONE"},
+		{SigHashSingle | SigHashAnyoneCanPay, true, "SINGLE|ANYONECANPAY"},
+		{0, false, "SigHashType(0x00)"},
+		{SigHashAll | 0x40, false, "ALL"},
+	}
+
+	for _, test := range tests {
+		if test.flag.Valid() != test.valid || test.flag.String() != test.name {
+			t.Errorf("expected %#02x to be valid: %v with name %v, got %v and %v", uint8(test.flag), test.valid, test.name, test.flag.Valid(), test.flag)
+		}
+	}
+}
+
+// TestSignTransactionHash checks that signatures with the default SigHashAll commit to every input and output,
+// while signatures with SigHashAnyoneCanPay and SigHashNone remain valid when the uncommitted parts change
+func TestSignTransactionHash(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+
+	funding := signedTransaction(t, common.MinerAddress(), []Outpoint{genesisOutpoint(t, chain)},
+		TxOutput{Value: 50, PubKey: "alice"}, TxOutput{Value: 40, PubKey: "bob"})
+	if err := chain.MineBlock(Transactions{funding}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	alice := TxInput{funding.ID, 0, common.NullAddress()}
+	bob := TxInput{funding.ID, 1, common.NullAddress()}
+
+	// A signature with SigHashAll is invalidated by a change to the outputs
+	txn := &Transaction{Inputs: []TxInput{alice}, Outputs: []TxOutput{{Value: 50, PubKey: "carol"}}}
+	if err := SignTransaction(txn, NewWallet("alice")); err != nil {
+		t.Fatal(err)
+	}
+
+	if flag := txn.Inputs[0].SigHashType(); flag != SigHashAll {
+		t.Fatalf("expected the default signature hash type %v, got %v", SigHashAll, flag)
+	}
+
+	if err := chain.VerifyTransaction(txn); err != nil {
+		t.Fatalf("expected the signature to be valid, got %v", err)
+	}
+
+	txn.Outputs[0].PubKey = "mallory"
+	if err := txn.SetID(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.VerifyTransaction(txn); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatalf("expected the changed output to invalidate the signature, got %v", err)
+	}
+
+	// A signature with SigHashAll|SigHashAnyoneCanPay remains valid when another input is added and signed
+	txn = &Transaction{Inputs: []TxInput{alice}, Outputs: []TxOutput{{Value: 90, PubKey: "carol"}}}
+	if err := SignTransactionHash(txn, NewWallet("alice"), SigHashAll|SigHashAnyoneCanPay); err != nil {
+		t.Fatal(err)
+	}
+
+	txn.Inputs = append(txn.Inputs, bob)
+	if err := SignTransaction(txn, NewWallet("bob")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.VerifyTransaction(txn); err != nil {
+		t.Fatalf("expected the collaborative transaction to be valid, got %v", err)
+	}
+
+	// A signature with SigHashAll is invalidated when another input is added
+	txn = &Transaction{Inputs: []TxInput{alice}, Outputs: []TxOutput{{Value: 90, PubKey: "carol"}}}
+	if err := SignTransaction(txn, NewWallet("alice")); err != nil {
+		t.Fatal(err)
+	}
+
+	txn.Inputs = append(txn.Inputs, bob)
+	if err := SignTransaction(txn, NewWallet("bob")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.VerifyTransaction(txn); !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatalf("expected the added input to invalidate the signature, got %v", err)
+	}
+
+	// A signature with SigHashNone remains valid when the outputs change
+	txn = &Transaction{Inputs: []TxInput{alice}, Outputs: []TxOutput{{Value: 50, PubKey: "carol"}}}
+	if err := SignTransactionHash(txn, NewWallet("alice"), SigHashNone); err != nil {
+		t.Fatal(err)
+	}
+
+	txn.Outputs[0].PubKey = "dave"
+	if err := txn.SetID(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.VerifyTransaction(txn); err != nil {
+		t.Fatalf("expected the signature to commit to no outputs, got %v", err)
+	}
+}
+
+// TestSignTransactionHashSingle checks that SigHashSingle cannot sign an input without a matching output,
+// in which case no input of the Transaction is signed
+func TestSignTransactionHashSingle(t *testing.T) {
+	txn := &Transaction{
+		Inputs:  []TxInput{{common.Hash256([]byte("first")), 0, common.NullAddress()}, {common.Hash256([]byte("second")), 0, common.NullAddress()}},
+		Outputs: []TxOutput{{Value: 10, PubKey: "carol"}},
+	}
+
+	if err := SignTransactionHash(txn, NewWallet("alice"), SigHashSingle); err == nil {
+		t.Fatalf("expected an error for an input without a matching output")
+	}
+
+	for idx, input := range txn.Inputs {
+		if input.Sig != common.NullAddress() {
+			t.Fatalf("expected input %v to remain unsigned, got %v", idx, input.Sig)
+		}
+	}
+
+	if _, err := txn.SignatureHash(0, SigHashType(0)); err == nil {
+		t.Fatalf("expected an error for an invalid signature hash type")
+	}
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"github.com/anee769/essensio/common"
+)
+
+const (
+	// MaxBlockDataSize is the maximum size in bytes of the serialized data of a Block that can be deserialized
+	MaxBlockDataSize = 32 << 20
+	// MaxHeaderDataSize is the maximum size in bytes of the serialized data of a BlockHeader that can be deserialized
+	MaxHeaderDataSize = 4 << 10
+	// MaxTxDataSize is the maximum size in bytes of the serialized data of a Transaction that can be deserialized
+	MaxTxDataSize = 1 << 20
+)
+
+// EstimatedAddressLength is the Address length assumed by EstimateTxSize for every input signer and output
+const EstimatedAddressLength = 32
+
+// Size returns the serialized size of the Transaction in bytes, which is the length of its Serialize output.
+// Returns 0 if the Transaction cannot be serialized.
+func (txn *Transaction) Size() int {
+	data, err := txn.Serialize()
+	if err != nil {
+		return 0
+	}
+
+	return len(data)
+}
+
+// checkBlockSize returns an error if the serialized data of a Block exceeds MaxBlockDataSize,
+// so that a Block is never stored that cannot be deserialized again
+func checkBlockSize(block *Block) error {
+	data, err := block.Serialize()
+	if err != nil {
+		return fmt.Errorf("block serialize failed: %w", err)
+	}
+
+	if size := len(data); size > MaxBlockDataSize {
+		return fmt.Errorf("block size %v exceeds maximum block data size %v", size, MaxBlockDataSize)
+	}
+
+	return nil
+}
+
+// EstimateTxSize returns the serialized size in bytes of a non-coinbase Transaction with the given
+// number of inputs and outputs, before it is built. The encoding of a Transaction varies with its
+// values, so the estimate is the size of the widest such Transaction and is an upper bound on the
+// Size of any Transaction of that shape with Addresses no longer than EstimatedAddressLength.
+func EstimateTxSize(inputs, outputs int) int {
+	var id common.Hash
+	for idx := range id {
+		id[idx] = math.MaxUint8
+	}
+
+	address := common.Address(bytes.Repeat([]byte{math.MaxUint8}, EstimatedAddressLength))
+	sig := encodeSignature(address, id, SigHashAll)
+
+	// Build a template Transaction with the widest encoding of every field
+	txn := Transaction{ID: id}
+	for idx := 0; idx < inputs; idx++ {
+		txn.Inputs = append(txn.Inputs, TxInput{id, math.MinInt, sig})
+	}
+
+	for idx := 0; idx < outputs; idx++ {
+		txn.Outputs = append(txn.Outputs, TxOutput{math.MinInt, address, OutputLock{math.MinInt64, math.MinInt64}})
+	}
+
+	return txn.Size()
+}
@@ -0,0 +1,160 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestEstimateTxSize checks that the estimated size of Transactions of several shapes is an upper bound
+// on the Size of signed Transactions of that shape, and that Size is the length of their serialized data
+func TestEstimateTxSize(t *testing.T) {
+	shapes := []struct{ inputs, outputs int }{{1, 1}, {1, 2}, {2, 1}, {3, 3}, {10, 2}}
+
+	for _, shape := range shapes {
+		spends := make([]Outpoint, shape.inputs)
+		for idx := range spends {
+			spends[idx] = Outpoint{common.Hash256([]byte(fmt.Sprint(idx))), idx}
+		}
+
+		outputs := make([]TxOutput, shape.outputs)
+		for idx := range outputs {
+			address := common.Address(strings.Repeat("a", EstimatedAddressLength))
+			outputs[idx] = TxOutput{Value: 1 << 40, PubKey: address, Lock: OutputLock{Height: 1 << 40}}
+		}
+
+		txn := signedTransaction(t, common.Address(strings.Repeat("m", EstimatedAddressLength)), spends, outputs...)
+
+		data, err := txn.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if size := txn.Size(); size != len(data) {
+			t.Fatalf("%v inputs and %v outputs: expected the size %v to be the serialized length %v", shape.inputs, shape.outputs, size, len(data))
+		}
+
+		if estimate := EstimateTxSize(shape.inputs, shape.outputs); estimate < len(data) {
+			t.Fatalf("%v inputs and %v outputs: expected the estimate %v to be at least the serialized length %v", shape.inputs, shape.outputs, estimate, len(data))
+		}
+	}
+
+	if EstimateTxSize(2, 1) <= EstimateTxSize(1, 1) || EstimateTxSize(1, 2) <= EstimateTxSize(1, 1) {
+		t.Fatalf("expected the estimate to grow with the inputs and outputs")
+	}
+}
+
+// TestMaxTxSize checks that Transactions larger than the configured maximum size are rejected
+func TestMaxTxSize(t *testing.T) {
+	opts := testChainOptions(0)
+	opts.Options.MaxTxSize = 128
+
+	chain := newTestChain(t, opts)
+
+	spend := signedTransaction(t, common.MinerAddress(), []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: 90, PubKey: "alice"})
+	if size := spend.Size(); size <= opts.Options.MaxTxSize {
+		t.Fatalf("expected the spend to exceed the maximum size, got a size of %v", size)
+	}
+
+	if err := chain.SubmitTransaction(spend); err == nil || !strings.Contains(err.Error(), "exceeds maximum transaction size") {
+		t.Fatalf("expected the transaction to exceed the maximum size, got %v", err)
+	}
+}
+
+// TestDeserializeOversized checks that Blocks, BlockHeaders and Transactions reject data beyond their size limits
+// with ErrDataTooLarge before decoding it
+func TestDeserializeOversized(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       int
+		deserialize func([]byte) error
+	}{
+		{"block", MaxBlockDataSize, new(Block).Deserialize},
+		{"header", MaxHeaderDataSize, new(BlockHeader).Deserialize},
+		{"transaction", MaxTxDataSize, new(Transaction).Deserialize},
+	}
+
+	for _, test := range tests {
+		if err := test.deserialize(make([]byte, test.limit+1)); !errors.Is(err, common.ErrDataTooLarge) {
+			t.Errorf("%v: expected oversized data to be rejected, got %v", test.name, err)
+		}
+	}
+}
+
+// oversizedOutput returns an output whose Address alone exceeds MaxTxDataSize,
+// which is only valid for an unlimited maximum address length
+func oversizedOutput(value int) TxOutput {
+	return TxOutput{Value: value, PubKey: common.Address(strings.Repeat("a", MaxTxDataSize))}
+}
+
+// TestMaxTxDataSize checks that Transactions and coinbases that exceed MaxTxDataSize, and so could not be
+// deserialized, are rejected when they are verified, mined and assembled from the Mempool
+func TestMaxTxDataSize(t *testing.T) {
+	opts := testChainOptions(0)
+	opts.Options.MaxAddressLength = 0
+
+	chain := newTestChain(t, opts)
+	miner := common.MinerAddress()
+
+	spend := signedTransaction(t, miner, []Outpoint{genesisOutpoint(t, chain)}, oversizedOutput(90))
+	coinbase := coinbaseOf(chain, oversizedOutput(BlockReward))
+
+	for _, txn := range []*Transaction{spend, coinbase} {
+		if err := chain.VerifyTransaction(txn); err == nil || !strings.Contains(err.Error(), "exceeds maximum transaction data size") {
+			t.Fatalf("expected the oversized transaction to be rejected, got %v", err)
+		}
+	}
+
+	if err := chain.MineBlock(Transactions{spend}, ""); err == nil {
+		t.Fatalf("expected a block with an oversized transaction to be rejected")
+	}
+
+	// A pending Transaction that is no longer valid is evicted instead of being mined
+	if err := chain.Mempool().Add(spend, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	assembly, err := chain.MinePending("")
+	if err != nil {
+		t.Fatalf("failed to mine pending transactions: %v", err)
+	}
+
+	if len(assembly.Included) != 0 || len(assembly.Rejected) != 1 || chain.Mempool().Size() != 0 {
+		t.Fatalf("expected the oversized transaction to be rejected and evicted, got %+v", assembly)
+	}
+}
+
+// TestMaxBlockDataSize checks that a Block that exceeds MaxBlockDataSize, and so could not be
+// deserialized, is rejected before it is stored
+func TestMaxBlockDataSize(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	head, height := chain.Head, chain.Height
+
+	bits, err := chain.nextBits()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each Transaction is within MaxTxDataSize, but together they exceed MaxBlockDataSize
+	txns := Transactions{coinbaseOf(chain, TxOutput{Value: BlockReward, PubKey: common.MinerAddress()})}
+	for idx := 0; idx <= MaxBlockDataSize/MaxTxDataSize; idx++ {
+		txn := &Transaction{
+			Inputs:  []TxInput{{common.Hash256([]byte(fmt.Sprint(idx))), 0, "signature"}},
+			Outputs: []TxOutput{{Value: 1, PubKey: common.Address(strings.Repeat("a", MaxTxDataSize-1024))}},
+		}
+
+		txn.SetID()
+		txns = append(txns, txn)
+	}
+
+	if err := chain.AcceptBlock(NewBlock(txns, head, height, bits)); err == nil || !strings.Contains(err.Error(), "exceeds maximum block data size") {
+		t.Fatalf("expected the oversized block to be rejected, got %v", err)
+	}
+
+	if !chain.Head.Equal(head) || chain.Height != height {
+		t.Fatalf("expected the chain head to be unchanged by the oversized block")
+	}
+}
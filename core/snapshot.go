@@ -0,0 +1,201 @@
+package core
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/anee769/essensio/common"
+)
+
+// SnapshotPrefix is the key prefix for the balance snapshots of the chain in the database.
+// Snapshots are derived from the UTXO set, so they are cleared and rebuilt by reindexing.
+var SnapshotPrefix = []byte("snapshot-")
+
+// balanceSnapshot represents the balance of every Address after the Block with the given hash.
+// The balances are stored sorted by Address, so that a snapshot of the same state always serializes
+// to the same bytes. Balances holds the balances of snapshots stored before they were sorted.
+type balanceSnapshot struct {
+	Hash     common.Hash
+	Entries  []common.MapEntry[common.Address, int]
+	Balances map[common.Address]int
+}
+
+// snapshotKey returns the database key for the balance snapshot at the given height
+func snapshotKey(height int64) []byte {
+	key := make([]byte, len(SnapshotPrefix)+8)
+	copy(key, SnapshotPrefix)
+	binary.BigEndian.PutUint64(key[len(SnapshotPrefix):], uint64(height))
+
+	return key
+}
+
+// snapshotBalances stores a balance snapshot for a Block that has just been applied to the UTXO set,
+// if its height is a multiple of Options.SnapshotInterval. The Genesis Block is never snapshotted.
+func (chain *ChainManager) snapshotBalances(block *Block) error {
+	interval := chain.opts.SnapshotInterval
+	if interval <= 0 || block.BlockHeight == 0 || block.BlockHeight%interval != 0 {
+		return nil
+	}
+
+	// The UTXO set lock is already held for writing by the caller connecting the Block
+	balances, err := chain.allBalances(context.Background())
+	if err != nil {
+		return err
+	}
+
+	data, err := common.GobEncode(balanceSnapshot{Hash: block.BlockHash, Entries: common.SortedEntries(balances)})
+	if err != nil {
+		return fmt.Errorf("balance snapshot serialize failed: %w", err)
+	}
+
+	if err := chain.db.SetEntry(snapshotKey(block.BlockHeight), data); err != nil {
+		return fmt.Errorf("balance snapshot store failed: %w", err)
+	}
+
+	return nil
+}
+
+// loadSnapshot returns the balance snapshot at the given height and whether it exists.
+// A snapshot of a Block that is no longer on the chain is treated as missing.
+func (chain *ChainManager) loadSnapshot(height int64) (map[common.Address]int, bool, error) {
+	key := snapshotKey(height)
+
+	exists, err := chain.db.HasEntry(key)
+	if err != nil || !exists {
+		return nil, false, err
+	}
+
+	data, err := chain.db.GetEntry(key)
+	if err != nil {
+		return nil, false, fmt.Errorf("balance snapshot retrieve failed: %w", err)
+	}
+
+	object, err := common.GobDecode(data, new(balanceSnapshot))
+	if err != nil {
+		return nil, false, fmt.Errorf("balance snapshot deserialize failed: %w", err)
+	}
+
+	hash, err := chain.BlockHashAtHeight(height)
+	if err != nil {
+		return nil, false, err
+	}
+
+	snapshot := object.(*balanceSnapshot)
+	if !snapshot.Hash.Equal(hash) {
+		return nil, false, nil
+	}
+
+	if snapshot.Balances != nil {
+		return snapshot.Balances, true, nil
+	}
+
+	return common.EntriesMap(snapshot.Entries), true, nil
+}
+
+// StateAt returns the balance of every Address with a nonzero balance after the Block at the given height.
+// The balances are replayed from the nearest balance snapshot at or below the height, or from the Genesis
+// Block if there is none, so their cost depends on Options.SnapshotInterval.
+func (chain *ChainManager) StateAt(height int64) (map[common.Address]int, error) {
+	if height < 0 || height >= chain.Height {
+		return nil, fmt.Errorf("height %v out of range for chain height %v", height, chain.Height)
+	}
+
+	// The UTXO set has the balances of the chain head
+	if height == chain.Height-1 {
+		return chain.AllBalances()
+	}
+
+	// Find the nearest snapshot at or below the height
+	balances, from := make(map[common.Address]int), int64(-1)
+	if interval := chain.opts.SnapshotInterval; interval > 0 {
+		for snapshot := height - height%interval; snapshot > 0; snapshot -= interval {
+			loaded, exists, err := chain.loadSnapshot(snapshot)
+			if err != nil {
+				return nil, err
+			}
+
+			if exists {
+				balances, from = loaded, snapshot
+				break
+			}
+		}
+	}
+
+	// Replay the Blocks after the snapshot up to the height
+	for current := from + 1; current <= height; current++ {
+		block, err := chain.GetBlockByHeight(current)
+		if err != nil {
+			return nil, fmt.Errorf("block at height %v retrieve failed: %w", current, err)
+		}
+
+		deltas, err := BlockDeltas(block, chain)
+		if err != nil {
+			return nil, fmt.Errorf("block at height %v: %w", current, err)
+		}
+
+		for address, delta := range deltas {
+			balances[address] += delta
+		}
+	}
+
+	// Remove any addresses without a balance
+	for address, balance := range balances {
+		if balance == 0 {
+			delete(balances, address)
+		}
+	}
+
+	return balances, nil
+}
+
+// BalanceAt returns the balance of an Address after the Block at the given height, as computed by StateAt
+func (chain *ChainManager) BalanceAt(address common.Address, height int64) (int, error) {
+	balances, err := chain.StateAt(height)
+	if err != nil {
+		return 0, err
+	}
+
+	return balances[address], nil
+}
+
+// StateDiff returns the net change in balance of every Address between the Blocks at the given heights,
+// which is the sum of the BlockDeltas of the Blocks above the earlier height up to the later height, and equals
+// its balance in StateAt the later height minus that in StateAt the earlier height. Its cost is proportional
+// to the number of Blocks between the heights. Addresses whose balance is unchanged are omitted.
+// Returns an error if from is above to or either height is out of range.
+func (chain *ChainManager) StateDiff(from, to int64) (map[common.Address]int, error) {
+	if from > to {
+		return nil, fmt.Errorf("from height %v is above to height %v", from, to)
+	}
+
+	if from < 0 || to >= chain.Height {
+		return nil, fmt.Errorf("heights %v to %v out of range for chain height %v", from, to, chain.Height)
+	}
+
+	diff := make(map[common.Address]int)
+	for current := from + 1; current <= to; current++ {
+		block, err := chain.GetBlockByHeight(current)
+		if err != nil {
+			return nil, fmt.Errorf("block at height %v retrieve failed: %w", current, err)
+		}
+
+		deltas, err := BlockDeltas(block, chain)
+		if err != nil {
+			return nil, fmt.Errorf("block at height %v: %w", current, err)
+		}
+
+		for address, delta := range deltas {
+			diff[address] += delta
+		}
+	}
+
+	// Remove any addresses whose balance is unchanged
+	for address, delta := range diff {
+		if delta == 0 {
+			delete(diff, address)
+		}
+	}
+
+	return diff, nil
+}
@@ -0,0 +1,254 @@
+package core
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// equalBalances returns whether two sets of balances are equal, ignoring zero balances
+func equalBalances(a, b map[common.Address]int) bool {
+	for address, balance := range a {
+		if b[address] != balance {
+			return false
+		}
+	}
+
+	for address, balance := range b {
+		if a[address] != balance {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TestStateAtSnapshots checks that the balances replayed from the balance snapshots equal the balances of a full scan of
+// the UTXO set at every height, which is obtained by disconnecting the Blocks above it
+func TestStateAtSnapshots(t *testing.T) {
+	opts := testChainOptions(10)
+	opts.Options.SnapshotInterval = 3
+
+	chain := newTestChain(t, opts)
+
+	for _, height := range []int64{3, 6, 9} {
+		if _, exists, err := chain.loadSnapshot(height); err != nil || !exists {
+			t.Fatalf("expected a balance snapshot at height %v, got %v", height, err)
+		}
+	}
+
+	if _, exists, err := chain.loadSnapshot(4); err != nil || exists {
+		t.Fatalf("expected no balance snapshot between intervals, got %v", err)
+	}
+
+	states := make([]map[common.Address]int, chain.Height)
+	for height := range states {
+		state, err := chain.StateAt(int64(height))
+		if err != nil {
+			t.Fatalf("failed to compute the state at height %v: %v", height, err)
+		}
+
+		states[height] = state
+	}
+
+	for height := chain.Height - 1; height >= 0; height-- {
+		balances, err := chain.AllBalances()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !equalBalances(states[height], balances) {
+			t.Fatalf("expected the state at height %v to equal the full scan %v, got %v", height, balances, states[height])
+		}
+
+		if height > 0 {
+			if _, err := chain.disconnectTip(); err != nil {
+				t.Fatalf("failed to disconnect block: %v", err)
+			}
+		}
+	}
+}
+
+// snapshotData returns the stored bytes of every balance snapshot of the chain by height
+func snapshotData(t *testing.T, chain *ChainManager) map[int64][]byte {
+	t.Helper()
+
+	snapshots := make(map[int64][]byte)
+	for height := int64(1); height < chain.Height; height++ {
+		exists, err := chain.db.HasEntry(snapshotKey(height))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !exists {
+			continue
+		}
+
+		data, err := chain.db.GetEntry(snapshotKey(height))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		snapshots[height] = data
+	}
+
+	return snapshots
+}
+
+// TestSnapshotDeterministic checks that the balance snapshots rebuilt by reindexing the chain serialize to the same
+// bytes every time, and that snapshots stored with a map of balances are still loaded
+func TestSnapshotDeterministic(t *testing.T) {
+	opts := testChainOptions(6)
+	opts.Options.SnapshotInterval = 2
+
+	chain := newTestChain(t, opts)
+
+	stored := snapshotData(t, chain)
+	if len(stored) != 3 {
+		t.Fatalf("expected 3 balance snapshots, got %v", len(stored))
+	}
+
+	for run := 0; run < 3; run++ {
+		if err := chain.reindex(); err != nil {
+			t.Fatalf("failed to reindex chain: %v", err)
+		}
+
+		for height, data := range snapshotData(t, chain) {
+			if !bytes.Equal(data, stored[height]) {
+				t.Fatalf("expected identical bytes for the snapshot at height %v on run %v", height, run)
+			}
+		}
+	}
+
+	balances, exists, err := chain.loadSnapshot(2)
+	if err != nil || !exists {
+		t.Fatalf("failed to load snapshot: %v", err)
+	}
+
+	// Replace the snapshot with one that stores the balances as a map
+	hash, err := chain.BlockHashAtHeight(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := common.GobEncode(balanceSnapshot{Hash: hash, Balances: balances})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.db.SetEntry(snapshotKey(2), data); err != nil {
+		t.Fatal(err)
+	}
+
+	legacy, exists, err := chain.loadSnapshot(2)
+	if err != nil || !exists || !equalBalances(legacy, balances) {
+		t.Fatalf("expected the snapshot with a map of balances to be loaded, got %v", err)
+	}
+}
+
+// TestStateDiff checks that the state diff between any two heights equals the sum of the BlockDeltas
+// of the Blocks above the first height up to the second height, across balance snapshots
+func TestStateDiff(t *testing.T) {
+	opts := testChainOptions(8)
+	opts.Options.SnapshotInterval = 3
+
+	chain := newTestChain(t, opts)
+
+	for from := int64(0); from < chain.Height; from++ {
+		sum := make(map[common.Address]int)
+
+		for to := from; to < chain.Height; to++ {
+			if to > from {
+				block, err := chain.GetBlockByHeight(to)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				deltas, err := BlockDeltas(block, chain)
+				if err != nil {
+					t.Fatalf("failed to compute deltas at height %v: %v", to, err)
+				}
+
+				for address, delta := range deltas {
+					sum[address] += delta
+				}
+			}
+
+			diff, err := chain.StateDiff(from, to)
+			if err != nil {
+				t.Fatalf("failed to compute the state diff from %v to %v: %v", from, to, err)
+			}
+
+			for address, delta := range diff {
+				if delta == 0 {
+					t.Fatalf("expected the unchanged balance of '%v' to be omitted from %v to %v", address, from, to)
+				}
+			}
+
+			if !equalBalances(diff, sum) {
+				t.Fatalf("expected the state diff from %v to %v to equal the summed deltas %v, got %v", from, to, sum, diff)
+			}
+
+			before, err := chain.StateAt(from)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			after, err := chain.StateAt(to)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for address, balance := range after {
+				if diff[address] != balance-before[address] {
+					t.Fatalf("expected the state diff of '%v' from %v to %v to be %v, got %v", address, from, to, balance-before[address], diff[address])
+				}
+			}
+		}
+	}
+
+	if _, err := chain.StateDiff(2, 1); err == nil {
+		t.Fatalf("expected an error for a from height above the to height")
+	}
+
+	if _, err := chain.StateDiff(0, chain.Height); err == nil {
+		t.Fatalf("expected an error for a to height above the chain head")
+	}
+}
+
+// TestStateDiffRecent checks that the state diff between two recent heights only reads the Blocks between them,
+// without replaying the chain from the Genesis Block, which is removed from the database
+func TestStateDiffRecent(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+
+	for idx := 0; idx < 4; idx++ {
+		if err := chain.MineBlock(nil, ""); err != nil {
+			t.Fatalf("failed to mine block: %v", err)
+		}
+	}
+
+	for _, height := range []int64{0, 1} {
+		hash, err := chain.BlockHashAtHeight(height)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := chain.db.DeleteEntry(hash.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := chain.StateAt(3); err == nil {
+		t.Fatalf("expected the replay from the removed genesis block to fail")
+	}
+
+	diff, err := chain.StateDiff(2, 4)
+	if err != nil {
+		t.Fatalf("failed to compute the state diff: %v", err)
+	}
+
+	if miner := common.MinerAddress(); len(diff) != 1 || diff[miner] != 2*BlockReward {
+		t.Fatalf("expected a state diff of %v for the miner, got %v", 2*BlockReward, diff)
+	}
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"sync"
+
+	"github.com/anee769/essensio/common"
+)
+
+// spendLocks is a set of locks on the coin selection of each Address
+type spendLocks struct {
+	mutex sync.Mutex
+	locks map[common.Address]*spendLock
+}
+
+// spendLock is the lock of a single Address, with the number of holders and waiters that reference it
+type spendLock struct {
+	mutex sync.Mutex
+	refs  int
+}
+
+// LockSpends locks the coin selection of an Address until the returned function is called, so that concurrent
+// builders of Transactions from the Address select outputs one at a time. A builder that holds the lock until its
+// Transaction is submitted to the Mempool guarantees that the next builder does not select the same outputs, as
+// outputs spent by pending Transactions are not selected by FindSpendableOutputs.
+func (chain *ChainManager) LockSpends(address common.Address) (unlock func()) {
+	spends := &chain.spends
+
+	spends.mutex.Lock()
+	if spends.locks == nil {
+		spends.locks = make(map[common.Address]*spendLock)
+	}
+
+	lock, exists := spends.locks[address]
+	if !exists {
+		lock = new(spendLock)
+		spends.locks[address] = lock
+	}
+
+	lock.refs++
+	spends.mutex.Unlock()
+
+	lock.mutex.Lock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			lock.mutex.Unlock()
+
+			spends.mutex.Lock()
+			defer spends.mutex.Unlock()
+
+			// Remove the lock once it has no holders or waiters
+			if lock.refs--; lock.refs == 0 {
+				delete(spends.locks, address)
+			}
+		})
+	}
+}
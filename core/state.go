@@ -0,0 +1,129 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/anee769/essensio/common"
+)
+
+// ChainState represents the state of the chain head, stored in the database
+// as a single entry so that the head, height and work are always updated together.
+type ChainState struct {
+	// Represents the hash of the last Block
+	Head common.Hash
+	// Represents the Height of the chain. Last block Height+1
+	Height int64
+	// Represents the cumulative Proof of Work of the Blocks on the chain
+	Work *big.Int
+}
+
+// BlockWork returns the expected number of hashes required to meet the given compact PoW target.
+// This is 2^256 / (target+1), and is zero for an invalid target.
+func BlockWork(bits uint32) *big.Int {
+	target := CompactToTarget(bits)
+	if target.Sign() <= 0 {
+		return new(big.Int)
+	}
+
+	work := new(big.Int).Lsh(big.NewInt(1), 256)
+	return work.Div(work, target.Add(target, big.NewInt(1)))
+}
+
+// State returns the current ChainState of the chain
+func (chain *ChainManager) State() ChainState {
+	return ChainState{chain.Head, chain.Height, new(big.Int).Set(chain.Work)}
+}
+
+// writeState stores the current ChainState in the DB at the key specified by ChainStateKey
+func (chain *ChainManager) writeState() error {
+	data, err := common.GobEncode(chain.State())
+	if err != nil {
+		return fmt.Errorf("chain state serialize failed: %w", err)
+	}
+
+	return chain.db.SetEntry(ChainStateKey, data)
+}
+
+// loadState loads the ChainState from the DB at the key specified by ChainStateKey.
+// Databases that store the chain head and height at the legacy keys are loaded from those keys
+// without the chain work. Their Blocks must be rewritten with migrateLegacyBlocks if they are in the legacy
+// block format, and the migration must be completed with finishMigration once the head is verified.
+func (chain *ChainManager) loadState() (legacy bool, err error) {
+	exists, err := chain.db.HasEntry(ChainStateKey)
+	if err != nil {
+		return false, err
+	}
+
+	if !exists {
+		return true, chain.loadLegacyState()
+	}
+
+	data, err := chain.db.GetEntry(ChainStateKey)
+	if err != nil {
+		return false, err
+	}
+
+	object, err := common.GobDecode(data, new(ChainState))
+	if err != nil {
+		return false, fmt.Errorf("chain state deserialize failed: %w", err)
+	}
+
+	state := object.(*ChainState)
+	if state.Work == nil {
+		state.Work = new(big.Int)
+	}
+
+	chain.Head, chain.Height, chain.Work = state.Head, state.Height, state.Work
+	return false, nil
+}
+
+// loadLegacyState loads the chain head and height from the legacy keys
+func (chain *ChainManager) loadLegacyState() error {
+	// Get the chain head
+	head, err := chain.db.GetEntry(ChainHeadKey)
+	if err != nil {
+		return fmt.Errorf("chain head retrieve failed: %w", err)
+	}
+
+	// Get the chain height and deserialize it into an int64
+	data, err := chain.db.GetEntry(ChainHeightKey)
+	if err != nil {
+		return fmt.Errorf("chain height retrieve failed: %w", err)
+	}
+
+	height, err := common.GobDecode(data, new(int64))
+	if err != nil {
+		return fmt.Errorf("error deserializing chain height: %w", err)
+	}
+
+	chain.Head, chain.Height = common.BytesToHash(head), *height.(*int64)
+	return nil
+}
+
+// finishMigration rebuilds the UTXO set and indexes of the chain, recounts its statistics and work, stores the
+// chain state as a single ChainState and removes the legacy keys of the chain head and height. The chain is
+// always reindexed, so that a migration interrupted after migrateLegacyBlocks completes when it is reopened.
+func (chain *ChainManager) finishMigration() error {
+	if err := chain.reindex(); err != nil {
+		return fmt.Errorf("reindex failed: %w", err)
+	}
+
+	// Recount the statistics and work of every Block on the chain
+	if err := chain.recountStats(); err != nil {
+		return fmt.Errorf("statistics recount failed: %w", err)
+	}
+
+	if err := chain.syncState(); err != nil {
+		return fmt.Errorf("chain state sync failed: %w", err)
+	}
+
+	for _, key := range [][]byte{ChainHeadKey, ChainHeightKey} {
+		if err := chain.db.DeleteEntry(key); err != nil {
+			return fmt.Errorf("legacy chain state remove failed: %w", err)
+		}
+	}
+
+	chain.opts.Logger.Infof("Migrated chain state at height %v to a single entry.", chain.Height)
+	return nil
+}
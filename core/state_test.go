@@ -0,0 +1,119 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/db"
+)
+
+// equalStates returns whether two ChainStates have the same head, height and work
+func equalStates(a, b ChainState) bool {
+	return a.Head.Equal(b.Head) && a.Height == b.Height && a.Work.Cmp(b.Work) == 0
+}
+
+// TestChainStateReadBack checks that the chain state is read back from its single entry as it was written
+func TestChainStateReadBack(t *testing.T) {
+	chain := generateTestChain(t, testChainOptions(3))
+	state := chain.State()
+	chain.Stop()
+
+	if state.Height != 4 || state.Work.Sign() <= 0 {
+		t.Fatalf("expected a chain state of height 4 with nonzero work, got %+v", state)
+	}
+
+	chain = openTestChain(t, DefaultOptions())
+	if reopened := chain.State(); !equalStates(reopened, state) {
+		t.Fatalf("expected the chain state %+v to read back as written, got %+v", state, reopened)
+	}
+
+	for _, key := range [][]byte{ChainHeadKey, ChainHeightKey} {
+		if exists, err := chain.db.HasEntry(key); err != nil || exists {
+			t.Fatalf("expected no legacy chain state key %q, got %v", key, err)
+		}
+	}
+}
+
+// TestMigrateTwoKeyState checks that a chain state stored at the two legacy keys
+// is migrated to a single entry with the same head and height, and the recounted work
+func TestMigrateTwoKeyState(t *testing.T) {
+	chain := generateTestChain(t, testChainOptions(3))
+	state := chain.State()
+	chain.Stop()
+
+	// Replace the chain state entry with the legacy keys of the chain head and height
+	database, err := db.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	height, err := common.GobEncode(state.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, err := range []error{
+		database.DeleteEntry(ChainStateKey),
+		database.SetEntry(ChainHeadKey, state.Head.Bytes()),
+		database.SetEntry(ChainHeightKey, height),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	database.Close()
+
+	chain = openTestChain(t, DefaultOptions())
+	if migrated := chain.State(); !equalStates(migrated, state) {
+		t.Fatalf("expected the migrated chain state to be %+v, got %+v", state, migrated)
+	}
+
+	if exists, err := chain.db.HasEntry(ChainStateKey); err != nil || !exists {
+		t.Fatalf("expected the migrated chain state entry to exist, got %v", err)
+	}
+
+	for _, key := range [][]byte{ChainHeadKey, ChainHeightKey} {
+		if exists, err := chain.db.HasEntry(key); err != nil || exists {
+			t.Fatalf("expected the legacy chain state key %q to be removed, got %v", key, err)
+		}
+	}
+
+	audit, err := chain.AuditUTXO()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !audit.Consistent() {
+		t.Fatalf("expected the utxo set of the migrated chain to be consistent, got %v discrepancies", len(audit.Discrepancies))
+	}
+}
+
+// TestMigrateLegacyState checks that the chain state of a chain of the legacy block format,
+// which is stored at the two legacy keys, is migrated to a single entry
+func TestMigrateLegacyState(t *testing.T) {
+	legacy := writeLegacyChain(t)
+	chain := openTestChain(t, DefaultOptions())
+
+	data, err := chain.db.GetEntry(ChainStateKey)
+	if err != nil {
+		t.Fatalf("expected the migrated chain state entry to exist, got %v", err)
+	}
+
+	object, err := common.GobDecode(data, new(ChainState))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The work of the migrated chain is the work of the targets of its Blocks
+	stored := *object.(*ChainState)
+	if stored.Height != int64(len(legacy)) || !equalStates(stored, chain.State()) {
+		t.Fatalf("expected the stored chain state %+v to match the migrated chain state %+v", stored, chain.State())
+	}
+
+	work := BlockWork(TargetToCompact(legacy[0].BlockHeader.Target))
+	if want := work.Mul(work, big.NewInt(int64(len(legacy)))); stored.Work.Cmp(want) != 0 {
+		t.Fatalf("expected the migrated chain work to be %v, got %v", want, stored.Work)
+	}
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/anee769/essensio/common"
+)
+
+// countBlock updates the running chain statistics with a Block appended to the chain
+func (chain *ChainManager) countBlock(block *Block) {
+	chain.TxnCount += int64(block.TxnCount())
+	chain.Work.Add(chain.Work, BlockWork(block.Bits))
+
+	for _, txn := range block.BlockTxns {
+		if !txn.IsCoinbase() {
+			continue
+		}
+
+		for _, output := range txn.Outputs {
+			chain.Supply += output.Value
+		}
+	}
+}
+
+// uncountBlock updates the running chain statistics with a Block removed from the chain head
+func (chain *ChainManager) uncountBlock(block *Block) {
+	chain.TxnCount -= int64(block.TxnCount())
+	chain.Work.Sub(chain.Work, BlockWork(block.Bits))
+
+	for _, txn := range block.BlockTxns {
+		if !txn.IsCoinbase() {
+			continue
+		}
+
+		for _, output := range txn.Outputs {
+			chain.Supply -= output.Value
+		}
+	}
+}
+
+// recountStats regenerates the chain statistics and work by scanning every Block on the chain
+func (chain *ChainManager) recountStats() error {
+	chain.TxnCount, chain.Supply, chain.Work = 0, 0, new(big.Int)
+
+	iter := chain.NewIterator()
+	for !iter.Done() {
+		block, err := iter.Next()
+		if err != nil {
+			return err
+		}
+
+		chain.countBlock(block)
+	}
+
+	return nil
+}
+
+// loadStats loads the chain statistics from the DB at the keys specified by ChainTxnCountKey
+// and ChainSupplyKey. The statistics are recounted if they do not exist in the DB.
+func (chain *ChainManager) loadStats() error {
+	exists, err := chain.db.HasEntry(ChainTxnCountKey)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if err := chain.recountStats(); err != nil {
+			return err
+		}
+
+		return chain.syncStats()
+	}
+
+	// Get the transaction count and deserialize it
+	data, err := chain.db.GetEntry(ChainTxnCountKey)
+	if err != nil {
+		return err
+	}
+
+	count, err := common.GobDecode(data, new(int64))
+	if err != nil {
+		return fmt.Errorf("error deserializing chain transaction count: %w", err)
+	}
+
+	// Get the supply and deserialize it
+	if data, err = chain.db.GetEntry(ChainSupplyKey); err != nil {
+		return err
+	}
+
+	supply, err := common.GobDecode(data, new(int))
+	if err != nil {
+		return fmt.Errorf("error deserializing chain supply: %w", err)
+	}
+
+	chain.TxnCount, chain.Supply = *count.(*int64), *supply.(*int)
+	return nil
+}
+
+// syncStats updates the chain statistics into the DB
+// at the keys specified by ChainTxnCountKey and ChainSupplyKey.
+func (chain *ChainManager) syncStats() error {
+	count, err := common.GobEncode(chain.TxnCount)
+	if err != nil {
+		return fmt.Errorf("error serializing chain transaction count: %w", err)
+	}
+
+	if err := chain.db.SetEntry(ChainTxnCountKey, count); err != nil {
+		return err
+	}
+
+	supply, err := common.GobEncode(chain.Supply)
+	if err != nil {
+		return fmt.Errorf("error serializing chain supply: %w", err)
+	}
+
+	return chain.db.SetEntry(ChainSupplyKey, supply)
+}
@@ -0,0 +1,167 @@
+package core
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestChainOptions represents the parameters of a chain generated by GenerateTestChain
+type TestChainOptions struct {
+	// Options is the configuration of the generated chain
+	Options Options
+
+	// Seed is the seed from which the addresses and values of the chain are generated
+	Seed int64
+	// Blocks is the number of Blocks generated on top of the Genesis Block
+	Blocks int
+	// TxnsPerBlock is the maximum number of non-coinbase Transactions in each generated Block.
+	// Blocks have fewer Transactions if there are not enough funded addresses.
+	TxnsPerBlock int
+	// Addresses is the number of generated addresses. DefaultTestAddresses is used if it is 0.
+	Addresses int
+
+	// NoPoW is whether the chain uses the easiest PoW target, so that Blocks are mined without any effort.
+	// It overrides Options.InitialBits.
+	NoPoW bool
+}
+
+// DefaultTestAddresses is the default number of addresses of a chain generated by GenerateTestChain
+const DefaultTestAddresses = 8
+
+// TestAddress returns the generated address with the given index of a chain generated by GenerateTestChain
+func TestAddress(index int) common.Address {
+	return common.Address(fmt.Sprintf("test-%v", index))
+}
+
+// GenerateTestChain creates a new chain in the database directory and populates it with valid Blocks for tests
+// and demos. Coinbase rewards and Transactions between the generated addresses are chosen from the seed, and each
+// Block is timestamped at the target spacing after its parent, so the same options always generate the same chain.
+// Returns an error if the database directory already contains a chain.
+func GenerateTestChain(opts TestChainOptions) (*ChainManager, error) {
+	if opts.Blocks < 0 || opts.TxnsPerBlock < 0 {
+		return nil, fmt.Errorf("invalid test chain shape: %v blocks of %v transactions", opts.Blocks, opts.TxnsPerBlock)
+	}
+
+	if opts.Addresses <= 0 {
+		opts.Addresses = DefaultTestAddresses
+	}
+
+	if opts.NoPoW {
+		opts.Options.InitialBits = DifficultyToBits(1)
+	}
+
+	chain, err := NewChainManager(opts.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	if chain.Height != 1 {
+		chain.Stop()
+		return nil, fmt.Errorf("database already contains a chain of height %v", chain.Height)
+	}
+
+	// The miner address holds the genesis reward, alongside the generated addresses
+	addresses := []common.Address{common.MinerAddress()}
+	for idx := 0; idx < opts.Addresses; idx++ {
+		addresses = append(addresses, TestAddress(idx))
+	}
+
+	random := rand.New(rand.NewSource(opts.Seed))
+
+	for height := 1; height <= opts.Blocks; height++ {
+		if err := chain.generateTestBlock(random, addresses, opts.TxnsPerBlock); err != nil {
+			chain.Stop()
+			return nil, fmt.Errorf("test block at height %v: %w", height, err)
+		}
+	}
+
+	return chain, nil
+}
+
+// generateTestBlock generates and appends a Block with a coinbase paid to a random address
+// and up to the given number of Transactions between random addresses
+func (chain *ChainManager) generateTestBlock(random *rand.Rand, addresses []common.Address, count int) error {
+	coinbase := CoinbaseTxn(addresses[random.Intn(len(addresses))], "", chain.Height)
+	txns := Transactions{coinbase}
+
+	// Outputs spent by Transactions of the Block that are not available to later Transactions
+	spent := make(map[Outpoint]bool)
+
+	for len(txns)-1 < count {
+		txn, err := chain.generateTestTransaction(random, addresses, spent)
+		if err != nil {
+			return err
+		}
+
+		// No more addresses are funded
+		if txn == nil {
+			break
+		}
+
+		txns = append(txns, txn)
+	}
+
+	block, err := chain.SimulateBlock(txns)
+	if err != nil {
+		return err
+	}
+
+	// Timestamp the Block at the target spacing after its parent, and mine it again
+	parent, err := chain.GetHeader(chain.Head)
+	if err != nil {
+		return err
+	}
+
+	block.Timestamp = parent.Timestamp + DefaultTargetSpacing
+	block.BlockHash = block.BlockHeader.Mint()
+
+	return chain.AcceptBlock(block)
+}
+
+// generateTestTransaction generates a signed Transaction of a random value from a random funded address to
+// another random address, that does not spend any of the given outputs. Returns nil if no address is funded.
+func (chain *ChainManager) generateTestTransaction(random *rand.Rand, addresses []common.Address, spent map[Outpoint]bool) (*Transaction, error) {
+	start := random.Intn(len(addresses))
+
+	for offset := range addresses {
+		from := addresses[(start+offset)%len(addresses)]
+
+		// Collect the mature outputs of the address that are not yet spent by the Block
+		var inputs []TxInput
+		funds := 0
+
+		if err := chain.forEachUTXOEntry(from, func(outpoint Outpoint, entry *UTXOEntry) bool {
+			if !spent[outpoint] && entry.IsMature(chain.Height, chain.opts.CoinbaseMaturity) {
+				inputs = append(inputs, TxInput{outpoint.ID, outpoint.Index, common.NullAddress()})
+				funds += entry.Output.Value
+			}
+
+			return true
+		}); err != nil {
+			return nil, err
+		}
+
+		if funds < 2 {
+			continue
+		}
+
+		// Send a random value and return the change to the sender
+		to := addresses[random.Intn(len(addresses))]
+		value := 1 + random.Intn(funds-1)
+
+		txn := &Transaction{Inputs: inputs, Outputs: []TxOutput{{Value: value, PubKey: to}, {Value: funds - value, PubKey: from}}}
+		if err := SignTransaction(txn, NewWallet(from)); err != nil {
+			return nil, err
+		}
+
+		for _, input := range inputs {
+			spent[Outpoint{input.ID, input.Out}] = true
+		}
+
+		return txn, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"os"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/db"
+)
+
+// resetDatabase removes the database directory and the write-ahead log of the test binary
+func resetDatabase(t testing.TB) {
+	t.Helper()
+
+	for _, path := range []string{db.Dir(), db.WALFile()} {
+		if err := os.RemoveAll(path); err != nil {
+			t.Fatalf("failed to remove %v: %v", path, err)
+		}
+	}
+}
+
+// newTestChain generates a test chain in an empty database directory.
+// The chain is stopped and its database is removed when the test ends.
+func newTestChain(t testing.TB, opts TestChainOptions) *ChainManager {
+	t.Helper()
+
+	chain := generateTestChain(t, opts)
+	t.Cleanup(chain.Stop)
+
+	return chain
+}
+
+// generateTestChain generates a test chain in an empty database directory, which is removed when the test ends.
+// The chain is not stopped, so that tests can stop and reopen it with openTestChain.
+func generateTestChain(t testing.TB, opts TestChainOptions) *ChainManager {
+	t.Helper()
+	resetDatabase(t)
+	t.Cleanup(func() { resetDatabase(t) })
+
+	chain, err := GenerateTestChain(opts)
+	if err != nil {
+		t.Fatalf("failed to generate test chain: %v", err)
+	}
+
+	return chain
+}
+
+// newChain opens a new chain with the given Options in an empty database directory.
+// The chain is stopped and its database is removed when the test ends.
+func newChain(t *testing.T, opts Options) *ChainManager {
+	t.Helper()
+	resetDatabase(t)
+	t.Cleanup(func() { resetDatabase(t) })
+
+	return openTestChain(t, opts)
+}
+
+// openTestChain opens the chain in the database directory with the given Options.
+// The chain is stopped when the test ends.
+func openTestChain(t *testing.T, opts Options) *ChainManager {
+	t.Helper()
+
+	chain, err := NewChainManager(opts)
+	if err != nil {
+		t.Fatalf("failed to open chain: %v", err)
+	}
+
+	t.Cleanup(chain.Stop)
+	return chain
+}
+
+// testChainOptions returns the TestChainOptions of a chain with the default Options, mined without PoW
+func testChainOptions(blocks int) TestChainOptions {
+	return TestChainOptions{Options: DefaultOptions(), Seed: 1, Blocks: blocks, TxnsPerBlock: 2, NoPoW: true}
+}
+
+// genesisOutpoint returns the Outpoint of the genesis coinbase output, which is owned by the miner address
+func genesisOutpoint(t *testing.T, chain *ChainManager) Outpoint {
+	t.Helper()
+
+	genesis, err := chain.GetBlockByHeight(0)
+	if err != nil {
+		t.Fatalf("failed to get genesis block: %v", err)
+	}
+
+	return Outpoint{genesis.BlockTxns[0].ID, 0}
+}
+
+// signedTransaction returns a Transaction that spends the given outputs of an Address to the given outputs,
+// signed with the Wallet of the Address
+func signedTransaction(t *testing.T, from common.Address, spends []Outpoint, outputs ...TxOutput) *Transaction {
+	t.Helper()
+
+	txn := &Transaction{Outputs: outputs}
+	for _, outpoint := range spends {
+		txn.Inputs = append(txn.Inputs, TxInput{outpoint.ID, outpoint.Index, common.NullAddress()})
+	}
+
+	if err := SignTransaction(txn, NewWallet(from)); err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+
+	return txn
+}
+
+// TestGenerateTestChain checks that GenerateTestChain builds a valid chain of the requested height,
+// which is identical for the same options
+func TestGenerateTestChain(t *testing.T) {
+	heads := make([]common.Hash, 0, 2)
+
+	for run := 0; run < 2; run++ {
+		resetDatabase(t)
+
+		chain, err := GenerateTestChain(testChainOptions(5))
+		if err != nil {
+			t.Fatalf("failed to generate test chain: %v", err)
+		}
+
+		if chain.Height != 6 {
+			t.Errorf("expected chain height 6, got %v", chain.Height)
+		}
+
+		if err := chain.VerifyChain(); err != nil {
+			t.Errorf("generated chain is invalid: %v", err)
+		}
+
+		heads = append(heads, chain.Head)
+		chain.Stop()
+	}
+
+	resetDatabase(t)
+
+	if !heads[0].Equal(heads[1]) {
+		t.Fatalf("expected the same chain head for the same options, got '%v' and '%v'", heads[0], heads[1])
+	}
+}
+
+// TestGenerateTestChainExisting checks that GenerateTestChain refuses to populate an existing chain
+func TestGenerateTestChainExisting(t *testing.T) {
+	generateTestChain(t, testChainOptions(1)).Stop()
+
+	if existing, err := GenerateTestChain(testChainOptions(1)); err == nil {
+		existing.Stop()
+		t.Fatalf("expected an error for a database that already contains a chain")
+	}
+
+	if chain := openTestChain(t, DefaultOptions()); chain.Height != 2 {
+		t.Fatalf("expected the existing chain of height 2, got %v", chain.Height)
+	}
+}
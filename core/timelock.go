@@ -0,0 +1,71 @@
+package core
+
+import "fmt"
+
+// OutputLock represents the condition under which a TxOutput can be spent. An output with a lock
+// can only be spent by a Block at or above the lock height, once the median time past of the chain
+// has reached the lock time. Either condition is disabled if it is 0.
+type OutputLock struct {
+	// Height is the minimum height of the Block that spends the output
+	Height int64
+	// Time is the minimum median time past of the chain when the output is spent
+	Time int64
+}
+
+// IsZero returns whether the OutputLock places no condition on spending the output
+func (lock OutputLock) IsZero() bool {
+	return lock == OutputLock{}
+}
+
+// Unlocked returns whether an output with the lock can be spent in the Block
+// at the given height, on a chain with the given median time past
+func (lock OutputLock) Unlocked(height, medianTime int64) bool {
+	return height >= lock.Height && medianTime >= lock.Time
+}
+
+// String implements the Stringer interface for OutputLock
+func (lock OutputLock) String() string {
+	return fmt.Sprintf("height %v, time %v", lock.Height, lock.Time)
+}
+
+// hasLockedOutputs returns whether any output of the Transaction is locked
+func (txn *Transaction) hasLockedOutputs() bool {
+	for _, output := range txn.Outputs {
+		if !output.Lock.IsZero() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verifyLocks checks that the locks of the outputs of a Transaction are valid.
+// Coinbase outputs cannot be locked, and lock heights and times must not be negative.
+func verifyLocks(txn *Transaction) error {
+	for idx, output := range txn.Outputs {
+		if output.Lock.IsZero() {
+			continue
+		}
+
+		if txn.IsCoinbase() {
+			return fmt.Errorf("output %v: coinbase output cannot be locked", idx)
+		}
+
+		if output.Lock.Height < 0 || output.Lock.Time < 0 {
+			return fmt.Errorf("output %v: invalid lock: %v", idx, output.Lock)
+		}
+	}
+
+	return nil
+}
+
+// lockClock returns the height of the next Block of the chain and the median time past of the chain,
+// against which the locks of outputs spent in the next Block are checked
+func (chain *ChainManager) lockClock() (int64, int64, error) {
+	median, err := chain.MedianTimePast(MedianTimeBlocks)
+	if err != nil {
+		return 0, 0, fmt.Errorf("median time past computation failed: %w", err)
+	}
+
+	return chain.Height, median, nil
+}
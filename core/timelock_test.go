@@ -0,0 +1,85 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestHeightLockedOutput checks that an output locked until a height cannot be spent or selected before it,
+// and can be spent by the Block at its unlock height
+func TestHeightLockedOutput(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+
+	// The locked output is created at height 1 and can be spent from height 3
+	locked := signedTransaction(t, common.MinerAddress(), []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: 90, PubKey: "alice", Lock: OutputLock{Height: 3}})
+	if err := chain.MineBlock(Transactions{locked}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	spend := signedTransaction(t, "alice", []Outpoint{{locked.ID, 0}}, TxOutput{Value: 90, PubKey: "bob"})
+	if err := chain.VerifyTransaction(spend); err == nil || !strings.Contains(err.Error(), "output is locked until") {
+		t.Fatalf("expected the output to be locked at height %v, got %v", chain.Height, err)
+	}
+
+	if found, _, err := chain.FindSpendableOutputs("alice", 90, 0); err != nil || found != 0 {
+		t.Fatalf("expected the locked output not to be selected at height %v, got %v and %v", chain.Height, found, err)
+	}
+
+	if err := chain.MineBlock(nil, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	if err := chain.VerifyTransaction(spend); err != nil {
+		t.Fatalf("expected the output to be unlocked at height %v, got %v", chain.Height, err)
+	}
+
+	if found, _, err := chain.FindSpendableOutputs("alice", 90, 0); err != nil || found != 90 {
+		t.Fatalf("expected the unlocked output to be selected, got %v and %v", found, err)
+	}
+
+	if err := chain.MineBlock(Transactions{spend}, ""); err != nil {
+		t.Fatalf("failed to mine the spend of the unlocked output: %v", err)
+	}
+}
+
+// TestTimeLockedOutput checks that an output locked until a time can only be spent once the median time past
+// of the chain has reached it
+func TestTimeLockedOutput(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	future := time.Now().Add(time.Hour).Unix()
+
+	// The output of alice is locked until the median time past reaches the timestamp of the Genesis Block,
+	// while the output of bob is locked until the future
+	locked := signedTransaction(t, common.MinerAddress(), []Outpoint{genesisOutpoint(t, chain)},
+		TxOutput{Value: 50, PubKey: "alice", Lock: OutputLock{Time: DefaultGenesisConfig().Timestamp}},
+		TxOutput{Value: 40, PubKey: "bob", Lock: OutputLock{Time: future}})
+	if err := chain.MineBlock(Transactions{locked}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	if err := chain.VerifyTransaction(signedTransaction(t, "alice", []Outpoint{{locked.ID, 0}}, TxOutput{Value: 50, PubKey: "carol"})); err != nil {
+		t.Fatalf("expected the output of alice to be unlocked, got %v", err)
+	}
+
+	if err := chain.VerifyTransaction(signedTransaction(t, "bob", []Outpoint{{locked.ID, 1}}, TxOutput{Value: 40, PubKey: "carol"})); err == nil || !strings.Contains(err.Error(), "output is locked until") {
+		t.Fatalf("expected the output of bob to be locked until %v, got %v", future, err)
+	}
+}
+
+// TestInvalidOutputLocks checks that negative locks and locked coinbase outputs are rejected
+func TestInvalidOutputLocks(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+
+	negative := signedTransaction(t, common.MinerAddress(), []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: 90, PubKey: "alice", Lock: OutputLock{Height: -1}})
+	if err := chain.VerifyTransaction(negative); err == nil || !strings.Contains(err.Error(), "invalid lock") {
+		t.Fatalf("expected a negative lock to be rejected, got %v", err)
+	}
+
+	coinbase := coinbaseOf(chain, TxOutput{Value: BlockReward, PubKey: common.MinerAddress(), Lock: OutputLock{Height: 10}})
+	if err := chain.AddBlock(Transactions{coinbase}); err == nil || !strings.Contains(err.Error(), "coinbase output cannot be locked") {
+		t.Fatalf("expected a locked coinbase output to be rejected, got %v", err)
+	}
+}
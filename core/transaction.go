@@ -1,10 +1,10 @@
 package core
 
 import (
-	"bytes"
 	"crypto/sha256"
 	"fmt"
 	"log"
+	"sort"
 
 	"github.com/anee769/essensio/common"
 )
@@ -16,11 +16,18 @@ type Transaction struct {
 	ID      common.Hash
 	Inputs  []TxInput
 	Outputs []TxOutput
+
+	// Height of the Block containing a coinbase Transaction and zero for all other Transactions.
+	// Ensures that coinbase Transactions with the same data have unique IDs.
+	Height int64
 }
 
 type TxOutput struct {
 	Value  int
 	PubKey common.Address
+
+	// Lock is the condition that must be met before the output can be spent. The output is not locked if it is zero.
+	Lock OutputLock
 }
 
 type TxInput struct {
@@ -29,68 +36,368 @@ type TxInput struct {
 	Sig common.Address
 }
 
-func CoinbaseTxn(to common.Address, data string) *Transaction {
+// BlockReward is the value paid by the coinbase Transaction of each Block
+const BlockReward = 100
+
+// MaxCoinbaseDataLength is the maximum length of the data in a coinbase Transaction
+const MaxCoinbaseDataLength = 100
+
+// CoinbaseTxn generates a coinbase Transaction that pays the block reward to an Address
+// for the Block at the given height. The given data is tagged in the input of the Transaction.
+func CoinbaseTxn(to common.Address, data string, height int64) *Transaction {
 	if data == "" {
 		data = fmt.Sprintf("Coins to %s", to)
 	}
 
 	txnIn := TxInput{common.NullHash(), -1, common.Address(data)}
-	txnOut := TxOutput{100, to}
+	txnOut := TxOutput{Value: BlockReward, PubKey: to}
 
-	tx := Transaction{common.NullHash(), []TxInput{txnIn}, []TxOutput{txnOut}}
+	tx := Transaction{common.NullHash(), []TxInput{txnIn}, []TxOutput{txnOut}, height}
 	tx.SetID()
 
 	return &tx
 }
 
-func NewTransaction(from, to common.Address, amount int, chain *ChainManager) *Transaction {
-	var inputs []TxInput
-	var outputs []TxOutput
+// CoinbaseShare represents the share of the block reward paid to an Address by a split coinbase Transaction
+type CoinbaseShare struct {
+	Address common.Address
+	Share   int
+}
+
+// verifyShares checks that a set of CoinbaseShares is non-empty and that every share is positive
+func verifyShares(shares []CoinbaseShare) error {
+	if len(shares) == 0 {
+		return fmt.Errorf("no coinbase shares")
+	}
+
+	for idx, share := range shares {
+		if share.Share <= 0 {
+			return fmt.Errorf("coinbase share %v: share %v is not positive", idx, share.Share)
+		}
+	}
+
+	return nil
+}
+
+// SplitCoinbaseTxn generates a coinbase Transaction that splits the block reward and the given fees across
+// several Addresses in proportion to their shares, with an output for each Address. The remainder of the
+// split is paid to the first Address, so the outputs always total the block reward and the fees.
+func SplitCoinbaseTxn(shares []CoinbaseShare, data string, height int64, fees int) (*Transaction, error) {
+	if err := verifyShares(shares); err != nil {
+		return nil, err
+	}
+
+	if data == "" {
+		data = fmt.Sprintf("Coins to %s", shares[0].Address)
+	}
+
+	total := 0
+	for _, share := range shares {
+		total += share.Share
+	}
+
+	if fees < 0 {
+		return nil, fmt.Errorf("negative coinbase fees %v", fees)
+	}
+
+	// Split the reward and fees proportionally and pay the remainder to the first Address
+	reward := BlockReward + fees
+
+	outputs := make([]TxOutput, len(shares))
+	remainder := reward
+
+	for idx, share := range shares {
+		value := reward * share.Share / total
+		outputs[idx] = TxOutput{Value: value, PubKey: share.Address}
+		remainder -= value
+	}
+
+	outputs[0].Value += remainder
+
+	txnIn := TxInput{common.NullHash(), -1, common.Address(data)}
+
+	tx := Transaction{common.NullHash(), []TxInput{txnIn}, outputs, height}
+	tx.SetID()
 
-	acc, validOutputs, err := chain.FindSpendableOutputs(from, amount)
+	return &tx, nil
+}
+
+// BuildOptions represents the optional parameters for building a Transaction
+type BuildOptions struct {
+	// Change is the Address that receives the change of the Transaction.
+	// The change is returned to the sender Address if it is null.
+	Change common.Address
+
+	// MinConfirmations is the minimum number of confirmations of the outputs selected for spending.
+	// An output in the chain head has 1 confirmation. All unspent outputs are eligible if it is 0.
+	MinConfirmations int64
+
+	// Consolidate is the maximum number of additional outputs of the sources spent to be swept into the change,
+	// which reduces the fragmentation of their unspent outputs. The smallest outputs are swept first.
+	// No additional outputs are spent if it is 0.
+	Consolidate int
+}
+
+// NewTransaction builds and signs a Transaction that sends an amount from one Address to another.
+// Panics if the Transaction cannot be built for the given chain.
+func NewTransaction(from, to common.Address, amount int, chain *ChainManager, opts BuildOptions) *Transaction {
+	txn, err := BuildUnsignedTransaction(from, []TxOutput{{Value: amount, PubKey: to}}, chain, opts)
 	if err != nil {
 		log.Panic(err)
 	}
 
+	if err := SignTransaction(txn, NewWallet(from)); err != nil {
+		log.Panic(err)
+	}
+
+	return txn
+}
+
+// BuildUnsignedTransaction generates a Transaction that spends outputs owned by the from
+// Address to pay the given outputs, with any change sent to the change Address of the BuildOptions.
+// The inputs of the Transaction are populated but left unsigned, to be signed with SignTransaction.
+// Returns an error if the from Address does not have enough funds.
+func BuildUnsignedTransaction(from common.Address, outputs []TxOutput, chain *ChainManager, opts BuildOptions) (*Transaction, error) {
+	return BuildUnsignedMultiTransaction([]common.Address{from}, outputs, chain, opts)
+}
+
+// BuildUnsignedMultiTransaction generates a Transaction that spends outputs owned by any of the source
+// Addresses to pay the given outputs. Outputs are selected from each source in order until the outputs
+// are funded, so later sources are only spent from if the earlier ones do not have enough funds.
+// Any change is sent to the change Address of the BuildOptions, or the first source if it is null,
+// along with any additional outputs swept with BuildOptions.Consolidate.
+// The inputs are left unsigned, to be signed with SignTransactionInputs by the Wallet of each source.
+// Returns an error if a source is repeated or the sources together do not have enough funds.
+func BuildUnsignedMultiTransaction(sources []common.Address, outputs []TxOutput, chain *ChainManager, opts BuildOptions) (*Transaction, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no source addresses for transaction")
+	}
+
+	if len(outputs) == 0 {
+		return nil, fmt.Errorf("no outputs for transaction")
+	}
+
+	// Accumulate the total value of the outputs
+	amount := 0
+	for _, output := range outputs {
+		if output.Value <= 0 {
+			return nil, fmt.Errorf("output value must be positive: %v", output.Value)
+		}
+
+		amount += output.Value
+	}
+
+	// Select spendable outputs from each source until the amount is met
+	acc := 0
+	var inputs []TxInput
+	seen := make(map[common.Address]struct{}, len(sources))
+
+	for _, source := range sources {
+		if _, exists := seen[source]; exists {
+			return nil, fmt.Errorf("source address %v is repeated", source)
+		}
+
+		seen[source] = struct{}{}
+		if acc >= amount {
+			continue
+		}
+
+		found, validOutputs, err := chain.FindSpendableOutputs(source, amount-acc, opts.MinConfirmations)
+		if err != nil {
+			return nil, fmt.Errorf("spendable output search failed: %w", err)
+		}
+
+		acc += found
+
+		// Create an unsigned input for each selected output, ordered by the Transaction
+		// of the output so that the same selection always builds the same Transaction
+		txids := make([]common.Hash, 0, len(validOutputs))
+		for txid := range validOutputs {
+			txids = append(txids, txid)
+		}
+
+		sort.Slice(txids, func(i, j int) bool { return txids[i].Cmp(txids[j]) < 0 })
+
+		for _, txid := range txids {
+			for _, out := range validOutputs[txid] {
+				inputs = append(inputs, TxInput{txid, out, common.NullAddress()})
+			}
+		}
+	}
+
 	if acc < amount {
-		log.Panic("Error: not enough funds")
+		return nil, fmt.Errorf("not enough funds: have %v, need %v", acc, amount)
 	}
 
-	for txid, outs := range validOutputs {
-		for _, out := range outs {
-			input := TxInput{txid, out, from}
-			inputs = append(inputs, input)
+	// Sweep additional small outputs of the sources into the change
+	if opts.Consolidate > 0 {
+		swept, outpoints, err := consolidateInputs(sources, inputs, chain, opts)
+		if err != nil {
+			return nil, err
 		}
+
+		for _, outpoint := range outpoints {
+			inputs = append(inputs, TxInput{outpoint.ID, outpoint.Index, common.NullAddress()})
+		}
+
+		acc += swept
 	}
 
-	outputs = append(outputs, TxOutput{amount, to})
+	// Return the change to the first source if no change address is set
+	change := opts.Change
+	if change == common.NullAddress() {
+		change = sources[0]
+	}
 
+	outputs = append([]TxOutput{}, outputs...)
 	if acc > amount {
-		outputs = append(outputs, TxOutput{acc - amount, from})
+		outputs = append(outputs, TxOutput{Value: acc - amount, PubKey: change})
 	}
 
-	tx := Transaction{common.NullHash(), inputs, outputs}
-	tx.SetID()
+	tx := Transaction{ID: common.NullHash(), Inputs: inputs, Outputs: outputs}
+	if err := tx.SetID(); err != nil {
+		return nil, err
+	}
 
-	return &tx
+	return &tx, nil
 }
 
-func (txn *Transaction) SetID() error {
-	txnHash, err := txn.Serialize()
-	if err != nil {
-		return err
+// consolidateInputs selects up to the Consolidate limit of the BuildOptions of additional spendable outputs
+// of the sources, from the first source onwards, that are not already spent by the given inputs.
+// Returns the accumulated value and the selected outputs.
+func consolidateInputs(sources []common.Address, inputs []TxInput, chain *ChainManager, opts BuildOptions) (int, []Outpoint, error) {
+	exclude := make(map[Outpoint]struct{}, len(inputs))
+	for _, input := range inputs {
+		exclude[Outpoint{input.ID, input.Out}] = struct{}{}
+	}
+
+	swept := 0
+	var selected []Outpoint
+
+	for _, source := range sources {
+		if len(selected) >= opts.Consolidate {
+			break
+		}
+
+		value, outpoints, err := chain.FindConsolidationOutputs(source, opts.Consolidate-len(selected), opts.MinConfirmations, exclude)
+		if err != nil {
+			return -1, nil, fmt.Errorf("consolidation output search failed: %w", err)
+		}
+
+		swept += value
+		selected = append(selected, outpoints...)
+	}
+
+	return swept, selected, nil
+}
+
+// SignTransaction signs all the unsigned inputs of a Transaction with the given Wallet, with SigHashAll.
+// The ID of the Transaction is regenerated to include the signatures.
+func SignTransaction(txn *Transaction, wallet *Wallet) error {
+	return SignTransactionHash(txn, wallet, SigHashAll)
+}
+
+// SignTransactionHash signs all the unsigned inputs of a Transaction with the given Wallet and SigHashType,
+// which selects the parts of the Transaction that each signature commits to. The ID of the Transaction
+// is regenerated to include the signatures. Returns an error without signing any input if an input
+// cannot be signed with the SigHashType.
+func SignTransactionHash(txn *Transaction, wallet *Wallet, flag SigHashType) error {
+	signers := make(map[int]*Wallet)
+	for idx, input := range txn.Inputs {
+		if input.Sig == common.NullAddress() {
+			signers[idx] = wallet
+		}
+	}
+
+	return signInputs(txn, signers, flag)
+}
+
+// signInputs signs the inputs of a Transaction at the indexes of the signers with their Wallets and the
+// SigHashType, and regenerates the ID of the Transaction. The signatures are computed before any input is
+// signed, so no input is signed if any signature fails. Returns an error if there are no signers.
+func signInputs(txn *Transaction, signers map[int]*Wallet, flag SigHashType) error {
+	if len(signers) == 0 {
+		return fmt.Errorf("no unsigned inputs in transaction")
 	}
-	hash := sha256.Sum256(txnHash)
-	txn.ID = hash
+
+	// Signatures never commit to other signatures, so they can all be computed before any is set
+	sigs := make(map[int]common.Address, len(signers))
+	for idx, wallet := range signers {
+		sig, err := wallet.Sign(txn, idx, flag)
+		if err != nil {
+			return fmt.Errorf("input %v: %w", idx, err)
+		}
+
+		sigs[idx] = sig
+	}
+
+	for idx, sig := range sigs {
+		txn.Inputs[idx].Sig = sig
+	}
+
+	return txn.SetID()
+}
+
+// SignTransactionInputs signs each unsigned input of a Transaction with the Wallet for the owner of the output
+// it spends, with SigHashAll, so that it signs Transactions that spend from several Addresses, such as those built
+// by BuildUnsignedMultiTransaction. The owner of each output is resolved from the UTXO set of the chain and the ID
+// of the Transaction is regenerated to include the signatures. Returns an error without signing any input if the
+// owner of an input has no Wallet.
+func SignTransactionInputs(txn *Transaction, chain *ChainManager, wallets ...*Wallet) error {
+	owners := make(map[common.Address]*Wallet, len(wallets))
+	for _, wallet := range wallets {
+		owners[wallet.Address] = wallet
+	}
+
+	// Resolve the Wallet of every unsigned input before signing any of them
+	signers := make(map[int]*Wallet)
+	for idx, input := range txn.Inputs {
+		if input.Sig != common.NullAddress() {
+			continue
+		}
+
+		entry, err := chain.GetUTXOEntry(Outpoint{input.ID, input.Out})
+		if err != nil {
+			return fmt.Errorf("input %v: %w", idx, err)
+		}
+
+		wallet, ok := owners[entry.Output.PubKey]
+		if !ok {
+			return fmt.Errorf("input %v: no wallet for output owner %v", idx, entry.Output.PubKey)
+		}
+
+		signers[idx] = wallet
+	}
+
+	return signInputs(txn, signers, SigHashAll)
+}
+
+// SetID generates the ID of the Transaction from the
+// SHA-256 hash of its contents, excluding any existing ID.
+func (txn *Transaction) SetID() error {
+	txn.ID = sha256.Sum256(txn.hashData())
 	return nil
 }
 
+// IsCoinbase returns whether the Transaction is a coinbase Transaction
 func (tx *Transaction) IsCoinbase() bool {
-	return len(tx.Inputs) == 1 && len(tx.Inputs[0].ID) == 0 && tx.Inputs[0].Out == -1
+	return len(tx.Inputs) == 1 && tx.Inputs[0].ID.IsZero() && tx.Inputs[0].Out == -1
+}
+
+// CoinbaseData returns the data tagged in a coinbase Transaction.
+// Returns an empty string if the Transaction is not a coinbase.
+func (tx *Transaction) CoinbaseData() string {
+	if !tx.IsCoinbase() {
+		return ""
+	}
+
+	return string(tx.Inputs[0].Sig)
 }
 
+// CanUnlock returns whether the input is signed by the Address. Only the signer of the signature is checked,
+// the commitment of the signature to its Transaction is checked by VerifyTransaction.
 func (in *TxInput) CanUnlock(address common.Address) bool {
-	return in.Sig == address
+	return parseSignature(in.Sig).Signer == address
 }
 
 func (out *TxOutput) CanBeUnlocked(address common.Address) bool {
@@ -102,10 +409,11 @@ func (txn *Transaction) Serialize() ([]byte, error) {
 }
 
 // Deserialize implements the common.Serializable interface for Transaction.
-// Converts the given data into Transaction and sets it the method's receiver using common.GobDecode.
+// Converts the given data into Transaction and sets it the method's receiver using common.GobDecodeLimit.
+// Returns an error without decoding if the data is longer than MaxTxDataSize.
 func (txn *Transaction) Deserialize(data []byte) error {
 	// Decode the data into a *Transaction
-	object, err := common.GobDecode(data, new(Transaction))
+	object, err := common.GobDecodeLimit(data, new(Transaction), MaxTxDataSize)
 	if err != nil {
 		return err
 	}
@@ -116,33 +424,42 @@ func (txn *Transaction) Deserialize(data []byte) error {
 	return nil
 }
 
-// Hash returns the SHA-256	hash of the Transaction's serialized representation.
+// Hash returns the SHA-256	hash of the Transaction's canonical representation.
 func (txn *Transaction) Hash() common.Hash {
-	data, err := txn.Serialize()
-	if err != nil {
-		return common.NullHash()
-	}
+	return common.Hash256(txn.hashData())
+}
 
-	return common.Hash256(data)
+// SigHashID returns the hash of the canonical representation of the Transaction with the signatures of its inputs
+// blanked. Unlike the ID, it is unchanged by signing, so it identifies a Transaction from its unsigned template onwards.
+// The data of a coinbase Transaction is not a signature and is kept.
+func (txn *Transaction) SigHashID() common.Hash {
+	return common.Hash256(txn.canonicalData(txn.IsCoinbase()))
 }
 
+// EmptySummary is the summary of a Block without Transactions, which is the hash of no data:
+// 0x5df6e0e2761359d30a8275058e299fcc0381534545f55cf43e41983f5d4c9456
+var EmptySummary = common.Hash256(nil)
+
 // GenerateSummary generates a summary hash for a given set of Transactions.
-// Currently, concatenates the hash of all given transactions and hashes that data to obtain the summary.
-// This is a valid method of summary generation but does not for allow tamper detection or inclusivity checks
+// The summary is the root of the Merkle tree over the hashes of the Transactions,
+// which allows the inclusion of a Transaction to be proven with a Merkle proof.
+// Nil and empty sets of Transactions both have the EmptySummary.
 func GenerateSummary(txns Transactions) common.Hash {
-	// Iterate over each transaction, obtain
-	// its hash and append it into the buffer
-	var buffer bytes.Buffer
+	if len(txns) == 0 {
+		return EmptySummary
+	}
+
+	// Iterate over each transaction and obtain its hash
+	leaves := make([]common.Hash, 0, len(txns))
 	for _, txn := range txns {
 		hash := txn.Hash()
-		if hash == common.NullHash() {
+		if hash.IsZero() {
 			return hash
 		}
 
-		buffer.Write(hash.Bytes())
+		leaves = append(leaves, hash)
 	}
 
-	// Generate the hash of the buffer bytes
-	txnsum := common.Hash256(buffer.Bytes())
-	return txnsum
+	// Generate the root of the Merkle tree over the hashes
+	return MerkleRoot(leaves)
 }
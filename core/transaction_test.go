@@ -0,0 +1,444 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// outputValues returns the values of the outputs of a Transaction
+func outputValues(txn *Transaction) []int {
+	values := make([]int, 0, len(txn.Outputs))
+	for _, output := range txn.Outputs {
+		values = append(values, output.Value)
+	}
+
+	return values
+}
+
+// equalValues returns whether two sets of output values are equal
+func equalValues(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for idx := range a {
+		if a[idx] != b[idx] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TestSplitCoinbaseTxn checks that the block reward and fees are split proportionally across the shares,
+// with the remainder of the split paid to the first Address
+func TestSplitCoinbaseTxn(t *testing.T) {
+	tests := []struct {
+		name   string
+		shares []int
+		fees   int
+		values []int
+	}{
+		{"70/30", []int{70, 30}, 0, []int{70, 30}},
+		{"70/30 with fees", []int{70, 30}, 10, []int{77, 33}},
+		{"remainder", []int{1, 1, 1}, 0, []int{34, 33, 33}},
+		{"remainder with fees", []int{1, 1, 1}, 2, []int{34, 34, 34}},
+		{"single", []int{5}, 7, []int{107}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shares := make([]CoinbaseShare, 0, len(test.shares))
+			for idx, share := range test.shares {
+				shares = append(shares, CoinbaseShare{TestAddress(idx), share})
+			}
+
+			txn, err := SplitCoinbaseTxn(shares, "", 1, test.fees)
+			if err != nil {
+				t.Fatalf("failed to split coinbase: %v", err)
+			}
+
+			if !txn.IsCoinbase() {
+				t.Fatalf("expected a coinbase transaction")
+			}
+
+			if values := outputValues(txn); !equalValues(values, test.values) {
+				t.Fatalf("expected output values %v, got %v", test.values, values)
+			}
+
+			for idx, output := range txn.Outputs {
+				if output.PubKey != TestAddress(idx) {
+					t.Fatalf("expected output %v to pay '%v', got '%v'", idx, TestAddress(idx), output.PubKey)
+				}
+			}
+		})
+	}
+}
+
+// TestSplitCoinbaseTxnInvalid checks that invalid shares and fees are rejected
+func TestSplitCoinbaseTxnInvalid(t *testing.T) {
+	if _, err := SplitCoinbaseTxn(nil, "", 1, 0); err == nil {
+		t.Errorf("expected an error for no shares")
+	}
+
+	if _, err := SplitCoinbaseTxn([]CoinbaseShare{{"a", 70}, {"b", 0}}, "", 1, 0); err == nil {
+		t.Errorf("expected an error for a share that is not positive")
+	}
+
+	if _, err := SplitCoinbaseTxn([]CoinbaseShare{{"a", 70}}, "", 1, -1); err == nil {
+		t.Errorf("expected an error for negative fees")
+	}
+}
+
+// TestMineBlockCoinbaseShares checks that a mined Block splits the block reward and fees 70/30
+func TestMineBlockCoinbaseShares(t *testing.T) {
+	opts := testChainOptions(0)
+	opts.Options.CoinbaseShares = []CoinbaseShare{{"pool", 70}, {"operator", 30}}
+
+	chain := newTestChain(t, opts)
+
+	// Spends the genesis reward with a fee of 20
+	spend := signedTransaction(t, common.MinerAddress(), []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: BlockReward - 20, PubKey: "recipient"})
+
+	if err := chain.MineBlock(Transactions{spend}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	tip, err := chain.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	coinbase := tip.BlockTxns[0]
+	if values := outputValues(coinbase); !equalValues(values, []int{84, 36}) {
+		t.Fatalf("expected coinbase output values [84 36], got %v", values)
+	}
+
+	if coinbase.Outputs[0].PubKey != "pool" || coinbase.Outputs[1].PubKey != "operator" {
+		t.Fatalf("expected coinbase outputs to pay 'pool' and 'operator', got %v", coinbase.Outputs)
+	}
+}
+
+// TestBuildUnsignedTransaction checks that a Transaction built without signatures on one side can be
+// serialized, signed by the Wallet of its sender on another side and then verified by the chain
+func TestBuildUnsignedTransaction(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	miner := common.MinerAddress()
+
+	unsigned, err := BuildUnsignedTransaction(miner, []TxOutput{{Value: 30, PubKey: "alice"}}, chain, BuildOptions{})
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+
+	for idx, input := range unsigned.Inputs {
+		if input.Sig != common.NullAddress() {
+			t.Fatalf("expected input %v to be unsigned, got %v", idx, input.Sig)
+		}
+	}
+
+	if err := chain.VerifyTransaction(unsigned); !errors.Is(err, ErrEmptySignature) {
+		t.Fatalf("expected the unsigned transaction to be rejected for an empty signature, got %v", err)
+	}
+
+	// The unsigned Transaction is carried to the signer in its serialized form
+	data, err := unsigned.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txn := new(Transaction)
+	if err := txn.Deserialize(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SignTransaction(txn, NewWallet(miner)); err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+
+	if txn.ID.Equal(unsigned.ID) {
+		t.Fatalf("expected the id of the transaction to change with its signatures")
+	}
+
+	if err := chain.VerifyTransaction(txn); err != nil {
+		t.Fatalf("expected the signed transaction to be valid, got %v", err)
+	}
+
+	// Signing with the Wallet of another Address is rejected
+	forged := new(Transaction)
+	if err := forged.Deserialize(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SignTransaction(forged, NewWallet("mallory")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.VerifyTransaction(forged); !errors.Is(err, ErrSignatureMismatch) {
+		t.Fatalf("expected a transaction signed by another wallet to be rejected, got %v", err)
+	}
+}
+
+// TestBuildTransactionChange checks that the change of a Transaction is sent to the change Address of its BuildOptions,
+// or returned to the sender without one, and that the balances of the chain account for it
+func TestBuildTransactionChange(t *testing.T) {
+	miner := common.MinerAddress()
+
+	tests := []struct {
+		name     string
+		opts     BuildOptions
+		balances map[common.Address]int
+	}{
+		// The miner is also paid the coinbase of the Block of the Transaction
+		{"default change", BuildOptions{}, map[common.Address]int{"alice": 30, miner: 70 + BlockReward}},
+		{"change address", BuildOptions{Change: "change"}, map[common.Address]int{"alice": 30, "change": 70, miner: BlockReward}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chain := newTestChain(t, testChainOptions(0))
+
+			txn := NewTransaction(miner, "alice", 30, chain, test.opts)
+			if err := chain.MineBlock(Transactions{txn}, ""); err != nil {
+				t.Fatalf("failed to mine block: %v", err)
+			}
+
+			balances, err := chain.AllBalances()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(balances) != len(test.balances) {
+				t.Fatalf("expected balances %v, got %v", test.balances, balances)
+			}
+
+			for address, balance := range test.balances {
+				if balances[address] != balance {
+					t.Fatalf("expected balances %v, got %v", test.balances, balances)
+				}
+			}
+		})
+	}
+}
+
+// TestEmptySummary checks that nil and empty sets of Transactions have the same defined summary,
+// and that Blocks assembled from either have the same summary and Transactions
+func TestEmptySummary(t *testing.T) {
+	want, err := common.HexToHash("0x5df6e0e2761359d30a8275058e299fcc0381534545f55cf43e41983f5d4c9456")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !EmptySummary.Equal(want) {
+		t.Fatalf("expected the empty summary to be '%v', got '%v'", want, EmptySummary)
+	}
+
+	if !GenerateSummary(nil).Equal(want) || !GenerateSummary(Transactions{}).Equal(want) {
+		t.Fatalf("expected nil and empty transactions to have the empty summary")
+	}
+
+	bits := DifficultyToBits(1)
+	empty, null := NewBlock(Transactions{}, common.NullHash(), 0, bits), NewBlock(nil, common.NullHash(), 0, bits)
+
+	if empty.BlockTxns != nil || !empty.Summary.Equal(null.Summary) || !empty.VerifySummary() {
+		t.Fatalf("expected a block of empty transactions to be normalized to nil transactions")
+	}
+}
+
+// TestBuildUnsignedMultiTransaction checks that outputs of three Addresses are consolidated into one output
+// by a Transaction built from all three sources and signed by the Wallet of each owner
+func TestBuildUnsignedMultiTransaction(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	sources := []common.Address{"alice", "bob", "carol"}
+
+	funding := signedTransaction(t, common.MinerAddress(), []Outpoint{genesisOutpoint(t, chain)},
+		TxOutput{Value: 30, PubKey: "alice"}, TxOutput{Value: 30, PubKey: "bob"}, TxOutput{Value: 30, PubKey: "carol"})
+	if err := chain.MineBlock(Transactions{funding}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	txn, err := BuildUnsignedMultiTransaction(sources, []TxOutput{{Value: 90, PubKey: "dave"}}, chain, BuildOptions{})
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+
+	if len(txn.Inputs) != 3 || len(txn.Outputs) != 1 {
+		t.Fatalf("expected 3 inputs consolidated into 1 output, got %v and %v", len(txn.Inputs), len(txn.Outputs))
+	}
+
+	if err := SignTransactionInputs(txn, chain, NewWallet("alice"), NewWallet("bob")); err == nil {
+		t.Fatalf("expected signing without the wallet of carol to fail")
+	}
+
+	wallets := make([]*Wallet, 0, len(sources))
+	for _, source := range sources {
+		wallets = append(wallets, NewWallet(source))
+	}
+
+	if err := SignTransactionInputs(txn, chain, wallets...); err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+
+	if err := chain.MineBlock(Transactions{txn}, ""); err != nil {
+		t.Fatalf("failed to mine consolidation: %v", err)
+	}
+
+	balances, err := chain.AllBalances()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if balances["dave"] != 90 || balances["alice"] != 0 || balances["bob"] != 0 || balances["carol"] != 0 {
+		t.Fatalf("expected the outputs of the sources to be consolidated to dave, got %v", balances)
+	}
+
+	if _, err := BuildUnsignedMultiTransaction([]common.Address{"dave", "dave"}, []TxOutput{{Value: 10, PubKey: "erin"}}, chain, BuildOptions{}); err == nil {
+		t.Fatalf("expected a repeated source to be rejected")
+	}
+
+	if _, err := BuildUnsignedMultiTransaction([]common.Address{"dave", "alice"}, []TxOutput{{Value: 100, PubKey: "erin"}}, chain, BuildOptions{}); err == nil {
+		t.Fatalf("expected sources without enough funds to be rejected")
+	}
+}
+
+// TestBuildTransactionConsolidate checks that a send with BuildOptions.Consolidate sweeps additional small outputs
+// of the sender into its change, leaving the sender with fewer unspent outputs than the same send without it
+func TestBuildTransactionConsolidate(t *testing.T) {
+	// The number of unspent outputs of alice after the send, for each consolidation limit
+	counts := make(map[int]int)
+
+	for _, consolidate := range []int{0, 3} {
+		t.Run(fmt.Sprintf("consolidate %v", consolidate), func(t *testing.T) {
+			chain := newTestChain(t, testChainOptions(0))
+
+			// Fragment the funds of alice into small outputs
+			var outputs []TxOutput
+			for _, value := range []int{10, 10, 10, 10, 20, 30} {
+				outputs = append(outputs, TxOutput{Value: value, PubKey: "alice"})
+			}
+
+			if err := chain.MineBlock(Transactions{signedTransaction(t, common.MinerAddress(), []Outpoint{genesisOutpoint(t, chain)}, outputs...)}, ""); err != nil {
+				t.Fatalf("failed to mine block: %v", err)
+			}
+
+			txn := NewTransaction("alice", "bob", 25, chain, BuildOptions{Consolidate: consolidate})
+			fee, err := chain.TransactionFee(txn)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := chain.MineBlock(Transactions{txn}, ""); err != nil {
+				t.Fatalf("failed to mine block: %v", err)
+			}
+
+			utxos, err := chain.FindUTXO("alice")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			balances, err := chain.AllBalances()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if balances["bob"] != 25 || balances["alice"] != 90-25-fee {
+				t.Fatalf("expected bob to be paid 25 and alice to keep the change, got %v", balances)
+			}
+
+			counts[consolidate] = len(utxos)
+		})
+	}
+
+	if counts[3] >= counts[0] {
+		t.Fatalf("expected consolidation to leave fewer unspent outputs, got %v with and %v without", counts[3], counts[0])
+	}
+}
+
+// TestBuildUnsignedTransactionDeterministic checks that building the same Transaction from outputs of several
+// Transactions always orders its inputs the same way, by the Transactions of the spent outputs
+func TestBuildUnsignedTransactionDeterministic(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+
+	// Pay alice with outputs of several Transactions, each spending the change of the previous one
+	var txns Transactions
+	change, remaining := genesisOutpoint(t, chain), 90
+	from := common.MinerAddress()
+
+	for idx := 0; idx < 6; idx++ {
+		remaining -= 10
+		txn := signedTransaction(t, from, []Outpoint{change}, TxOutput{Value: 10, PubKey: "alice"}, TxOutput{Value: remaining, PubKey: "fund"})
+
+		txns = append(txns, txn)
+		change, from = Outpoint{txn.ID, 1}, "fund"
+	}
+
+	if err := chain.MineBlock(txns, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	var built *Transaction
+	for run := 0; run < 8; run++ {
+		txn, err := BuildUnsignedTransaction("alice", []TxOutput{{Value: 50, PubKey: "bob"}}, chain, BuildOptions{})
+		if err != nil {
+			t.Fatalf("failed to build transaction: %v", err)
+		}
+
+		if len(txn.Inputs) < 2 {
+			t.Fatalf("expected the transaction to spend several outputs, got %v inputs", len(txn.Inputs))
+		}
+
+		for idx := 1; idx < len(txn.Inputs); idx++ {
+			if txn.Inputs[idx-1].ID.Cmp(txn.Inputs[idx].ID) >= 0 {
+				t.Fatalf("expected the inputs to be ordered by transaction, got %v", txn.Inputs)
+			}
+		}
+
+		if built != nil && !txn.ID.Equal(built.ID) {
+			t.Fatalf("expected the same transaction on run %v, got '%v' and '%v'", run, txn.ID, built.ID)
+		}
+
+		built = txn
+	}
+}
+
+// TestSigHashID checks that signing a Transaction changes its ID but not its SigHashID,
+// which still changes with the outputs of the Transaction and with the data of a coinbase
+func TestSigHashID(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	miner := common.MinerAddress()
+
+	unsigned, err := BuildUnsignedTransaction(miner, []TxOutput{{Value: 30, PubKey: "alice"}}, chain, BuildOptions{})
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+
+	id, sigHashID := unsigned.ID, unsigned.SigHashID()
+
+	if err := SignTransaction(unsigned, NewWallet(miner)); err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+
+	if unsigned.ID.Equal(id) {
+		t.Fatalf("expected the id of the transaction to change with its signatures")
+	}
+
+	if hash := unsigned.SigHashID(); !hash.Equal(sigHashID) {
+		t.Fatalf("expected the sighash id '%v' to be unchanged by signing, got '%v'", sigHashID, hash)
+	}
+
+	unsigned.Outputs[0].Value++
+	if hash := unsigned.SigHashID(); hash.Equal(sigHashID) {
+		t.Fatalf("expected the sighash id to change with the outputs of the transaction")
+	}
+
+	// The data of a coinbase is part of its SigHashID, so coinbases of different data are distinct
+	coinbase := coinbaseOf(chain, TxOutput{Value: BlockReward, PubKey: miner})
+	other := coinbaseOf(chain, TxOutput{Value: BlockReward, PubKey: miner})
+	other.Inputs[0].Sig = common.Address("other coinbase")
+
+	if coinbase.SigHashID().Equal(other.SigHashID()) {
+		t.Fatalf("expected coinbases with different data to have different sighash ids")
+	}
+}
@@ -0,0 +1,541 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/anee769/essensio/common"
+)
+
+// UTXOPrefix is the key prefix for the entries of the UTXO set in the database
+var UTXOPrefix = []byte("utxo-")
+
+// errStopScan is returned by the callback of a UTXO set scan to stop the scan early
+var errStopScan = errors.New("utxo scan stopped")
+
+// Outpoint represents a reference to a specific output of a Transaction
+type Outpoint struct {
+	// Represents the ID of the Transaction
+	ID common.Hash
+	// Represents the index of the output in the Transaction
+	Index int
+}
+
+// String implements the Stringer interface for Outpoint
+func (outpoint Outpoint) String() string {
+	return fmt.Sprintf("%v:%v", outpoint.ID.Hex(), outpoint.Index)
+}
+
+// Key returns the database key for the Outpoint in the UTXO set
+func (outpoint Outpoint) Key() []byte {
+	return outpoint.prefixedKey(UTXOPrefix)
+}
+
+// prefixedKey returns the database key for the Outpoint with the given key prefix
+func (outpoint Outpoint) prefixedKey(prefix []byte) []byte {
+	key := make([]byte, len(prefix)+common.HashLength+8)
+	copy(key, prefix)
+	copy(key[len(prefix):], outpoint.ID.Bytes())
+	binary.BigEndian.PutUint64(key[len(prefix)+common.HashLength:], uint64(outpoint.Index))
+
+	return key
+}
+
+// outpointFromKey decodes the Outpoint from its database key in the UTXO set.
+// Returns false if the key is not a valid UTXO set key.
+func outpointFromKey(key []byte) (Outpoint, bool) {
+	if len(key) != len(UTXOPrefix)+common.HashLength+8 {
+		return Outpoint{}, false
+	}
+
+	return Outpoint{
+		ID:    common.BytesToHash(key[len(UTXOPrefix) : len(UTXOPrefix)+common.HashLength]),
+		Index: int(binary.BigEndian.Uint64(key[len(UTXOPrefix)+common.HashLength:])),
+	}, true
+}
+
+// IsUnspent returns whether the output referenced by the Outpoint is in the UTXO set
+func (chain *ChainManager) IsUnspent(outpoint Outpoint) (bool, error) {
+	chain.utxoMutex.RLock()
+	defer chain.utxoMutex.RUnlock()
+
+	return chain.isUnspent(outpoint)
+}
+
+// isUnspent is IsUnspent without the UTXO set lock, for callers that already hold it
+func (chain *ChainManager) isUnspent(outpoint Outpoint) (bool, error) {
+	return chain.db.HasEntry(outpoint.Key())
+}
+
+// UTXOEntry represents an output in the UTXO set along with the context of its creation
+type UTXOEntry struct {
+	// Represents the unspent output
+	Output TxOutput
+	// Represents the height of the Block that created the output
+	Height int64
+	// Represents whether the output was created by a coinbase Transaction
+	Coinbase bool
+}
+
+// Confirmations returns the number of Blocks on the chain, of the given
+// height, that confirm the entry. An entry in the chain head has 1 confirmation.
+func (entry *UTXOEntry) Confirmations(height int64) int64 {
+	return height - entry.Height
+}
+
+// IsMature returns whether the entry can be spent in the next Block of a chain of the given height,
+// with the given coinbase maturity. Outputs of coinbase Transactions must have at least maturity
+// confirmations before they are spent, while all other outputs are always mature.
+func (entry *UTXOEntry) IsMature(height, maturity int64) bool {
+	return !entry.Coinbase || entry.Confirmations(height) >= maturity
+}
+
+// GetUTXOEntry returns the entry for the output referenced by the Outpoint from the UTXO set.
+// Returns an error if the output does not exist or has already been spent.
+func (chain *ChainManager) GetUTXOEntry(outpoint Outpoint) (*UTXOEntry, error) {
+	chain.utxoMutex.RLock()
+	defer chain.utxoMutex.RUnlock()
+
+	return chain.getUTXOEntry(outpoint)
+}
+
+// getUTXOEntry is GetUTXOEntry without the UTXO set lock, for callers that already hold it
+func (chain *ChainManager) getUTXOEntry(outpoint Outpoint) (*UTXOEntry, error) {
+	unspent, err := chain.isUnspent(outpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if !unspent {
+		return nil, fmt.Errorf("output %v is not in the utxo set", outpoint)
+	}
+
+	data, err := chain.db.GetEntry(outpoint.Key())
+	if err != nil {
+		return nil, err
+	}
+
+	// Deserialize the data into a UTXOEntry
+	object, err := common.GobDecode(data, new(UTXOEntry))
+	if err != nil {
+		return nil, fmt.Errorf("utxo entry deserialize failed: %w", err)
+	}
+
+	return object.(*UTXOEntry), nil
+}
+
+// GetUnspentOutput returns the output referenced by the Outpoint from the UTXO set.
+// Returns an error if the output does not exist or has already been spent.
+func (chain *ChainManager) GetUnspentOutput(outpoint Outpoint) (*TxOutput, error) {
+	entry, err := chain.GetUTXOEntry(outpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry.Output, nil
+}
+
+// FindOutput returns the output referenced by the Outpoint, regardless of whether it is spent.
+// Returns an error if the Transaction or the output does not exist on the chain.
+func (chain *ChainManager) FindOutput(outpoint Outpoint) (*TxOutput, error) {
+	txn, err := chain.FindTransaction(outpoint.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if outpoint.Index < 0 || outpoint.Index >= len(txn.Outputs) {
+		return nil, fmt.Errorf("output %v not found", outpoint)
+	}
+
+	return &txn.Outputs[outpoint.Index], nil
+}
+
+// updateUTXO applies the Transactions of a Block to the UTXO set.
+// The outputs spent by each Transaction are removed and the outputs it creates are added.
+// The removed entries are stored as the undo data of the Block, except for the outputs created by
+// the Block itself, which are removed rather than restored when the Block is disconnected.
+func (chain *ChainManager) updateUTXO(block *Block) error {
+	undo := make([]UndoEntry, 0)
+
+	// Outputs created by the Block, which are not restored if they are spent by the Block
+	created := make(map[Outpoint]struct{})
+
+	for _, txn := range block.BlockTxns {
+		// Remove the spent outputs from the UTXO set
+		if !txn.IsCoinbase() {
+			for _, input := range txn.Inputs {
+				outpoint := Outpoint{input.ID, input.Out}
+
+				entry, err := chain.getUTXOEntry(outpoint)
+				if err != nil {
+					return fmt.Errorf("utxo retrieve failed: %w", err)
+				}
+
+				if err := chain.db.DeleteEntry(outpoint.Key()); err != nil {
+					return fmt.Errorf("utxo remove failed: %w", err)
+				}
+
+				if _, exists := created[outpoint]; !exists {
+					undo = append(undo, UndoEntry{outpoint, *entry})
+				}
+			}
+		}
+
+		// Add the created outputs to the UTXO set
+		for idx, output := range txn.Outputs {
+			created[Outpoint{txn.ID, idx}] = struct{}{}
+
+			data, err := common.GobEncode(UTXOEntry{output, block.BlockHeight, txn.IsCoinbase()})
+			if err != nil {
+				return fmt.Errorf("utxo entry serialize failed: %w", err)
+			}
+
+			if err := chain.db.SetEntry(Outpoint{txn.ID, idx}.Key(), data); err != nil {
+				return fmt.Errorf("utxo add failed: %w", err)
+			}
+		}
+	}
+
+	// Store the undo data of the Block
+	data, err := common.GobEncode(undo)
+	if err != nil {
+		return fmt.Errorf("undo data serialize failed: %w", err)
+	}
+
+	if err := chain.db.SetEntry(undoKey(block.BlockHash), data); err != nil {
+		return fmt.Errorf("undo data store failed: %w", err)
+	}
+
+	return nil
+}
+
+// scanUTXO calls fn for each entry in the UTXO set along with its Outpoint.
+// The scan stops early if fn returns an error, which is returned.
+func (chain *ChainManager) scanUTXO(fn func(Outpoint, *UTXOEntry) error) error {
+	return chain.scanUTXOContext(context.Background(), fn)
+}
+
+// scanUTXOContext calls fn for each entry in the UTXO set along with its Outpoint.
+// The scan stops early if fn returns an error or the context is done, and the error is returned.
+// The UTXO set lock is held for reading during the scan, so fn must not read the UTXO set itself.
+func (chain *ChainManager) scanUTXOContext(ctx context.Context, fn func(Outpoint, *UTXOEntry) error) error {
+	chain.utxoMutex.RLock()
+	defer chain.utxoMutex.RUnlock()
+
+	return chain.iterateUTXO(ctx, fn)
+}
+
+// iterateUTXO is scanUTXOContext without the UTXO set lock, for callers that already hold it
+func (chain *ChainManager) iterateUTXO(ctx context.Context, fn func(Outpoint, *UTXOEntry) error) error {
+	return chain.db.IteratePrefix(UTXOPrefix, func(key, value []byte) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Decode the Outpoint from the key
+		outpoint, ok := outpointFromKey(key)
+		if !ok {
+			return fmt.Errorf("invalid utxo key: %x", key)
+		}
+
+		// Deserialize the value into a UTXOEntry
+		object, err := common.GobDecode(value, new(UTXOEntry))
+		if err != nil {
+			return fmt.Errorf("utxo entry deserialize failed: %w", err)
+		}
+
+		return fn(outpoint, object.(*UTXOEntry))
+	})
+}
+
+// ForEachUTXO calls fn for each unspent output owned by the Address, along with its Outpoint,
+// without collecting the outputs in memory. The iteration stops early if fn returns false.
+// The UTXO set is locked for reading during the iteration, so fn must not read the UTXO set.
+func (chain *ChainManager) ForEachUTXO(address common.Address, fn func(TxOutput, Outpoint) bool) error {
+	return chain.forEachUTXOEntry(address, func(outpoint Outpoint, entry *UTXOEntry) bool {
+		return fn(entry.Output, outpoint)
+	})
+}
+
+// forEachUTXOEntry calls fn for each entry in the UTXO set owned by the Address, along with its Outpoint.
+// The iteration stops early if fn returns false.
+func (chain *ChainManager) forEachUTXOEntry(address common.Address, fn func(Outpoint, *UTXOEntry) bool) error {
+	err := chain.scanUTXO(func(outpoint Outpoint, entry *UTXOEntry) error {
+		if !entry.Output.CanBeUnlocked(address) {
+			return nil
+		}
+
+		if !fn(outpoint, entry) {
+			return errStopScan
+		}
+
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errStopScan) {
+		return fmt.Errorf("utxo scan failed: %w", err)
+	}
+
+	return nil
+}
+
+// ListedUTXO represents an unspent output owned by an Address, as listed by ListUTXO
+type ListedUTXO struct {
+	UTXOEntry
+
+	// Represents the reference to the output
+	Outpoint Outpoint
+	// Represents the number of confirmations of the output
+	Confirmations int64
+	// Represents whether the output can be spent in the next Block, which is only false for immature coinbase outputs
+	Mature bool
+}
+
+// ListUTXO returns the unspent outputs owned by the Address from the UTXO set, each annotated with its
+// confirmations and whether it is mature for spending according to Options.CoinbaseMaturity
+func (chain *ChainManager) ListUTXO(address common.Address) ([]ListedUTXO, error) {
+	var listed []ListedUTXO
+	if err := chain.forEachUTXOEntry(address, func(outpoint Outpoint, entry *UTXOEntry) bool {
+		listed = append(listed, ListedUTXO{
+			UTXOEntry:     *entry,
+			Outpoint:      outpoint,
+			Confirmations: entry.Confirmations(chain.Height),
+			Mature:        entry.IsMature(chain.Height, chain.opts.CoinbaseMaturity),
+		})
+
+		return true
+	}); err != nil {
+		return nil, err
+	}
+
+	return listed, nil
+}
+
+// FindSpendableOutputs selects unspent outputs owned by the Address from the UTXO set, until their
+// accumulated value reaches the given amount. Outputs with fewer than minConfirmations are skipped, as are
+// immature coinbase outputs, locked outputs and outputs already spent by pending Transactions in the Mempool.
+// Returns the accumulated value and the selected output indexes mapped to their Transaction ID.
+func (chain *ChainManager) FindSpendableOutputs(address common.Address, amount int, minConfirmations int64) (int, map[common.Hash][]int, error) {
+	unspentOuts := make(map[common.Hash][]int)
+	accumulated := 0
+
+	if err := chain.forEachSpendable(address, minConfirmations, func(outpoint Outpoint, entry *UTXOEntry) bool {
+		accumulated += entry.Output.Value
+		unspentOuts[outpoint.ID] = append(unspentOuts[outpoint.ID], outpoint.Index)
+
+		return accumulated < amount
+	}); err != nil {
+		return -1, nil, err
+	}
+
+	return accumulated, unspentOuts, nil
+}
+
+// forEachSpendable calls fn for each unspent output owned by the Address that can be spent in the next Block,
+// skipping outputs with fewer than minConfirmations, immature coinbase outputs, locked outputs and outputs
+// already spent by pending Transactions in the Mempool. The scan stops early if fn returns false.
+func (chain *ChainManager) forEachSpendable(address common.Address, minConfirmations int64, fn func(outpoint Outpoint, entry *UTXOEntry) bool) error {
+	// The clock for the locks of outputs is only computed if a locked output is found
+	var height, median int64
+	var clockErr error
+	clocked := false
+
+	if err := chain.forEachUTXOEntry(address, func(outpoint Outpoint, entry *UTXOEntry) bool {
+		if entry.Confirmations(chain.Height) < minConfirmations || !entry.IsMature(chain.Height, chain.opts.CoinbaseMaturity) {
+			return true
+		}
+
+		if !entry.Output.Lock.IsZero() {
+			if !clocked {
+				if height, median, clockErr = chain.lockClock(); clockErr != nil {
+					return false
+				}
+
+				clocked = true
+			}
+
+			if !entry.Output.Lock.Unlocked(height, median) {
+				return true
+			}
+		}
+
+		if _, pending := chain.mempool.Spender(outpoint); pending {
+			return true
+		}
+
+		return fn(outpoint, entry)
+	}); err != nil {
+		return err
+	}
+
+	return clockErr
+}
+
+// FindConsolidationOutputs selects up to limit of the smallest spendable outputs owned by the Address,
+// excluding the given outputs, to be swept into the change of a Transaction. Outputs are eligible under
+// the same rules as FindSpendableOutputs. Returns the accumulated value and the selected outputs,
+// ordered from the smallest value.
+func (chain *ChainManager) FindConsolidationOutputs(address common.Address, limit int, minConfirmations int64, exclude map[Outpoint]struct{}) (int, []Outpoint, error) {
+	if limit <= 0 {
+		return 0, nil, nil
+	}
+
+	var candidates []ListedUTXO
+	if err := chain.forEachSpendable(address, minConfirmations, func(outpoint Outpoint, entry *UTXOEntry) bool {
+		if _, excluded := exclude[outpoint]; !excluded {
+			candidates = append(candidates, ListedUTXO{UTXOEntry: *entry, Outpoint: outpoint})
+		}
+
+		return true
+	}); err != nil {
+		return -1, nil, err
+	}
+
+	// Order the candidates from the smallest value, breaking ties by outpoint for a deterministic selection
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Output.Value != b.Output.Value {
+			return a.Output.Value < b.Output.Value
+		}
+
+		if cmp := bytes.Compare(a.Outpoint.ID.Bytes(), b.Outpoint.ID.Bytes()); cmp != 0 {
+			return cmp < 0
+		}
+
+		return a.Outpoint.Index < b.Outpoint.Index
+	})
+
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	accumulated := 0
+	selected := make([]Outpoint, 0, len(candidates))
+	for _, candidate := range candidates {
+		accumulated += candidate.Output.Value
+		selected = append(selected, candidate.Outpoint)
+	}
+
+	return accumulated, selected, nil
+}
+
+// FindUTXOMulti returns the unspent outputs for each of the given Addresses
+// with a single scan over the UTXO set. Addresses without outputs are omitted.
+func (chain *ChainManager) FindUTXOMulti(addresses []common.Address) (map[common.Address][]TxOutput, error) {
+	// Collect the set of addresses to look for
+	wanted := make(map[common.Address]struct{}, len(addresses))
+	for _, address := range addresses {
+		wanted[address] = struct{}{}
+	}
+
+	UTXOs := make(map[common.Address][]TxOutput)
+	if err := chain.scanUTXO(func(_ Outpoint, entry *UTXOEntry) error {
+		owner := entry.Output.PubKey
+		if _, ok := wanted[owner]; ok {
+			UTXOs[owner] = append(UTXOs[owner], entry.Output)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("utxo scan failed: %w", err)
+	}
+
+	return UTXOs, nil
+}
+
+// AllBalances returns the total unspent value of every Address
+// with a nonzero balance, with a single scan over the UTXO set.
+func (chain *ChainManager) AllBalances() (map[common.Address]int, error) {
+	return chain.AllBalancesContext(context.Background())
+}
+
+// AllBalancesContext is AllBalances with a context that stops the scan when it is done
+func (chain *ChainManager) AllBalancesContext(ctx context.Context) (map[common.Address]int, error) {
+	chain.utxoMutex.RLock()
+	defer chain.utxoMutex.RUnlock()
+
+	return chain.allBalances(ctx)
+}
+
+// allBalances is AllBalancesContext without the UTXO set lock, for callers that already hold it
+func (chain *ChainManager) allBalances(ctx context.Context) (map[common.Address]int, error) {
+	balances := make(map[common.Address]int)
+	if err := chain.iterateUTXO(ctx, func(_ Outpoint, entry *UTXOEntry) error {
+		balances[entry.Output.PubKey] += entry.Output.Value
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("utxo scan failed: %w", err)
+	}
+
+	// Remove any addresses that only hold zero value outputs
+	for address, balance := range balances {
+		if balance == 0 {
+			delete(balances, address)
+		}
+	}
+
+	return balances, nil
+}
+
+// reindex regenerates the UTXO set, the undo data, the balance snapshots and the transaction and spent
+// indexes by clearing them and replaying every Block of the chain from the Genesis Block.
+func (chain *ChainManager) reindex() error {
+	// Collect and remove all the existing entries of the UTXO set and indexes
+	var keys [][]byte
+	for _, prefix := range [][]byte{UTXOPrefix, TxIndexPrefix, SpentIndexPrefix, UndoPrefix, SnapshotPrefix} {
+		if err := chain.db.IteratePrefix(prefix, func(key, _ []byte) error {
+			keys = append(keys, key)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range keys {
+		if err := chain.db.DeleteEntry(key); err != nil {
+			return err
+		}
+	}
+
+	// Collect the hashes of all Blocks from the head to the Genesis Block
+	hashes := make([]common.Hash, 0, chain.Height)
+	for hash := chain.Head; !hash.IsZero(); {
+		block, err := chain.GetBlock(hash)
+		if err != nil {
+			return err
+		}
+
+		hashes = append(hashes, hash)
+		hash = block.Priori
+	}
+
+	// Apply each Block to the UTXO set and indexes, starting from the Genesis Block
+	for idx := len(hashes) - 1; idx >= 0; idx-- {
+		block, err := chain.GetBlock(hashes[idx])
+		if err != nil {
+			return err
+		}
+
+		if err := chain.updateUTXO(block); err != nil {
+			return err
+		}
+
+		if err := chain.pruneUndo(block); err != nil {
+			return err
+		}
+
+		if err := chain.snapshotBalances(block); err != nil {
+			return err
+		}
+
+		if err := chain.indexTransactions(block); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
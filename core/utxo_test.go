@@ -0,0 +1,254 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestAllBalances checks that the balances aggregated from the UTXO set equal the totals of the outputs
+// returned by FindUTXO for each Address, and that every Address has a nonzero balance
+func TestAllBalances(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(20))
+
+	balances, err := chain.AllBalances()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(balances) < 2 {
+		t.Fatalf("expected the test chain to have balances for several addresses, got %v", balances)
+	}
+
+	total := 0
+	for address, balance := range balances {
+		outputs, err := chain.FindUTXO(address)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sum := 0
+		for _, output := range outputs {
+			sum += output.Value
+		}
+
+		if balance <= 0 || balance != sum {
+			t.Fatalf("expected the balance of %v to be its unspent total %v, got %v", address, sum, balance)
+		}
+
+		total += balance
+	}
+
+	if total != chain.Supply {
+		t.Fatalf("expected the balances to add up to the supply %v, got %v", chain.Supply, total)
+	}
+}
+
+// TestFindSpendableOutputsConfirmations checks that coin selection skips the outputs with fewer than
+// the minimum number of confirmations
+func TestFindSpendableOutputsConfirmations(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	miner := common.MinerAddress()
+
+	// Alice is paid 60 by the Block at height 1 and 40 by the Block at height 2, which is the chain head
+	first := signedTransaction(t, miner, []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: 60, PubKey: "alice"}, TxOutput{Value: 40, PubKey: miner})
+	if err := chain.MineBlock(Transactions{first}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	second := signedTransaction(t, miner, []Outpoint{{first.ID, 1}}, TxOutput{Value: 40, PubKey: "alice"})
+	if err := chain.MineBlock(Transactions{second}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	tests := []struct {
+		minConfirmations int64
+		accumulated      int
+	}{
+		{0, 100},
+		{1, 100},
+		{2, 60},
+		{3, 0},
+	}
+
+	for _, test := range tests {
+		accumulated, outputs, err := chain.FindSpendableOutputs("alice", 100, test.minConfirmations)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if accumulated != test.accumulated {
+			t.Fatalf("expected %v spendable with %v confirmations, got %v in %v", test.accumulated, test.minConfirmations, accumulated, outputs)
+		}
+	}
+
+	if _, err := BuildUnsignedTransaction("alice", []TxOutput{{Value: 80, PubKey: "bob"}}, chain, BuildOptions{MinConfirmations: 2}); err == nil {
+		t.Fatalf("expected the unconfirmed output to be excluded from the transaction")
+	}
+
+	txn, err := BuildUnsignedTransaction("alice", []TxOutput{{Value: 50, PubKey: "bob"}}, chain, BuildOptions{MinConfirmations: 2})
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+
+	if len(txn.Inputs) != 1 || !txn.Inputs[0].ID.Equal(first.ID) {
+		t.Fatalf("expected the transaction to spend only the confirmed output, got %v", txn.Inputs)
+	}
+}
+
+// TestForEachUTXO checks that a full iteration over the unspent outputs of an Address matches FindUTXO,
+// and that the iteration stops as soon as the callback returns false
+func TestForEachUTXO(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	miner := common.MinerAddress()
+
+	// The miner has the coinbase outputs of the genesis Block and of 4 mined Blocks
+	for idx := 0; idx < 4; idx++ {
+		if err := chain.MineBlock(nil, ""); err != nil {
+			t.Fatalf("failed to mine block: %v", err)
+		}
+	}
+
+	outputs, err := chain.FindUTXO(miner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var streamed []TxOutput
+	if err := chain.ForEachUTXO(miner, func(output TxOutput, _ Outpoint) bool {
+		streamed = append(streamed, output)
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(outputs) != 5 || len(streamed) != len(outputs) {
+		t.Fatalf("expected 5 outputs from both FindUTXO and ForEachUTXO, got %v and %v", len(outputs), len(streamed))
+	}
+
+	for idx := range outputs {
+		if streamed[idx] != outputs[idx] {
+			t.Fatalf("expected the streamed outputs %v to match %v", streamed, outputs)
+		}
+	}
+
+	// Stop once the value of two outputs is gathered
+	calls, value := 0, 0
+	if err := chain.ForEachUTXO(miner, func(output TxOutput, _ Outpoint) bool {
+		calls++
+		value += output.Value
+
+		return value < 2*BlockReward
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected the iteration to stop after 2 outputs, got %v", calls)
+	}
+}
+
+// TestListUTXOMaturity checks that recent coinbase outputs are listed as immature and old ones as mature,
+// and that an immature coinbase output cannot be spent
+func TestListUTXOMaturity(t *testing.T) {
+	opts := testChainOptions(0)
+	opts.Options.CoinbaseMaturity = 3
+
+	chain := newTestChain(t, opts)
+	miner := common.MinerAddress()
+
+	for idx := 0; idx < 3; idx++ {
+		if err := chain.MineBlock(nil, ""); err != nil {
+			t.Fatalf("failed to mine block: %v", err)
+		}
+	}
+
+	listed, err := chain.ListUTXO(miner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(listed) != 4 {
+		t.Fatalf("expected the 4 coinbase outputs of the miner, got %v", len(listed))
+	}
+
+	var immature *ListedUTXO
+	for idx, utxo := range listed {
+		if want := utxo.Confirmations >= 3; !utxo.Coinbase || utxo.Mature != want {
+			t.Fatalf("expected the coinbase output at height %v with %v confirmations to be mature: %v", utxo.Height, utxo.Confirmations, want)
+		}
+
+		if utxo.Height == chain.Height-1 {
+			immature = &listed[idx]
+		}
+	}
+
+	if immature == nil || immature.Mature {
+		t.Fatalf("expected the coinbase output of the chain head to be immature")
+	}
+
+	spend := signedTransaction(t, miner, []Outpoint{immature.Outpoint}, TxOutput{Value: BlockReward, PubKey: "alice"})
+	if err := chain.VerifyTransaction(spend); err == nil || !strings.Contains(err.Error(), "confirmations required to spend") {
+		t.Fatalf("expected the immature coinbase output not to be spendable, got %v", err)
+	}
+
+	spend = signedTransaction(t, miner, []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: BlockReward, PubKey: "alice"})
+	if err := chain.VerifyTransaction(spend); err != nil {
+		t.Fatalf("expected the mature genesis output to be spendable, got %v", err)
+	}
+}
+
+// TestFindUnspentTransactionsFrom checks that a minimum height stops the scan of FindUnspentTransactionsFrom
+// before the Blocks below it, and that its partial result holds only the unspent outputs above the floor,
+// which are the outputs returned by FindUTXOFrom for the same floor
+func TestFindUnspentTransactionsFrom(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	miner := common.MinerAddress()
+
+	// The coinbases of the Blocks at heights 1 to 3 pay the miner
+	for idx := 0; idx < 3; idx++ {
+		if err := chain.MineBlock(nil, ""); err != nil {
+			t.Fatalf("failed to mine block: %v", err)
+		}
+	}
+
+	full, err := chain.FindUTXO(miner)
+	if err != nil || len(full) != 4 {
+		t.Fatalf("expected 4 unspent outputs without a floor, got %v and %v", len(full), err)
+	}
+
+	// The scan with a floor never reaches the Genesis Block, which is removed from the database
+	genesis, err := chain.BlockHashAtHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.db.DeleteEntry(genesis.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := chain.FindUnspentTransactions(miner); err == nil {
+		t.Fatalf("expected the full scan to reach the removed genesis block")
+	}
+
+	txns, err := chain.FindUnspentTransactionsFrom(miner, 2)
+	if err != nil {
+		t.Fatalf("expected the scan to stop above the removed genesis block, got %v", err)
+	}
+
+	outputs, err := chain.FindUTXOFrom(miner, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(txns) != 2 || len(outputs) != 2 {
+		t.Fatalf("expected the 2 coinbases above the floor, got %v transactions and %v outputs", len(txns), len(outputs))
+	}
+
+	for idx, txn := range txns {
+		if txn.Height < 2 || txn.Outputs[0] != outputs[0] {
+			t.Fatalf("expected transaction %v to be a coinbase above the floor paying %v, got height %v", idx, outputs[0], txn.Height)
+		}
+	}
+}
@@ -0,0 +1,444 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anee769/essensio/common"
+)
+
+var (
+	// ErrEmptySignature is returned by VerifyTransaction for an input without a signature
+	ErrEmptySignature = errors.New("empty signature")
+	// ErrSignatureMismatch is returned by VerifyTransaction for an input whose
+	// signature does not match the owner of the output it spends
+	ErrSignatureMismatch = errors.New("signature does not match output owner")
+	// ErrMalformedCoinbase is returned by VerifyTransaction for a Transaction with an input that has a null
+	// Transaction ID, unless it is the only input and references output -1, as the input of a coinbase
+	ErrMalformedCoinbase = errors.New("malformed coinbase input")
+)
+
+// DefaultMaxClockSkew is the default maximum time by which a Block timestamp may be ahead of the local clock
+const DefaultMaxClockSkew = 2 * time.Hour
+
+// MaxClockSkew returns the effective maximum time by which a Block timestamp may be ahead of the local clock,
+// which is Options.MaxClockSkew or DefaultMaxClockSkew if it is not set
+func (chain *ChainManager) MaxClockSkew() time.Duration {
+	if chain.opts.MaxClockSkew <= 0 {
+		return DefaultMaxClockSkew
+	}
+
+	return chain.opts.MaxClockSkew
+}
+
+// TxValidator is a hook for custom Transaction policy. It is called by VerifyTransaction for
+// every Transaction that passes the consensus checks and rejects it by returning an error.
+type TxValidator func(txn *Transaction) error
+
+// VerifyTransaction checks that a Transaction is valid for the chain with verifyConsensus,
+// followed by the TxValidator of the chain, if set. The TxValidator is only called for Transactions
+// that are valid by consensus, and so can only reject Transactions and never accept an invalid one.
+func (chain *ChainManager) VerifyTransaction(txn *Transaction) error {
+	_, err := chain.verifyTransaction(txn, nil)
+	return err
+}
+
+// verifyTransaction checks that a Transaction is valid for the chain like VerifyTransaction, with its inputs
+// resolved from the pendingOutputs or the UTXO set, and returns the fee paid by the Transaction
+func (chain *ChainManager) verifyTransaction(txn *Transaction, pending pendingOutputs) (int, error) {
+	fee, err := chain.verifyConsensus(txn, pending)
+	if err != nil {
+		return 0, err
+	}
+
+	if chain.opts.TxValidator != nil {
+		if err := chain.opts.TxValidator(txn); err != nil {
+			return 0, fmt.Errorf("rejected by validator: %w", err)
+		}
+	}
+
+	return fee, nil
+}
+
+// verifyConsensus checks that a Transaction is valid for the chain by consensus.
+// Each input must reference a mature and unlocked output in the pendingOutputs or the UTXO set and be
+// signed by its owner, and the value of the outputs must not exceed the value of the inputs.
+// No Transaction may exceed MaxTxDataSize or have a negative output value, and the Addresses of all outputs must be valid for ValidateAddress.
+// An input with a null Transaction ID is only valid as the single input of a coinbase, with output -1.
+// Coinbase Transactions are only checked for a valid ID, output values, unlocked outputs and data length.
+// Returns the fee paid by the Transaction, which is the value of its inputs not spent by its outputs.
+func (chain *ChainManager) verifyConsensus(txn *Transaction, pending pendingOutputs) (int, error) {
+	// Verify that the ID of the Transaction matches its contents
+	verify := *txn
+	if err := verify.SetID(); err != nil {
+		return 0, fmt.Errorf("transaction id generation failed: %w", err)
+	}
+
+	if !verify.ID.Equal(txn.ID) {
+		return 0, fmt.Errorf("transaction id mismatch: %v", txn.ID)
+	}
+
+	// Verify that the Transaction can be deserialized again, including coinbase Transactions
+	size := txn.Size()
+	if size > MaxTxDataSize {
+		return 0, fmt.Errorf("transaction size %v exceeds maximum transaction data size %v", size, MaxTxDataSize)
+	}
+
+	// Verify that no output has a negative value, which would offset the value of the other outputs
+	for idx, output := range txn.Outputs {
+		if output.Value < 0 {
+			return 0, fmt.Errorf("output %v: negative value %v", idx, output.Value)
+		}
+	}
+
+	// Verify that no output exceeds the configured maximum value
+	if limit := chain.opts.MaxOutputValue; limit > 0 {
+		for idx, output := range txn.Outputs {
+			if output.Value > limit {
+				return 0, fmt.Errorf("output %v: value %v exceeds maximum output value %v", idx, output.Value, limit)
+			}
+		}
+	}
+
+	// Verify that the Addresses of the outputs are valid
+	for idx, output := range txn.Outputs {
+		if err := chain.ValidateAddress(output.PubKey); err != nil {
+			return 0, fmt.Errorf("output %v: %w", idx, err)
+		}
+	}
+
+	// Verify that the locks of the outputs are valid
+	if err := verifyLocks(txn); err != nil {
+		return 0, err
+	}
+
+	// Verify that a null input ID only appears as the single input of a coinbase
+	if !txn.IsCoinbase() {
+		for idx, input := range txn.Inputs {
+			if input.ID.IsZero() {
+				return 0, fmt.Errorf("input %v: %w: output %v of null transaction id in a transaction of %v inputs", idx, ErrMalformedCoinbase, input.Out, len(txn.Inputs))
+			}
+		}
+	}
+
+	if txn.IsCoinbase() {
+		if len(txn.CoinbaseData()) > MaxCoinbaseDataLength {
+			return 0, fmt.Errorf("coinbase data exceeds maximum length %v", MaxCoinbaseDataLength)
+		}
+
+		return 0, nil
+	}
+
+	if len(txn.Inputs) == 0 {
+		return 0, fmt.Errorf("transaction has no inputs")
+	}
+
+	// Verify that no output is below the configured dust threshold
+	if threshold := chain.opts.DustThreshold; threshold > 0 {
+		for idx, output := range txn.Outputs {
+			if output.Value < threshold {
+				return 0, fmt.Errorf("output %v: value %v is below dust threshold %v", idx, output.Value, threshold)
+			}
+		}
+	}
+
+	// Verify that the Transaction does not exceed the configured maximum size
+	if limit := chain.opts.MaxTxSize; limit > 0 && size > limit {
+		return 0, fmt.Errorf("transaction size %v exceeds maximum transaction size %v", size, limit)
+	}
+
+	// Reject unsigned inputs before resolving any of them
+	for idx, input := range txn.Inputs {
+		if input.Sig == common.NullAddress() {
+			return 0, fmt.Errorf("input %v: %w", idx, ErrEmptySignature)
+		}
+	}
+
+	// The clock for the locks of spent outputs is only computed if a spent output is locked
+	var height, median int64
+	clocked := false
+
+	inputs := 0
+	for idx, input := range txn.Inputs {
+		// Find the referenced output in the pending outputs or the UTXO set
+		entry, err := chain.resolveEntry(Outpoint{input.ID, input.Out}, pending)
+		if err != nil {
+			return 0, fmt.Errorf("input %v: %w", idx, err)
+		}
+
+		// Check that a coinbase output has matured
+		if !entry.IsMature(chain.Height, chain.opts.CoinbaseMaturity) {
+			return 0, fmt.Errorf("input %v: coinbase output has %v of %v confirmations required to spend", idx, entry.Confirmations(chain.Height), chain.opts.CoinbaseMaturity)
+		}
+
+		output := &entry.Output
+
+		// Check that a locked output has been unlocked
+		if !output.Lock.IsZero() {
+			if !clocked {
+				if height, median, err = chain.lockClock(); err != nil {
+					return 0, err
+				}
+
+				clocked = true
+			}
+
+			if !output.Lock.Unlocked(height, median) {
+				return 0, fmt.Errorf("input %v: output is locked until %v", idx, output.Lock)
+			}
+		}
+
+		// Check that the input is signed by the owner of the output and commits to the Transaction
+		if err := txn.verifySignature(idx, output.PubKey); err != nil {
+			return 0, fmt.Errorf("input %v: %w", idx, err)
+		}
+
+		inputs += output.Value
+	}
+
+	outputs := 0
+	for _, output := range txn.Outputs {
+		outputs += output.Value
+	}
+
+	if outputs > inputs {
+		return 0, fmt.Errorf("output value %v exceeds input value %v", outputs, inputs)
+	}
+
+	return inputs - outputs, nil
+}
+
+// pendingOutputs holds outputs that are not in the UTXO set of the chain, such as the outputs created by the
+// earlier Transactions of a Block, so that the Transactions that follow them can spend them
+type pendingOutputs map[Outpoint]*UTXOEntry
+
+// add adds the outputs of a Transaction to the pendingOutputs, as created by a Block at the given height
+func (pending pendingOutputs) add(txn *Transaction, height int64) {
+	for idx, output := range txn.Outputs {
+		pending[Outpoint{txn.ID, idx}] = &UTXOEntry{output, height, txn.IsCoinbase()}
+	}
+}
+
+// resolveEntry returns the entry for the output referenced by the Outpoint from the pendingOutputs,
+// or from the UTXO set if it is not pending. Returns an error if the output does not exist or has been spent.
+func (chain *ChainManager) resolveEntry(outpoint Outpoint, pending pendingOutputs) (*UTXOEntry, error) {
+	if entry, exists := pending[outpoint]; exists {
+		return entry, nil
+	}
+
+	return chain.GetUTXOEntry(outpoint)
+}
+
+// ValidateAddress checks that an Address is valid for the outputs of the chain with common.ValidateAddress,
+// limited to Options.MaxAddressLength
+func (chain *ChainManager) ValidateAddress(address common.Address) error {
+	return common.ValidateAddress(address, chain.opts.MaxAddressLength)
+}
+
+// CheckTransaction checks that a standalone non-coinbase Transaction is valid for inclusion
+// in the next Block of the chain. Along with VerifyTransaction, the Transaction must not
+// already exist on the chain. Neither the Transaction nor the chain is modified.
+func (chain *ChainManager) CheckTransaction(txn *Transaction) error {
+	_, err := chain.checkTransaction(txn, nil)
+	return err
+}
+
+// checkTransaction checks that a non-coinbase Transaction is valid for inclusion in the next Block of the chain
+// like CheckTransaction, with its inputs resolved from the pendingOutputs or the UTXO set, and returns its fee
+func (chain *ChainManager) checkTransaction(txn *Transaction, pending pendingOutputs) (int, error) {
+	if txn.IsCoinbase() {
+		return 0, fmt.Errorf("coinbase transaction cannot be submitted")
+	}
+
+	fee, err := chain.verifyTransaction(txn, pending)
+	if err != nil {
+		return 0, err
+	}
+
+	exists, err := chain.HasTransaction(txn.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	if exists {
+		return 0, fmt.Errorf("transaction '%v' already exists on chain", txn.ID)
+	}
+
+	return fee, nil
+}
+
+// verifyTransactions checks that each of the given Transactions is valid for the next Block of the chain.
+// The first Transaction must be the only coinbase of the Block, and the value of its outputs must not
+// exceed the block reward and the fees paid by the other Transactions of the Block.
+// No two Transactions may spend the same output or share an ID with each other or any existing Transaction.
+// Inputs may spend the outputs of earlier Blocks or of earlier Transactions of the same Block, and an input
+// that references the Transaction itself or a later Transaction of the Block is rejected as a forward reference.
+func (chain *ChainManager) verifyTransactions(txns Transactions) error {
+	if len(txns) == 0 || !txns[0].IsCoinbase() {
+		return fmt.Errorf("block does not start with a coinbase transaction")
+	}
+
+	spent := make(map[Outpoint]struct{})
+	seen := make(map[common.Hash]struct{})
+	fees := 0
+
+	// Outputs created by the Transactions of the Block, which can be spent by the Transactions after them
+	pending := make(pendingOutputs)
+
+	// Index the position of each Transaction in the Block
+	positions := make(map[common.Hash]int, len(txns))
+	for pos, txn := range txns {
+		if _, exists := positions[txn.ID]; !exists {
+			positions[txn.ID] = pos
+		}
+	}
+
+	for pos, txn := range txns {
+		// Check that no input references the Transaction itself or a later Transaction in the Block
+		if !txn.IsCoinbase() {
+			for idx, input := range txn.Inputs {
+				if ref, exists := positions[input.ID]; exists && ref >= pos {
+					return fmt.Errorf("transaction '%v': input %v references transaction at position %v of the block, which is not before it", txn.ID, idx, ref)
+				}
+			}
+		}
+
+		fee, err := chain.verifyTransaction(txn, pending)
+		if err != nil {
+			return fmt.Errorf("transaction '%v': %w", txn.ID, err)
+		}
+
+		fees += fee
+
+		// Check that the ID of the Transaction is unique
+		if _, exists := seen[txn.ID]; exists {
+			return fmt.Errorf("transaction '%v' is duplicated in block", txn.ID)
+		}
+
+		exists, err := chain.HasTransaction(txn.ID)
+		if err != nil {
+			return fmt.Errorf("transaction '%v': %w", txn.ID, err)
+		}
+
+		if exists {
+			return fmt.Errorf("transaction '%v' already exists on chain", txn.ID)
+		}
+
+		seen[txn.ID] = struct{}{}
+		pending.add(txn, chain.Height)
+
+		if txn.IsCoinbase() {
+			if pos > 0 {
+				return fmt.Errorf("transaction '%v': coinbase at position %v of the block, only the first transaction may be a coinbase", txn.ID, pos)
+			}
+
+			// Check that the coinbase is committed to the height of the Block
+			if txn.Height != chain.Height {
+				return fmt.Errorf("transaction '%v': coinbase height %v does not match block height %v", txn.ID, txn.Height, chain.Height)
+			}
+
+			continue
+		}
+
+		for _, input := range txn.Inputs {
+			outpoint := Outpoint{input.ID, input.Out}
+			if _, exists := spent[outpoint]; exists {
+				return fmt.Errorf("transaction '%v': output %v is spent more than once", txn.ID, outpoint)
+			}
+
+			spent[outpoint] = struct{}{}
+		}
+	}
+
+	// Check that the coinbase does not pay more than the block reward and the fees of the Block
+	reward := 0
+	for _, output := range txns[0].Outputs {
+		reward += output.Value
+	}
+
+	if reward > BlockReward+fees {
+		return fmt.Errorf("coinbase output value %v exceeds block reward %v and fees %v", reward, BlockReward, fees)
+	}
+
+	return nil
+}
+
+// VerifyBlock checks that a Block is valid to be appended to the chain.
+// Along with the integrity of the Block, its header must extend the chain head,
+// its Transactions must be valid for the chain and it must not exceed MaxBlockDataSize.
+func (chain *ChainManager) VerifyBlock(block *Block) error {
+	if err := checkBlockSize(block); err != nil {
+		return err
+	}
+
+	if err := chain.verifyHeader(block); err != nil {
+		return err
+	}
+
+	return chain.verifyTransactions(block.BlockTxns)
+}
+
+// verifyHeader checks the integrity of a Block and that it extends the chain head with a timestamp
+// no earlier than the median time past of the last MedianTimeBlocks Blocks, and no later than
+// the local clock by more than MaxClockSkew.
+// Only the Genesis Block at height 0 of an empty chain may have a null Priori and skips the other
+// checks, the Priori of every other Block must resolve to an existing Block on the chain.
+func (chain *ChainManager) verifyHeader(block *Block) error {
+	if err := block.CheckIntegrity(); err != nil {
+		return err
+	}
+
+	if block.BlockHeight == 0 && chain.Height == 0 {
+		if !block.Priori.IsZero() {
+			return fmt.Errorf("genesis block must have a null priori")
+		}
+
+		return nil
+	}
+
+	if block.Priori.IsZero() {
+		return fmt.Errorf("block at height %v has a null priori and does not extend the chain head", block.BlockHeight)
+	}
+
+	exists, err := chain.db.HasEntry(block.Priori.Bytes())
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("priori block '%v' does not exist", block.Priori)
+	}
+
+	if !block.Priori.Equal(chain.Head) || block.BlockHeight != chain.Height {
+		return fmt.Errorf("block at height %v does not extend the chain head", block.BlockHeight)
+	}
+
+	// Check that the Block is not older than the median time past of the chain
+	median, err := chain.MedianTimePast(MedianTimeBlocks)
+	if err != nil {
+		return err
+	}
+
+	if block.Timestamp < median {
+		return fmt.Errorf("block timestamp %v is before median time past %v", block.Timestamp, median)
+	}
+
+	// Check that the Block is not too far in the future of the local clock
+	skew := chain.MaxClockSkew()
+	if limit := time.Now().Add(skew).Unix(); block.Timestamp > limit {
+		return fmt.Errorf("block timestamp %v is more than %v ahead of the local clock", block.Timestamp, skew)
+	}
+
+	// Check that the Block uses the expected PoW target
+	bits, err := chain.nextBits()
+	if err != nil {
+		return err
+	}
+
+	if block.Bits != bits {
+		return fmt.Errorf("block target bits %08x do not match expected bits %08x", block.Bits, bits)
+	}
+
+	return nil
+}
@@ -0,0 +1,592 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anee769/essensio/common"
+)
+
+// coinbaseOf returns a coinbase Transaction for the next Block of the chain that pays the given outputs
+func coinbaseOf(chain *ChainManager, outputs ...TxOutput) *Transaction {
+	txn := &Transaction{Inputs: []TxInput{{common.NullHash(), -1, common.Address("test coinbase")}}, Outputs: outputs, Height: chain.Height}
+	txn.SetID()
+
+	return txn
+}
+
+// TestVerifyTransactionsCoinbase checks that a Block must have exactly one coinbase Transaction,
+// as its first Transaction, which pays no more than the block reward and the fees of the Block
+func TestVerifyTransactionsCoinbase(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	miner := common.MinerAddress()
+
+	// Spends the genesis reward with a fee of 10
+	spend := signedTransaction(t, miner, []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: BlockReward - 10, PubKey: "recipient"})
+
+	tests := []struct {
+		name  string
+		txns  Transactions
+		error string
+	}{
+		{"no transactions", nil, "does not start with a coinbase"},
+		{"no coinbase", Transactions{spend}, "does not start with a coinbase"},
+		{"coinbase not first", Transactions{spend, coinbaseOf(chain, TxOutput{Value: BlockReward, PubKey: miner})}, "does not start with a coinbase"},
+		{"second coinbase", Transactions{
+			coinbaseOf(chain, TxOutput{Value: BlockReward, PubKey: miner}),
+			CoinbaseTxn("other", "second coinbase", chain.Height),
+		}, "only the first transaction may be a coinbase"},
+		{"excess reward", Transactions{coinbaseOf(chain, TxOutput{Value: BlockReward + 1, PubKey: miner})}, "exceeds block reward"},
+		{"excess fees", Transactions{coinbaseOf(chain, TxOutput{Value: BlockReward + 11, PubKey: miner}), spend}, "exceeds block reward"},
+		{"reward", Transactions{coinbaseOf(chain, TxOutput{Value: BlockReward, PubKey: miner})}, ""},
+		{"reward and fees", Transactions{coinbaseOf(chain, TxOutput{Value: BlockReward + 10, PubKey: miner}), spend}, ""},
+		{"split reward", Transactions{coinbaseOf(chain, TxOutput{Value: 60, PubKey: miner}, TxOutput{Value: 40, PubKey: "pool"})}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := chain.verifyTransactions(test.txns)
+
+			if test.error == "" {
+				if err != nil {
+					t.Fatalf("expected transactions to be valid, got %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), test.error) {
+				t.Fatalf("expected error containing %q, got %v", test.error, err)
+			}
+		})
+	}
+}
+
+// TestAcceptBlockCoinbase checks that AcceptBlock rejects a Block whose coinbase overpays the miner
+func TestAcceptBlockCoinbase(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+
+	bits, err := chain.nextBits()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block := NewBlock(Transactions{coinbaseOf(chain, TxOutput{Value: 2 * BlockReward, PubKey: common.MinerAddress()})}, chain.Head, chain.Height, bits)
+	if err := chain.AcceptBlock(block); err == nil || !strings.Contains(err.Error(), "exceeds block reward") {
+		t.Fatalf("expected the block to be rejected for its coinbase value, got %v", err)
+	}
+
+	if chain.Height != 1 {
+		t.Fatalf("expected the chain height to remain 1, got %v", chain.Height)
+	}
+}
+
+// TestVerifyTransactionsSameBlock checks that a Transaction may spend the output of an earlier Transaction
+// of the same Block, but not of a later Transaction, which is a forward reference
+func TestVerifyTransactionsSameBlock(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	miner := common.MinerAddress()
+
+	parent := signedTransaction(t, miner, []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: BlockReward, PubKey: "child"})
+	child := signedTransaction(t, "child", []Outpoint{{parent.ID, 0}}, TxOutput{Value: BlockReward, PubKey: "grandchild"})
+	coinbase := coinbaseOf(chain, TxOutput{Value: BlockReward, PubKey: miner})
+
+	if err := chain.verifyTransactions(Transactions{coinbase, parent, child}); err != nil {
+		t.Fatalf("expected a spend of an earlier transaction of the block to be valid, got %v", err)
+	}
+
+	err := chain.verifyTransactions(Transactions{coinbase, child, parent})
+	if err == nil || !strings.Contains(err.Error(), "which is not before it") {
+		t.Fatalf("expected a forward reference to be rejected, got %v", err)
+	}
+
+	// A second spend of the same output of an earlier Transaction is a double spend
+	other := signedTransaction(t, "child", []Outpoint{{parent.ID, 0}}, TxOutput{Value: BlockReward, PubKey: "other"})
+
+	err = chain.verifyTransactions(Transactions{coinbase, parent, child, other})
+	if err == nil || !strings.Contains(err.Error(), "spent more than once") {
+		t.Fatalf("expected a double spend of an output of the block to be rejected, got %v", err)
+	}
+}
+
+// TestVerifyTransactionsSameBlockCoinbase checks that the coinbase output of a Block is subject
+// to the coinbase maturity when it is spent by a later Transaction of the same Block
+func TestVerifyTransactionsSameBlockCoinbase(t *testing.T) {
+	opts := testChainOptions(0)
+	opts.Options.CoinbaseMaturity = 1
+
+	chain := newTestChain(t, opts)
+	miner := common.MinerAddress()
+
+	coinbase := coinbaseOf(chain, TxOutput{Value: BlockReward, PubKey: miner})
+	spend := signedTransaction(t, miner, []Outpoint{{coinbase.ID, 0}}, TxOutput{Value: BlockReward, PubKey: "recipient"})
+
+	err := chain.verifyTransactions(Transactions{coinbase, spend})
+	if err == nil || !strings.Contains(err.Error(), "confirmations required") {
+		t.Fatalf("expected an immature coinbase spend to be rejected, got %v", err)
+	}
+}
+
+// TestDisconnectSameBlockSpend checks that disconnecting a Block that spends its own outputs
+// restores the UTXO set to its state before the Block
+func TestDisconnectSameBlockSpend(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	miner, genesis := common.MinerAddress(), genesisOutpoint(t, chain)
+
+	parent := signedTransaction(t, miner, []Outpoint{genesis}, TxOutput{Value: BlockReward, PubKey: "child"})
+	child := signedTransaction(t, "child", []Outpoint{{parent.ID, 0}}, TxOutput{Value: BlockReward, PubKey: "grandchild"})
+
+	if err := chain.MineBlock(Transactions{parent, child}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	unspent := func(outpoint Outpoint) bool {
+		exists, err := chain.IsUnspent(outpoint)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return exists
+	}
+
+	if unspent(genesis) || unspent(Outpoint{parent.ID, 0}) || !unspent(Outpoint{child.ID, 0}) {
+		t.Fatalf("expected only the output of the child to be unspent after the block")
+	}
+
+	if _, err := chain.disconnectTip(); err != nil {
+		t.Fatalf("failed to disconnect block: %v", err)
+	}
+
+	if !unspent(genesis) || unspent(Outpoint{parent.ID, 0}) || unspent(Outpoint{child.ID, 0}) {
+		t.Fatalf("expected only the genesis output to be unspent after the block is disconnected")
+	}
+}
+
+// TestVerifyTransactionSpent checks that a Transaction which spends an output already spent
+// by an earlier Block is rejected, since the output is no longer in the UTXO set
+func TestVerifyTransactionSpent(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	miner, genesis := common.MinerAddress(), genesisOutpoint(t, chain)
+
+	spend := signedTransaction(t, miner, []Outpoint{genesis}, TxOutput{Value: BlockReward, PubKey: "alice"})
+	if err := chain.VerifyTransaction(spend); err != nil {
+		t.Fatalf("expected the first spend to be valid, got %v", err)
+	}
+
+	if err := chain.MineBlock(Transactions{spend}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	respend := signedTransaction(t, miner, []Outpoint{genesis}, TxOutput{Value: BlockReward, PubKey: "bob"})
+	if err := chain.VerifyTransaction(respend); err == nil || !strings.Contains(err.Error(), "not in the utxo set") {
+		t.Fatalf("expected a spend of a spent output to be rejected, got %v", err)
+	}
+
+	if err := chain.MineBlock(Transactions{respend}, ""); err == nil {
+		t.Fatalf("expected a block that spends a spent output to be rejected")
+	}
+}
+
+// TestMaxOutputValue checks that outputs above Options.MaxOutputValue are rejected, including those of a coinbase,
+// while outputs at the maximum are valid
+func TestMaxOutputValue(t *testing.T) {
+	opts := testChainOptions(0)
+	opts.Options.MaxOutputValue = 60
+
+	chain := newTestChain(t, opts)
+	miner, genesis := common.MinerAddress(), genesisOutpoint(t, chain)
+
+	tests := []struct {
+		name  string
+		txn   *Transaction
+		valid bool
+	}{
+		{"at maximum", signedTransaction(t, miner, []Outpoint{genesis}, TxOutput{Value: 60, PubKey: "alice"}, TxOutput{Value: 40, PubKey: miner}), true},
+		{"over maximum", signedTransaction(t, miner, []Outpoint{genesis}, TxOutput{Value: 61, PubKey: "alice"}, TxOutput{Value: 39, PubKey: miner}), false},
+		{"coinbase at maximum", coinbaseOf(chain, TxOutput{Value: 60, PubKey: miner}, TxOutput{Value: 40, PubKey: "pool"}), true},
+		{"coinbase over maximum", coinbaseOf(chain, TxOutput{Value: BlockReward, PubKey: miner}), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := chain.VerifyTransaction(test.txn)
+			if test.valid && err != nil {
+				t.Fatalf("expected the transaction to be valid, got %v", err)
+			}
+
+			if !test.valid && (err == nil || !strings.Contains(err.Error(), "exceeds maximum output value")) {
+				t.Fatalf("expected the transaction to be rejected for its output value, got %v", err)
+			}
+		})
+	}
+}
+
+// TestNegativeOutputValue checks that Transactions and coinbases with a negative output are rejected,
+// so that a negative output cannot offset another output that creates value
+func TestNegativeOutputValue(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	miner, genesis := common.MinerAddress(), genesisOutpoint(t, chain)
+
+	spend := signedTransaction(t, miner, []Outpoint{genesis}, TxOutput{Value: -1000, PubKey: "alice"}, TxOutput{Value: 1100, PubKey: "bob"})
+	coinbase := coinbaseOf(chain, TxOutput{Value: 3 * BlockReward, PubKey: miner}, TxOutput{Value: -2 * BlockReward, PubKey: "pool"})
+
+	for _, txn := range []*Transaction{spend, coinbase} {
+		if err := chain.VerifyTransaction(txn); err == nil || !strings.Contains(err.Error(), "negative value") {
+			t.Fatalf("expected the transaction to be rejected for its negative output, got %v", err)
+		}
+	}
+
+	if err := chain.MineBlock(Transactions{spend}, ""); err == nil {
+		t.Fatalf("expected a block with a negative output to be rejected")
+	}
+
+	bits, err := chain.nextBits()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.AcceptBlock(NewBlock(Transactions{coinbase}, chain.Head, chain.Height, bits)); err == nil {
+		t.Fatalf("expected a block with a negative coinbase output to be rejected")
+	}
+
+	balances, err := chain.AllBalances()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if balances["bob"] != 0 || chain.Height != 1 {
+		t.Fatalf("expected no block to be added and bob to have no balance, got %v", balances["bob"])
+	}
+}
+
+// TestCoinbaseUniqueIDs checks that coinbase Transactions with the same data and outputs have distinct IDs
+// at different heights, so that their outputs do not collide, and that a Transaction ID already on the chain is rejected
+func TestCoinbaseUniqueIDs(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	miner := common.MinerAddress()
+
+	first, second := CoinbaseTxn(miner, "same data", 1), CoinbaseTxn(miner, "same data", 2)
+	if first.ID.Equal(second.ID) {
+		t.Fatalf("expected coinbases at different heights to have distinct IDs")
+	}
+
+	for idx := 0; idx < 2; idx++ {
+		if err := chain.MineBlock(nil, "same data"); err != nil {
+			t.Fatalf("failed to mine block: %v", err)
+		}
+	}
+
+	// Both coinbase outputs must be unspent and indexed
+	for height := int64(1); height <= 2; height++ {
+		block, err := chain.GetBlockByHeight(height)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		coinbase := block.BlockTxns[0]
+		if exists, err := chain.IsUnspent(Outpoint{coinbase.ID, 0}); err != nil || !exists {
+			t.Fatalf("expected the coinbase output at height %v to be unspent, got %v", height, err)
+		}
+
+		found, err := chain.FindTransactionBlock(coinbase.ID)
+		if err != nil || found.BlockHeight != height {
+			t.Fatalf("expected the coinbase at height %v to be indexed to its block, got %v", height, err)
+		}
+	}
+
+	genesis, err := chain.GetBlockByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = chain.verifyTransactions(Transactions{coinbaseOf(chain, TxOutput{Value: BlockReward, PubKey: miner}), genesis.BlockTxns[0]})
+	if err == nil || !strings.Contains(err.Error(), "already exists on chain") {
+		t.Fatalf("expected a transaction already on the chain to be rejected, got %v", err)
+	}
+}
+
+// TestVerifyHeaderPriori checks that only the Genesis Block of an empty chain may have a null priori,
+// and that the priori of every other Block must resolve to an existing Block
+func TestVerifyHeaderPriori(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+
+	bits, err := chain.nextBits()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	coinbase := Transactions{coinbaseOf(chain, TxOutput{Value: BlockReward, PubKey: common.MinerAddress()})}
+
+	tests := []struct {
+		name  string
+		block *Block
+		error string
+	}{
+		{"null priori", NewBlock(coinbase, common.NullHash(), chain.Height, bits), "has a null priori"},
+		{"unknown priori", NewBlock(coinbase, common.BytesToHash([]byte("unknown")), chain.Height, bits), "does not exist"},
+		{"genesis with priori", NewBlock(coinbase, chain.Head, 0, bits), "does not extend the chain head"},
+		{"second genesis", NewBlock(coinbase, common.NullHash(), 0, bits), "does not extend the chain head"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := chain.verifyHeader(test.block); err == nil || !strings.Contains(err.Error(), test.error) {
+				t.Fatalf("expected error containing %q, got %v", test.error, err)
+			}
+		})
+	}
+
+	if err := chain.AcceptBlock(tests[0].block); err == nil {
+		t.Fatalf("expected a second genesis block to be rejected")
+	}
+
+	if chain.Height != 1 {
+		t.Fatalf("expected the chain height to remain 1, got %v", chain.Height)
+	}
+}
+
+// TestAcceptBlockGenesis checks that a Block at height 0 with a null priori is rejected by a chain that is not empty,
+// without replacing the chain head or the Genesis Block at height 0
+func TestAcceptBlockGenesis(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(3))
+	head, height := chain.Head, chain.Height
+
+	genesis, err := chain.BlockHashAtHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rogue := NewBlock(Transactions{coinbaseOf(chain, TxOutput{Value: BlockReward, PubKey: "mallory"})}, common.NullHash(), 0, 0x2100ffff)
+	if err := chain.AcceptBlock(rogue); err == nil || !strings.Contains(err.Error(), "does not extend the chain head") {
+		t.Fatalf("expected the rogue genesis block to be rejected, got %v", err)
+	}
+
+	if !chain.Head.Equal(head) || chain.Height != height {
+		t.Fatalf("expected the chain head to be unchanged by the rogue genesis block")
+	}
+
+	if hash, err := chain.BlockHashAtHeight(0); err != nil || !hash.Equal(genesis) {
+		t.Fatalf("expected the genesis block '%v' at height 0, got '%v' (%v)", genesis, hash, err)
+	}
+
+	if err := chain.VerifyChain(); err != nil {
+		t.Fatalf("expected the chain to remain valid, got %v", err)
+	}
+}
+
+// TestTxValidator checks that the TxValidator of a chain rejects the Transactions that it blocks, both when they are
+// verified and when they are mined, and that it is never called for Transactions that are invalid by consensus
+func TestTxValidator(t *testing.T) {
+	calls := 0
+
+	opts := testChainOptions(0)
+	opts.Options.TxValidator = func(txn *Transaction) error {
+		calls++
+
+		for _, output := range txn.Outputs {
+			if output.PubKey == "blocked" {
+				return errors.New("address is blocked")
+			}
+		}
+
+		return nil
+	}
+
+	chain := newTestChain(t, opts)
+	miner, genesis := common.MinerAddress(), genesisOutpoint(t, chain)
+
+	blocked := signedTransaction(t, miner, []Outpoint{genesis}, TxOutput{Value: BlockReward, PubKey: "blocked"})
+	if err := chain.VerifyTransaction(blocked); err == nil || !strings.Contains(err.Error(), "rejected by validator: address is blocked") {
+		t.Fatalf("expected the blocked transaction to be rejected, got %v", err)
+	}
+
+	if err := chain.MineBlock(Transactions{blocked}, ""); err == nil {
+		t.Fatalf("expected a block with the blocked transaction to be rejected")
+	}
+
+	// The validator accepts every other Transaction, but cannot accept an overspend
+	calls = 0
+
+	overspend := signedTransaction(t, miner, []Outpoint{genesis}, TxOutput{Value: BlockReward + 1, PubKey: "alice"})
+	if err := chain.VerifyTransaction(overspend); err == nil || !strings.Contains(err.Error(), "exceeds input value") {
+		t.Fatalf("expected the overspend to be rejected, got %v", err)
+	}
+
+	if calls != 0 {
+		t.Fatalf("expected the validator not to be called for an invalid transaction")
+	}
+
+	allowed := signedTransaction(t, miner, []Outpoint{genesis}, TxOutput{Value: BlockReward, PubKey: "alice"})
+	if err := chain.VerifyTransaction(allowed); err != nil || calls != 1 {
+		t.Fatalf("expected the allowed transaction to be valid after one validator call, got %v after %v calls", err, calls)
+	}
+}
+
+// TestDustThreshold checks that non-coinbase outputs below Options.DustThreshold are rejected,
+// while outputs at the threshold and coinbase outputs below it are valid
+func TestDustThreshold(t *testing.T) {
+	opts := testChainOptions(0)
+	opts.Options.DustThreshold = 10
+
+	chain := newTestChain(t, opts)
+	miner, genesis := common.MinerAddress(), genesisOutpoint(t, chain)
+
+	tests := []struct {
+		name  string
+		txn   *Transaction
+		valid bool
+	}{
+		{"at threshold", signedTransaction(t, miner, []Outpoint{genesis}, TxOutput{Value: 10, PubKey: "alice"}, TxOutput{Value: 90, PubKey: miner}), true},
+		{"below threshold", signedTransaction(t, miner, []Outpoint{genesis}, TxOutput{Value: 9, PubKey: "alice"}, TxOutput{Value: 91, PubKey: miner}), false},
+		{"coinbase below threshold", coinbaseOf(chain, TxOutput{Value: 95, PubKey: miner}, TxOutput{Value: 5, PubKey: "pool"}), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := chain.VerifyTransaction(test.txn)
+			if test.valid && err != nil {
+				t.Fatalf("expected the transaction to be valid, got %v", err)
+			}
+
+			if !test.valid && (err == nil || !strings.Contains(err.Error(), "below dust threshold")) {
+				t.Fatalf("expected the transaction to be rejected as dust, got %v", err)
+			}
+		})
+	}
+}
+
+// TestVerifyTransactionSignatures checks that inputs without a signature and inputs signed by an Address
+// other than the owner of the spent output are rejected with distinct errors
+func TestVerifyTransactionSignatures(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	genesis := genesisOutpoint(t, chain)
+
+	empty := signedTransaction(t, common.MinerAddress(), []Outpoint{genesis}, TxOutput{Value: BlockReward, PubKey: "alice"})
+	empty.Inputs[0].Sig = common.NullAddress()
+	empty.SetID()
+
+	mismatched := signedTransaction(t, "mallory", []Outpoint{genesis}, TxOutput{Value: BlockReward, PubKey: "mallory"})
+
+	// An unsigned input is rejected even if it spends an unknown output
+	unknown := &Transaction{Inputs: []TxInput{{common.Hash256([]byte("unknown")), 0, common.NullAddress()}}, Outputs: []TxOutput{{Value: 1, PubKey: "alice"}}}
+	unknown.SetID()
+
+	tests := []struct {
+		name string
+		txn  *Transaction
+		err  error
+	}{
+		{"empty signature", empty, ErrEmptySignature},
+		{"empty signature of unknown output", unknown, ErrEmptySignature},
+		{"mismatched signature", mismatched, ErrSignatureMismatch},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := chain.VerifyTransaction(test.txn); !errors.Is(err, test.err) {
+				t.Fatalf("expected error %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+// TestMaxClockSkew checks that a Block ahead of the local clock is accepted within the configured clock skew
+// and rejected beyond it, and that the default skew is used if none is configured
+func TestMaxClockSkew(t *testing.T) {
+	opts := testChainOptions(0)
+	opts.Options.MaxClockSkew = 10 * time.Minute
+
+	chain := newTestChain(t, opts)
+	if skew := chain.MaxClockSkew(); skew != 10*time.Minute {
+		t.Fatalf("expected the configured clock skew, got %v", skew)
+	}
+
+	beyond := timedBlock(t, chain, time.Now().Add(20*time.Minute).Unix())
+	if err := chain.AcceptBlock(beyond); err == nil || !strings.Contains(err.Error(), "ahead of the local clock") {
+		t.Fatalf("expected a block beyond the clock skew to be rejected, got %v", err)
+	}
+
+	within := timedBlock(t, chain, time.Now().Add(5*time.Minute).Unix())
+	if err := chain.AcceptBlock(within); err != nil {
+		t.Fatalf("expected a block within the clock skew to be accepted, got %v", err)
+	}
+
+	chain.opts.MaxClockSkew = 0
+	if skew := chain.MaxClockSkew(); skew != DefaultMaxClockSkew {
+		t.Fatalf("expected the default clock skew %v, got %v", DefaultMaxClockSkew, skew)
+	}
+}
+
+// TestMalformedCoinbase checks that inputs with a null transaction ID are rejected unless they are the single
+// input of a coinbase with output -1, for a null ID input with output 0 and for a coinbase with an extra real input
+func TestMalformedCoinbase(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(0))
+	genesis := genesisOutpoint(t, chain)
+
+	tests := []struct {
+		name   string
+		inputs []TxInput
+	}{
+		{"null id with output 0", []TxInput{{common.NullHash(), 0, common.Address("test coinbase")}}},
+		{"coinbase with a real input", []TxInput{{common.NullHash(), -1, common.Address("test coinbase")}, {genesis.ID, genesis.Index, common.NullAddress()}}},
+	}
+
+	for _, test := range tests {
+		txn := &Transaction{Inputs: test.inputs, Outputs: []TxOutput{{Value: 10, PubKey: "alice"}}, Height: chain.Height}
+		if err := txn.SetID(); err != nil {
+			t.Fatal(err)
+		}
+
+		if txn.IsCoinbase() {
+			t.Fatalf("%v: expected the transaction not to be a coinbase", test.name)
+		}
+
+		if err := chain.VerifyTransaction(txn); !errors.Is(err, ErrMalformedCoinbase) {
+			t.Errorf("%v: expected the transaction to be rejected as a malformed coinbase, got %v", test.name, err)
+		}
+	}
+
+	if err := chain.VerifyTransaction(coinbaseOf(chain, TxOutput{Value: BlockReward, PubKey: "alice"})); errors.Is(err, ErrMalformedCoinbase) {
+		t.Fatalf("expected a well-formed coinbase not to be malformed, got %v", err)
+	}
+}
+
+// TestVerifyTransactionAddress checks that Transactions and coinbases paying an over-long Address
+// or an Address with disallowed characters are rejected, unless the length is not limited
+func TestVerifyTransactionAddress(t *testing.T) {
+	long := common.Address(strings.Repeat("a", common.DefaultMaxAddressLength+1))
+
+	chain := newTestChain(t, testChainOptions(0))
+	miner, genesis := common.MinerAddress(), genesisOutpoint(t, chain)
+
+	tests := []struct {
+		name  string
+		txn   *Transaction
+		valid bool
+	}{
+		{"valid", signedTransaction(t, miner, []Outpoint{genesis}, TxOutput{Value: 90, PubKey: "alice"}), true},
+		{"over-long", signedTransaction(t, miner, []Outpoint{genesis}, TxOutput{Value: 90, PubKey: long}), false},
+		{"disallowed character", signedTransaction(t, miner, []Outpoint{genesis}, TxOutput{Value: 90, PubKey: "al ice"}), false},
+		{"coinbase", coinbaseOf(chain, TxOutput{Value: BlockReward, PubKey: "pool/1"}), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := chain.VerifyTransaction(test.txn)
+			if test.valid && err != nil {
+				t.Fatalf("expected the transaction to be valid, got %v", err)
+			}
+
+			if !test.valid && !errors.Is(err, common.ErrInvalidAddress) {
+				t.Fatalf("expected the transaction to be rejected for an invalid address, got %v", err)
+			}
+		})
+	}
+
+	chain.opts.MaxAddressLength = 0
+	if err := chain.VerifyTransaction(tests[1].txn); err != nil {
+		t.Fatalf("expected an unlimited address length to allow the long address, got %v", err)
+	}
+}
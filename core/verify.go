@@ -0,0 +1,100 @@
+package core
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/anee769/essensio/common"
+)
+
+// VerifyHeightIndex checks the consistency of the height index with the chain. Every height from 0 to
+// the chain height must map to exactly one Block with a matching BlockHeight, without any gaps, entries
+// beyond the chain height or Blocks indexed at more than one height, and the chain head must be indexed
+// at the last height. It detects corruption of the index, independently of the Blocks themselves.
+func (chain *ChainManager) VerifyHeightIndex() error {
+	indexed := make(map[int64]common.Hash)
+	heights := make(map[common.Hash]int64)
+
+	if err := chain.db.IteratePrefix(HeightIndexPrefix, func(key, value []byte) error {
+		if len(key) != len(HeightIndexPrefix)+8 {
+			return fmt.Errorf("height index has a malformed key '%x'", key)
+		}
+
+		height := int64(binary.BigEndian.Uint64(key[len(HeightIndexPrefix):]))
+		if height < 0 || height >= chain.Height {
+			return fmt.Errorf("height index has an entry at height %v beyond chain height %v", height, chain.Height)
+		}
+
+		hash := common.BytesToHash(value)
+		if other, exists := heights[hash]; exists {
+			return fmt.Errorf("block '%v' is indexed at heights %v and %v", hash, other, height)
+		}
+
+		indexed[height], heights[hash] = hash, height
+		return nil
+	}); err != nil {
+		return fmt.Errorf("height index scan failed: %w", err)
+	}
+
+	for height := int64(0); height < chain.Height; height++ {
+		hash, exists := indexed[height]
+		if !exists {
+			return fmt.Errorf("height index has no entry at height %v", height)
+		}
+
+		block, err := chain.GetBlock(hash)
+		if err != nil {
+			return fmt.Errorf("height index entry at height %v: %w", height, err)
+		}
+
+		if block.BlockHeight != height {
+			return fmt.Errorf("block '%v' at index height %v has height %v", hash, height, block.BlockHeight)
+		}
+	}
+
+	if head := indexed[chain.Height-1]; !head.Equal(chain.Head) {
+		return fmt.Errorf("chain head '%v' is not indexed at height %v, found '%v'", chain.Head, chain.Height-1, head)
+	}
+
+	return nil
+}
+
+// VerifyChain checks the integrity of every Block on the chain from the head to the Genesis Block,
+// that each Block is linked to its parent at the previous height, and the height index with VerifyHeightIndex
+func (chain *ChainManager) VerifyChain() error {
+	return chain.VerifyChainContext(context.Background())
+}
+
+// VerifyChainContext is VerifyChain with a context that stops the verification when it is done
+func (chain *ChainManager) VerifyChainContext(ctx context.Context) error {
+	height := chain.Height - 1
+
+	iter := chain.NewIterator()
+	for !iter.Done() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		block, err := iter.Next()
+		if err != nil {
+			return fmt.Errorf("chain block retrieve failed: %w", err)
+		}
+
+		if err := block.CheckIntegrity(); err != nil {
+			return fmt.Errorf("block '%v': %w", block.BlockHash, err)
+		}
+
+		if block.BlockHeight != height {
+			return fmt.Errorf("block '%v' at chain height %v has height %v", block.BlockHash, height, block.BlockHeight)
+		}
+
+		height--
+	}
+
+	if height != -1 {
+		return fmt.Errorf("chain ended at height %v before the genesis block", height+1)
+	}
+
+	return chain.VerifyHeightIndex()
+}
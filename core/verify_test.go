@@ -0,0 +1,96 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestVerifyChainContext checks that the verification of the chain stops when its context is done
+func TestVerifyChainContext(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(5))
+
+	if err := chain.VerifyChainContext(context.Background()); err != nil {
+		t.Fatalf("expected the chain to be valid, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := chain.VerifyChainContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the verification to be cancelled, got %v", err)
+	}
+}
+
+// TestVerifyHeightIndex checks that VerifyHeightIndex and VerifyChain detect a missing,
+// duplicated or out of range entry of the height index
+func TestVerifyHeightIndex(t *testing.T) {
+	chain := newTestChain(t, testChainOptions(3))
+
+	if err := chain.VerifyHeightIndex(); err != nil {
+		t.Fatalf("expected the height index to be valid, got %v", err)
+	}
+
+	first, err := chain.BlockHashAtHeight(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := chain.BlockHashAtHeight(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		corrupt func() error
+		restore func() error
+		error   string
+	}{
+		{
+			"missing",
+			func() error { return chain.db.DeleteEntry(heightKey(2)) },
+			func() error { return chain.db.SetEntry(heightKey(2), second.Bytes()) },
+			"no entry at height 2",
+		},
+		{
+			"duplicated",
+			func() error { return chain.db.SetEntry(heightKey(2), first.Bytes()) },
+			func() error { return chain.db.SetEntry(heightKey(2), second.Bytes()) },
+			"is indexed at heights",
+		},
+		{
+			"beyond chain height",
+			func() error { return chain.db.SetEntry(heightKey(chain.Height), second.Bytes()) },
+			func() error { return chain.db.DeleteEntry(heightKey(chain.Height)) },
+			"beyond chain height",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.corrupt(); err != nil {
+				t.Fatal(err)
+			}
+
+			defer func() {
+				if err := test.restore(); err != nil {
+					t.Fatal(err)
+				}
+			}()
+
+			if err := chain.VerifyHeightIndex(); err == nil || !strings.Contains(err.Error(), test.error) {
+				t.Fatalf("expected error containing %q, got %v", test.error, err)
+			}
+
+			if err := chain.VerifyChain(); err == nil || !strings.Contains(err.Error(), test.error) {
+				t.Fatalf("expected the chain verification to fail with %q, got %v", test.error, err)
+			}
+		})
+	}
+
+	if err := chain.VerifyChain(); err != nil {
+		t.Fatalf("expected the restored chain to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package core
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/anee769/essensio/db"
+)
+
+// Durability represents how chain updates are persisted to disk
+type Durability int
+
+const (
+	// DurabilitySync syncs every update of the chain to disk as it is made
+	DurabilitySync Durability = iota
+	// DurabilityBatched syncs updates of the chain to disk in batches. Each appended Block is
+	// recorded in a write-ahead log, from which any Blocks not yet flushed are recovered after a crash.
+	DurabilityBatched
+)
+
+const (
+	// DefaultFlushBlocks is the default number of Blocks between flushes in batched mode
+	DefaultFlushBlocks = 100
+	// DefaultFlushInterval is the default maximum time between flushes in batched mode
+	DefaultFlushInterval = 5 * time.Second
+)
+
+// writeAheadLog is an append-only log of the Blocks appended to the chain since the last flush
+type writeAheadLog struct {
+	file *os.File
+
+	// Represents the number of Blocks in the log
+	pending int
+	// Represents the time of the last flush
+	flushed time.Time
+}
+
+// openWAL opens the write-ahead log at the given path for appending, creating it if required
+func openWAL(path string) (*writeAheadLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("wal open failed: %w", err)
+	}
+
+	return &writeAheadLog{file: file, flushed: time.Now()}, nil
+}
+
+// append records a Block in the log and syncs it to disk
+func (wal *writeAheadLog) append(block *Block) error {
+	if err := writeFrame(wal.file, block); err != nil {
+		return fmt.Errorf("wal write failed: %w", err)
+	}
+
+	if err := wal.file.Sync(); err != nil {
+		return fmt.Errorf("wal sync failed: %w", err)
+	}
+
+	wal.pending++
+	return nil
+}
+
+// reset empties the log once its Blocks are flushed to the database
+func (wal *writeAheadLog) reset() error {
+	if err := wal.file.Truncate(0); err != nil {
+		return fmt.Errorf("wal truncate failed: %w", err)
+	}
+
+	if err := wal.file.Sync(); err != nil {
+		return fmt.Errorf("wal sync failed: %w", err)
+	}
+
+	wal.pending, wal.flushed = 0, time.Now()
+	return nil
+}
+
+// readWAL returns the Blocks recorded in the write-ahead log at the given path.
+// A Block that was only partially written when the chain crashed is ignored.
+func readWAL(path string) ([]*Block, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("wal open failed: %w", err)
+	}
+
+	defer file.Close()
+
+	var blocks []*Block
+	reader := bufio.NewReader(file)
+
+	for {
+		block, err := readFrame(reader)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return blocks, nil
+			}
+
+			return nil, fmt.Errorf("wal read failed: %w", err)
+		}
+
+		blocks = append(blocks, block)
+	}
+}
+
+// openDB opens the database of the chain for its configured Durability and write retries
+func (chain *ChainManager) openDB() (err error) {
+	if chain.opts.Durability != DurabilityBatched {
+		chain.db, err = db.Open()
+	} else {
+		chain.db, err = db.OpenBatched()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	chain.db.SetRetryPolicy(db.RetryPolicy{Retries: chain.opts.WriteRetries, Delay: chain.opts.WriteRetryDelay})
+
+	if chain.opts.Durability == DurabilityBatched {
+		chain.wal, err = openWAL(db.WALFile())
+	}
+
+	return err
+}
+
+// logBlock records a Block about to be appended to the chain in the write-ahead log, if batched
+func (chain *ChainManager) logBlock(block *Block) error {
+	if chain.wal == nil {
+		return nil
+	}
+
+	return chain.wal.append(block)
+}
+
+// maybeFlush flushes the chain to disk if the batch of the write-ahead log is full or due
+func (chain *ChainManager) maybeFlush() error {
+	if chain.wal == nil {
+		return nil
+	}
+
+	blocks, interval := chain.opts.FlushBlocks, chain.opts.FlushInterval
+	if blocks <= 0 {
+		blocks = DefaultFlushBlocks
+	}
+
+	if interval <= 0 {
+		interval = DefaultFlushInterval
+	}
+
+	if chain.wal.pending < blocks && time.Since(chain.wal.flushed) < interval {
+		return nil
+	}
+
+	return chain.Flush()
+}
+
+// Flush syncs all updates of the chain to disk and empties the write-ahead log.
+// It has no effect unless the chain is configured with DurabilityBatched.
+func (chain *ChainManager) Flush() error {
+	if chain.wal == nil {
+		return nil
+	}
+
+	if err := chain.db.Sync(); err != nil {
+		return err
+	}
+
+	return chain.wal.reset()
+}
+
+// recoverWAL appends the Blocks recorded in the write-ahead log that were not flushed before a crash.
+// The chain may hold partial updates of the unflushed Blocks, so the UTXO set, indexes and statistics
+// are first rebuilt for the chain head before the Blocks are appended again.
+func (chain *ChainManager) recoverWAL() error {
+	blocks, err := readWAL(db.WALFile())
+	if err != nil || len(blocks) == 0 {
+		return err
+	}
+
+	chain.opts.Logger.Infof("Recovering %v blocks from the write-ahead log.", len(blocks))
+
+	// Rebuild the chain state for the chain head
+	if err := chain.reindex(); err != nil {
+		return fmt.Errorf("reindex failed: %w", err)
+	}
+
+	if err := chain.recountStats(); err != nil {
+		return fmt.Errorf("statistics recount failed: %w", err)
+	}
+
+	if err := chain.syncState(); err != nil {
+		return fmt.Errorf("chain state sync failed: %w", err)
+	}
+
+	// Append the logged Blocks that are not on the chain. The log is only valid up to the first Block
+	// that cannot be appended, which can occur if the chain crashed during a reorganization.
+	recovered := 0
+	for _, block := range blocks {
+		if err := chain.AcceptBlock(block); err != nil {
+			if errors.Is(err, ErrKnownBlock) {
+				continue
+			}
+
+			chain.opts.Logger.Infof("Discarding write-ahead log from block at height %v: %v", block.BlockHeight, err)
+			break
+		}
+
+		recovered++
+	}
+
+	// Sync the recovered Blocks to disk and empty the write-ahead log
+	if chain.wal != nil {
+		if err := chain.Flush(); err != nil {
+			return err
+		}
+	} else {
+		if err := os.Remove(db.WALFile()); err != nil {
+			return fmt.Errorf("wal remove failed: %w", err)
+		}
+	}
+
+	chain.opts.Logger.Infof("Recovered %v blocks from the write-ahead log.", recovered)
+	return nil
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/db"
+)
+
+// batchedOptions returns the Options of a chain with batched durability that is never flushed by its batch
+func batchedOptions() Options {
+	opts := DefaultOptions()
+	opts.InitialBits = DifficultyToBits(8)
+	opts.Durability = DurabilityBatched
+	opts.FlushBlocks = 1000
+	opts.FlushInterval = time.Hour
+
+	return opts
+}
+
+// TestRecoverWAL checks that the Blocks appended in batched mode are recovered from the write-ahead log
+// after a crash that loses every update of the database since the last flush
+func TestRecoverWAL(t *testing.T) {
+	opts := batchedOptions()
+	resetDatabase(t)
+	t.Cleanup(func() { resetDatabase(t) })
+
+	chain, err := NewChainManager(opts)
+	if err != nil {
+		t.Fatalf("failed to open chain: %v", err)
+	}
+
+	miner := common.MinerAddress()
+	spend := signedTransaction(t, miner, []Outpoint{genesisOutpoint(t, chain)}, TxOutput{Value: BlockReward, PubKey: "alice"})
+
+	for _, txns := range []Transactions{{spend}, nil, nil} {
+		if err := chain.MineBlock(txns, ""); err != nil {
+			t.Fatalf("failed to mine block: %v", err)
+		}
+	}
+
+	head, height := chain.Head, chain.Height
+
+	logged, err := os.ReadFile(db.WALFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Crash without flushing, then lose the unflushed updates by restoring the database of the flushed Genesis Block,
+	// which is the same for every chain with the same Options
+	chain.wal.file.Close()
+	chain.db.Close()
+	resetDatabase(t)
+
+	fresh, err := NewChainManager(opts)
+	if err != nil {
+		t.Fatalf("failed to open chain: %v", err)
+	}
+
+	fresh.Stop()
+
+	// A Block that was partially written when the chain crashed is ignored
+	if err := os.WriteFile(db.WALFile(), append(logged, logged[:16]...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	chain = openTestChain(t, opts)
+
+	if !chain.Head.Equal(head) || chain.Height != height {
+		t.Fatalf("expected the chain to recover head '%v' at height %v, got '%v' at %v", head, height, chain.Head, chain.Height)
+	}
+
+	balances, err := chain.AllBalances()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if balances["alice"] != BlockReward || balances[miner] != 3*BlockReward {
+		t.Fatalf("expected the utxo set of the recovered blocks, got %v", balances)
+	}
+
+	// The recovered Blocks are flushed and the write-ahead log is emptied
+	if info, err := os.Stat(db.WALFile()); err != nil || info.Size() != 0 {
+		t.Fatalf("expected an empty write-ahead log after recovery, got %v", err)
+	}
+}
+
+// TestRecoverWALKnownBlocks checks that the Blocks of the write-ahead log that reached the database
+// before a crash are skipped by the recovery
+func TestRecoverWALKnownBlocks(t *testing.T) {
+	opts := batchedOptions()
+	resetDatabase(t)
+	t.Cleanup(func() { resetDatabase(t) })
+
+	chain, err := NewChainManager(opts)
+	if err != nil {
+		t.Fatalf("failed to open chain: %v", err)
+	}
+
+	for idx := 0; idx < 2; idx++ {
+		if err := chain.MineBlock(nil, ""); err != nil {
+			t.Fatalf("failed to mine block: %v", err)
+		}
+	}
+
+	head, height := chain.Head, chain.Height
+
+	// Crash without flushing, after the updates of the Blocks reached the database
+	chain.wal.file.Close()
+	chain.db.Close()
+
+	chain = openTestChain(t, opts)
+
+	if !chain.Head.Equal(head) || chain.Height != height {
+		t.Fatalf("expected the chain to keep head '%v' at height %v, got '%v' at %v", head, height, chain.Head, chain.Height)
+	}
+
+	audit, err := chain.AuditUTXO()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !audit.Consistent() {
+		t.Fatalf("expected the utxo set to be consistent after recovery")
+	}
+}
@@ -0,0 +1,25 @@
+package core
+
+import "github.com/anee769/essensio/common"
+
+// Wallet represents the signing authority for an Address.
+// Placeholder for a key pair, signatures are currently the Address of the Wallet and the signature hash.
+type Wallet struct {
+	Address common.Address
+}
+
+// NewWallet returns a new Wallet for the given Address
+func NewWallet(address common.Address) *Wallet {
+	return &Wallet{address}
+}
+
+// Sign returns the signature of the Wallet for the input of a Transaction at the given index, which commits
+// to the parts of the Transaction selected by the SigHashType. Returns an error if the signature hash fails.
+func (wallet *Wallet) Sign(txn *Transaction, idx int, flag SigHashType) (common.Address, error) {
+	hash, err := txn.SignatureHash(idx, flag)
+	if err != nil {
+		return common.NullAddress(), err
+	}
+
+	return encodeSignature(wallet.Address, hash, flag), nil
+}
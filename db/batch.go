@@ -0,0 +1,36 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNestedBatch is returned by Batch when it is called within another Batch
+var ErrNestedBatch = errors.New("batch already in progress")
+
+// Batch runs fn with every write to the database collected into a single transaction, which is committed
+// when fn returns nil and discarded otherwise. Either all of the writes of fn are applied or none of them
+// are, even if the process crashes while fn runs. Reads within fn observe the writes of the Batch.
+// The database must not be used concurrently while a Batch runs, and the writes of a Batch must
+// fit within a single transaction of the database.
+func (db *Database) Batch(fn func() error) error {
+	if db.batch != nil {
+		return ErrNestedBatch
+	}
+
+	db.batch = db.client.NewTransaction(true)
+	defer func() {
+		db.batch.Discard()
+		db.batch = nil
+	}()
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	if err := db.batch.Commit(); err != nil {
+		return fmt.Errorf("db batch commit fail: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+// TestBatch checks that the writes of a Batch are observed within it and committed together,
+// that a failed Batch discards all of its writes and that Batches cannot be nested
+func TestBatch(t *testing.T) {
+	if err := os.RemoveAll(Dir()); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { os.RemoveAll(Dir()) })
+
+	database, err := Open()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	defer database.Close()
+
+	failure := errors.New("failure")
+	if err := database.Batch(func() error {
+		if err := database.SetEntry([]byte("discarded"), []byte("value")); err != nil {
+			return err
+		}
+
+		return failure
+	}); !errors.Is(err, failure) {
+		t.Fatalf("expected the batch to fail, got %v", err)
+	}
+
+	if exists, err := database.HasEntry([]byte("discarded")); err != nil || exists {
+		t.Fatalf("expected the writes of the failed batch to be discarded, got %v", err)
+	}
+
+	if err := database.Batch(func() error {
+		for _, key := range []string{"first", "second"} {
+			if err := database.SetEntry([]byte(key), []byte(key)); err != nil {
+				return err
+			}
+		}
+
+		if value, err := database.GetEntry([]byte("first")); err != nil || string(value) != "first" {
+			t.Errorf("expected the batch to observe its writes, got %q and %v", value, err)
+		}
+
+		if err := database.Batch(func() error { return nil }); !errors.Is(err, ErrNestedBatch) {
+			t.Errorf("expected a nested batch to be rejected, got %v", err)
+		}
+
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to commit batch: %v", err)
+	}
+
+	for _, key := range []string{"first", "second"} {
+		if value, err := database.GetEntry([]byte(key)); err != nil || string(value) != key {
+			t.Fatalf("expected the writes of the batch to be committed, got %q and %v", value, err)
+		}
+	}
+}
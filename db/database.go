@@ -1,6 +1,7 @@
 package db
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/dgraph-io/badger"
@@ -8,46 +9,83 @@ import (
 
 type Database struct {
 	client *badger.DB
+	lock   *dirLock
+	retry  RetryPolicy
+
+	// Represents the transaction of the running Batch, which is nil outside of a Batch
+	batch *badger.Txn
 }
 
-// Open opens a Badger client to the database at Dir()
+// Open opens a Badger client to the database at Dir().
+// Every write is synced to disk before it returns.
 func Open() (*Database, error) {
+	return open(true)
+}
+
+// OpenBatched opens a Badger client to the database at Dir() that does not sync each write to disk.
+// Writes are only guaranteed to be durable after a call to Sync.
+func OpenBatched() (*Database, error) {
+	return open(false)
+}
+
+// open opens a Badger client to the database at Dir() with the given write sync mode.
+// The database directory is locked while it is open, so it cannot be opened by another process.
+func open(syncWrites bool) (*Database, error) {
+	// Lock the database directory
+	lock, err := acquireLock()
+	if err != nil {
+		return nil, err
+	}
+
 	// Setup Badger Options
 	opts := badger.DefaultOptions(Dir())
 	opts.Logger = nil
+	opts.SyncWrites = syncWrites
 
 	// Open Badger Client
 	client, err := badger.Open(opts)
 	if err != nil {
+		lock.release()
 		return nil, fmt.Errorf("db open fail: %w", err)
 	}
 
 	// Wrap client inside Database and return
-	return &Database{client}, nil
+	return &Database{client: client, lock: lock}, nil
 }
 
-// Close closes the Badger client to the database at Dir()
+// Close closes the Badger client to the database at Dir() and releases the lock on its directory
 func (db *Database) Close() {
 	if err := db.client.Close(); err != nil {
 		panic(fmt.Errorf("db close fail: %w", err))
 	}
+
+	if err := db.lock.release(); err != nil {
+		panic(fmt.Errorf("db lock release fail: %w", err))
+	}
+}
+
+// Sync flushes all writes to the database to disk
+func (db *Database) Sync() error {
+	if err := db.client.Sync(); err != nil {
+		return fmt.Errorf("db sync fail: %w", err)
+	}
+
+	return nil
 }
 
+// GetEntry returns the value stored at the given key
 func (db *Database) GetEntry(key []byte) (value []byte, err error) {
 	// Define a view transaction on the database
-	err = db.client.View(func(txn *badger.Txn) error {
+	err = db.view(func(txn *badger.Txn) error {
 		// Attempt to get the Item for the given key
 		item, err := txn.Get(key)
 		if err != nil {
 			return fmt.Errorf("db get on key '%x' fail: %w", key, err)
 		}
 
-		// Retrieve the value from the Item
-		if err = item.Value(func(val []byte) error {
-			value = val
-			return nil
-
-		}); err != nil {
+		// Retrieve a copy of the value from the Item.
+		// The value is only valid for the lifetime of the transaction.
+		if value, err = item.ValueCopy(nil); err != nil {
 			return fmt.Errorf("db value get on key '%x' fail: %w", key, err)
 		}
 
@@ -57,9 +95,11 @@ func (db *Database) GetEntry(key []byte) (value []byte, err error) {
 	return
 }
 
+// SetEntry sets the value at the given key.
+// Transient failures are retried with the RetryPolicy of the database.
 func (db *Database) SetEntry(key, value []byte) error {
 	// Define an update transaction the database
-	return db.client.Update(func(txn *badger.Txn) error {
+	return db.update(func(txn *badger.Txn) error {
 		// Attempt to set the key-value pair to the database
 		if err := txn.Set(key, value); err != nil {
 			return fmt.Errorf("db set for key '%x' failed: %w", key, err)
@@ -68,3 +108,108 @@ func (db *Database) SetEntry(key, value []byte) error {
 		return nil
 	})
 }
+
+// DeleteEntry removes the value at the given key.
+// Transient failures are retried with the RetryPolicy of the database.
+func (db *Database) DeleteEntry(key []byte) error {
+	// Define an update transaction the database
+	return db.update(func(txn *badger.Txn) error {
+		// Attempt to delete the key from the database
+		if err := txn.Delete(key); err != nil {
+			return fmt.Errorf("db delete for key '%x' failed: %w", key, err)
+		}
+
+		return nil
+	})
+}
+
+// update runs an update transaction on the database, retried with the RetryPolicy of the database.
+// Within a Batch, fn runs on the transaction of the Batch instead, and is never retried.
+func (db *Database) update(fn func(txn *badger.Txn) error) error {
+	if db.batch != nil {
+		return fn(db.batch)
+	}
+
+	return db.retry.Retry(func() error {
+		return db.client.Update(fn)
+	})
+}
+
+// view runs a view transaction on the database.
+// Within a Batch, fn runs on the transaction of the Batch instead, so that it observes the writes of the Batch.
+func (db *Database) view(fn func(txn *badger.Txn) error) error {
+	if db.batch != nil {
+		return fn(db.batch)
+	}
+
+	return db.client.View(fn)
+}
+
+// HasEntry returns whether a value exists at the given key
+func (db *Database) HasEntry(key []byte) (exists bool, err error) {
+	// Define a view transaction on the database
+	err = db.view(func(txn *badger.Txn) error {
+		// Attempt to get the Item for the given key
+		_, err := txn.Get(key)
+		switch {
+		case err == nil:
+			exists = true
+		case errors.Is(err, badger.ErrKeyNotFound):
+			exists = false
+		default:
+			return fmt.Errorf("db get on key '%x' fail: %w", key, err)
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// IteratePrefix calls fn for each key-value pair in the database whose key begins with the given prefix.
+// Iteration stops early if fn returns an error, which is returned.
+func (db *Database) IteratePrefix(prefix []byte, fn func(key, value []byte) error) error {
+	// Define a view transaction on the database
+	return db.view(func(txn *badger.Txn) error {
+		iter := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer iter.Close()
+
+		for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+			item := iter.Item()
+
+			// Copy the key and value out of the Item
+			key := item.KeyCopy(nil)
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return fmt.Errorf("db value get on key '%x' fail: %w", key, err)
+			}
+
+			if err := fn(key, value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// CountPrefix returns the number of keys in the database that begin with the given prefix.
+// Only the keys are iterated, so the values of the entries are never read.
+func (db *Database) CountPrefix(prefix []byte) (count int, err error) {
+	// Define a view transaction on the database
+	err = db.view(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+
+		iter := txn.NewIterator(opts)
+		defer iter.Close()
+
+		for iter.Seek(prefix); iter.ValidForPrefix(prefix); iter.Next() {
+			count++
+		}
+
+		return nil
+	})
+
+	return
+}
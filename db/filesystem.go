@@ -9,7 +9,9 @@ import (
 
 const dbFolder = "data"
 
-// Exists returns a boolean indicating if the database directory is already initialized
+// Exists returns a boolean indicating if the database directory is already initialized.
+// A database that exists holds no data if its initialization was interrupted, so users
+// of the database must check its contents to decide whether it needs to be initialized.
 func Exists() bool {
 	// Create path to MANIFEST file in database directory.
 	// This MANIFEST file is good indication of whether the database is initialized
@@ -38,3 +40,8 @@ func Dir() string {
 	// Add dbFolder to return directory of database
 	return filepath.Join(execDir, dbFolder)
 }
+
+// WALFile returns the path of the write-ahead log of the database, which is alongside Dir()
+func WALFile() string {
+	return Dir() + ".wal"
+}
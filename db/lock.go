@@ -0,0 +1,17 @@
+package db
+
+import (
+	"errors"
+	"path/filepath"
+)
+
+// lockFile is the name of the lock file in the database directory
+const lockFile = "essensio.lock"
+
+// ErrInUse is returned when opening a database that is already open in another process
+var ErrInUse = errors.New("database already in use")
+
+// lockPath returns the path of the lock file of the database
+func lockPath() string {
+	return filepath.Join(Dir(), lockFile)
+}
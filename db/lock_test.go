@@ -0,0 +1,64 @@
+package db
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+// TestOpenLocked checks that the database cannot be opened again while it is open,
+// and that it can be reopened once it is closed
+func TestOpenLocked(t *testing.T) {
+	if err := os.RemoveAll(Dir()); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { os.RemoveAll(Dir()) })
+
+	database, err := Open()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	if _, err := Open(); !errors.Is(err, ErrInUse) {
+		t.Fatalf("expected the open database to be in use, got %v", err)
+	}
+
+	database.Close()
+
+	database, err = Open()
+	if err != nil {
+		t.Fatalf("failed to reopen closed database: %v", err)
+	}
+
+	database.Close()
+}
+
+// TestOpenStaleLock checks that a lock file left behind by a process that exited without releasing
+// the lock does not prevent the database from being opened
+func TestOpenStaleLock(t *testing.T) {
+	if err := os.RemoveAll(Dir()); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { os.RemoveAll(Dir()) })
+
+	if err := os.MkdirAll(Dir(), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(lockPath(), []byte("99999\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	database, err := Open()
+	if err != nil {
+		t.Fatalf("expected a stale lock file not to prevent opening the database, got %v", err)
+	}
+
+	if _, err := Open(); !errors.Is(err, ErrInUse) {
+		t.Fatalf("expected the open database to be in use, got %v", err)
+	}
+
+	database.Close()
+}
@@ -0,0 +1,56 @@
+//go:build !windows
+
+package db
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// dirLock is an exclusive lock on the database directory, held with flock on the lock file.
+// The lock is released by the operating system if the process exits without releasing it.
+type dirLock struct {
+	file *os.File
+}
+
+// acquireLock acquires the exclusive lock on the database directory, creating the directory if required.
+// Returns an error wrapping ErrInUse if the lock is held by another process.
+func acquireLock() (*dirLock, error) {
+	if err := os.MkdirAll(Dir(), 0o755); err != nil {
+		return nil, fmt.Errorf("db directory create fail: %w", err)
+	}
+
+	file, err := os.OpenFile(lockPath(), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("db lock file open fail: %w", err)
+	}
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, fmt.Errorf("%w: %v is locked by another process", ErrInUse, Dir())
+		}
+
+		return nil, fmt.Errorf("db lock fail: %w", err)
+	}
+
+	// Record the process holding the lock for diagnostics
+	if err := file.Truncate(0); err == nil {
+		fmt.Fprintf(file, "%v\n", os.Getpid())
+	}
+
+	return &dirLock{file}, nil
+}
+
+// release releases the lock on the database directory
+func (lock *dirLock) release() error {
+	if err := syscall.Flock(int(lock.file.Fd()), syscall.LOCK_UN); err != nil {
+		lock.file.Close()
+		return fmt.Errorf("db unlock fail: %w", err)
+	}
+
+	return lock.file.Close()
+}
@@ -0,0 +1,96 @@
+//go:build windows
+
+package db
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = kernel32.NewProc("LockFileEx")
+	procUnlockFileEx = kernel32.NewProc("UnlockFileEx")
+)
+
+const (
+	// Flags of LockFileEx for an exclusive lock that fails instead of waiting
+	lockfileFailImmediately = 0x1
+	lockfileExclusiveLock   = 0x2
+
+	// errorLockViolation is returned by LockFileEx if the lock is held by another handle
+	errorLockViolation syscall.Errno = 33
+)
+
+// dirLock is an exclusive lock on the database directory, held with LockFileEx on the lock file.
+// The lock is released by the operating system if the process exits without releasing it.
+type dirLock struct {
+	file *os.File
+}
+
+// acquireLock acquires the exclusive lock on the database directory, creating the directory if required.
+// Returns an error wrapping ErrInUse if the lock is held by another process.
+func acquireLock() (*dirLock, error) {
+	if err := os.MkdirAll(Dir(), 0o755); err != nil {
+		return nil, fmt.Errorf("db directory create fail: %w", err)
+	}
+
+	file, err := os.OpenFile(lockPath(), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("db lock file open fail: %w", err)
+	}
+
+	if err := lockFileEx(file, lockfileExclusiveLock|lockfileFailImmediately); err != nil {
+		file.Close()
+
+		if errors.Is(err, errorLockViolation) {
+			return nil, fmt.Errorf("%w: %v is locked by another process", ErrInUse, Dir())
+		}
+
+		return nil, fmt.Errorf("db lock fail: %w", err)
+	}
+
+	// Record the process holding the lock for diagnostics
+	if err := file.Truncate(0); err == nil {
+		fmt.Fprintf(file, "%v\n", os.Getpid())
+	}
+
+	return &dirLock{file}, nil
+}
+
+// release releases the lock on the database directory
+func (lock *dirLock) release() error {
+	if err := unlockFileEx(lock.file); err != nil {
+		lock.file.Close()
+		return fmt.Errorf("db unlock fail: %w", err)
+	}
+
+	return lock.file.Close()
+}
+
+// lockFileEx locks the first byte of a file with LockFileEx and the given flags
+func lockFileEx(file *os.File, flags uint32) error {
+	var overlapped syscall.Overlapped
+
+	result, _, err := procLockFileEx.Call(file.Fd(), uintptr(flags), 0, 1, 0, uintptr(unsafe.Pointer(&overlapped)))
+	if result == 0 {
+		return err
+	}
+
+	return nil
+}
+
+// unlockFileEx unlocks the first byte of a file locked by lockFileEx with UnlockFileEx
+func unlockFileEx(file *os.File) error {
+	var overlapped syscall.Overlapped
+
+	result, _, err := procUnlockFileEx.Call(file.Fd(), 0, 1, 0, uintptr(unsafe.Pointer(&overlapped)))
+	if result == 0 {
+		return err
+	}
+
+	return nil
+}
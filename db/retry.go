@@ -0,0 +1,48 @@
+package db
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dgraph-io/badger"
+)
+
+// RetryPolicy is the policy for retrying database writes that fail with a transient error
+type RetryPolicy struct {
+	// Retries is the maximum number of times a failed write is retried. Writes are not retried if it is 0.
+	Retries int
+	// Delay is the delay before the first retry, which doubles with every subsequent retry
+	Delay time.Duration
+}
+
+// IsTransient returns whether a write error is transient, such that the write may succeed if it is retried.
+// Transaction conflicts and errors that report themselves as temporary are transient.
+func IsTransient(err error) bool {
+	if errors.Is(err, badger.ErrConflict) {
+		return true
+	}
+
+	var temporary interface{ Temporary() bool }
+	return errors.As(err, &temporary) && temporary.Temporary()
+}
+
+// Retry calls write until it succeeds, fails with an error that is not transient or runs out of retries.
+// Returns the error of the last call to write.
+func (policy RetryPolicy) Retry(write func() error) error {
+	delay := policy.Delay
+
+	for attempt := 0; ; attempt++ {
+		err := write()
+		if err == nil || attempt >= policy.Retries || !IsTransient(err) {
+			return err
+		}
+
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// SetRetryPolicy sets the RetryPolicy for the writes to the database
+func (db *Database) SetRetryPolicy(policy RetryPolicy) {
+	db.retry = policy
+}
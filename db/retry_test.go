@@ -0,0 +1,95 @@
+package db
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger"
+)
+
+// failingWrite returns a write that fails with err for the given number of calls before it succeeds,
+// and counts its calls into calls
+func failingWrite(failures int, err error, calls *int) func() error {
+	return func() error {
+		if *calls++; *calls <= failures {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// TestRetry checks that transient failures are retried with the RetryPolicy until the write succeeds or the retries
+// run out, and that permanent failures are not retried
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{Retries: 3, Delay: time.Millisecond}
+	permanent := errors.New("permanent failure")
+
+	tests := []struct {
+		name     string
+		failures int
+		err      error
+		calls    int
+		fails    bool
+	}{
+		{"transient", 2, badger.ErrConflict, 3, false},
+		{"exhausted", 5, badger.ErrConflict, 4, true},
+		{"permanent", 2, permanent, 1, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := 0
+			err := policy.Retry(failingWrite(test.failures, test.err, &calls))
+
+			if calls != test.calls {
+				t.Fatalf("expected %v calls, got %v", test.calls, calls)
+			}
+
+			if (err != nil) != test.fails || (test.fails && !errors.Is(err, test.err)) {
+				t.Fatalf("expected the write to fail: %v, got %v", test.fails, err)
+			}
+		})
+	}
+
+	calls := 0
+	if err := (RetryPolicy{}).Retry(failingWrite(1, badger.ErrConflict, &calls)); err == nil || calls != 1 {
+		t.Fatalf("expected no retries without a policy, got %v calls", calls)
+	}
+}
+
+// TestUpdateRetry checks that an update of the database that fails twice with a transient error is committed
+// once it succeeds on a retry
+func TestUpdateRetry(t *testing.T) {
+	if err := os.RemoveAll(Dir()); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { os.RemoveAll(Dir()) })
+
+	database, err := Open()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	defer database.Close()
+	database.SetRetryPolicy(RetryPolicy{Retries: 2, Delay: time.Millisecond})
+
+	calls := 0
+	if err := database.update(func(txn *badger.Txn) error {
+		if calls++; calls <= 2 {
+			return badger.ErrConflict
+		}
+
+		return txn.Set([]byte("key"), []byte("value"))
+	}); err != nil {
+		t.Fatalf("expected the update to succeed on a retry, got %v", err)
+	}
+
+	value, err := database.GetEntry([]byte("key"))
+	if err != nil || string(value) != "value" {
+		t.Fatalf("expected the retried update to be committed, got %q and %v", value, err)
+	}
+}
@@ -16,7 +16,76 @@ type AddBlockArgs struct {
 type TransactionInput struct {
 	To    string `json:"to"`
 	From  string `json:"from"`
-	Value int    `json:"value"`
+	Value Value  `json:"value"`
+
+	// Address that receives the change, defaults to the sender
+	Change string `json:"change,omitempty"`
+	// Minimum confirmations of the spent outputs, defaults to any
+	MinConfirmations int64 `json:"min_confirmations,omitempty"`
+	// Maximum number of additional small outputs of the sender swept into the change, defaults to none
+	Consolidate int `json:"consolidate,omitempty"`
+
+	// Minimum block height and median time past at which the paid output can be spent, defaults to unlocked
+	LockHeight int64 `json:"lock_height,omitempty"`
+	LockTime   int64 `json:"lock_time,omitempty"`
+}
+
+// validate checks that the addresses of the TransactionInput are valid for the chain
+func (input TransactionInput) validate(chain *core.ChainManager) error {
+	if err := chain.ValidateAddress(common.NormalizeAddress(input.To)); err != nil {
+		return fmt.Errorf("invalid recipient: %w", err)
+	}
+
+	if err := chain.ValidateAddress(common.NormalizeAddress(input.From)); err != nil {
+		return fmt.Errorf("invalid sender: %w", err)
+	}
+
+	// The change address is optional and defaults to the sender
+	if input.Change != "" {
+		if err := chain.ValidateAddress(common.NormalizeAddress(input.Change)); err != nil {
+			return fmt.Errorf("invalid change address: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// outputs returns the outputs paid by the TransactionInput, excluding any change
+func (input TransactionInput) outputs() []core.TxOutput {
+	return []core.TxOutput{{
+		Value:  input.Value.Int(),
+		PubKey: common.NormalizeAddress(input.To),
+		Lock:   core.OutputLock{Height: input.LockHeight, Time: input.LockTime},
+	}}
+}
+
+// buildOptions returns the core.BuildOptions for the TransactionInput
+func (input TransactionInput) buildOptions() core.BuildOptions {
+	return core.BuildOptions{
+		Change:           common.NormalizeAddress(input.Change),
+		MinConfirmations: input.MinConfirmations,
+		Consolidate:      input.Consolidate,
+	}
+}
+
+// newTransaction builds and signs the Transaction for a TransactionInput
+func (api *API) newTransaction(input TransactionInput) (*core.Transaction, error) {
+	if err := input.validate(api.chain); err != nil {
+		return nil, err
+	}
+
+	from := common.NormalizeAddress(input.From)
+
+	txn, err := core.BuildUnsignedTransaction(from, input.outputs(), api.chain, input.buildOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to build transaction: %w", err)
+	}
+
+	if err := core.SignTransaction(txn, core.NewWallet(from)); err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	return txn, nil
 }
 
 type AddBlockResult struct {
@@ -32,17 +101,30 @@ func (api *API) AddBlock(r *http.Request, args *AddBlockArgs, result *AddBlockRe
 	}
 
 	transactions := make(core.Transactions, 0, len(args.Transactions))
-	for _, txn := range args.Transactions {
-		newtxn := core.NewTransaction(common.Address(txn.From), common.Address(txn.To), txn.Value, api.chain)
+	for idx, txn := range args.Transactions {
+		newtxn, err := api.newTransaction(txn)
+		if err != nil {
+			return fmt.Errorf("transaction %v: %w", idx, err)
+		}
+
 		transactions = append(transactions, newtxn)
 	}
 
-	if err := api.chain.AddBlock(transactions); err != nil {
+	if err := checkContext(r); err != nil {
+		return err
+	}
+
+	if err := api.chain.MineBlock(transactions, ""); err != nil {
 		return fmt.Errorf("failed to add block: %w", err)
 	}
 
+	height, err := api.headHeight()
+	if err != nil {
+		return err
+	}
+
 	*result = AddBlockResult{
-		BlockHeight: uint64(api.chain.Height - 1),
+		BlockHeight: height,
 		BlockHash:   api.chain.Head.Hex(),
 	}
 
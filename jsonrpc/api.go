@@ -1,6 +1,7 @@
 package jsonrpc
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/anee769/essensio/core"
@@ -8,15 +9,30 @@ import (
 
 type API struct {
 	chain *core.ChainManager
+
+	// Represents the maximum number of Blocks in a response, which is not limited if 0
+	maxResponseBlocks int64
+	// Represents the peers of the node, managed with the peer methods
+	peers *PeerSet
 }
 
-func NewAPI() *API {
-	chain, err := core.NewChainManager()
+// NewAPI returns a new API for a chain configured with the given Options
+func NewAPI(opts core.Options) *API {
+	chain, err := core.NewChainManager(opts)
 	if err != nil {
 		log.Fatalln("Failed to Start Blockchain:", err)
 	}
 
-	return &API{chain}
+	return &API{chain: chain, peers: NewPeerSet()}
+}
+
+// checkResponseBlocks returns an error if a response of the given number of Blocks exceeds the maximum response size
+func (api *API) checkResponseBlocks(count int64) error {
+	if api.maxResponseBlocks > 0 && count > api.maxResponseBlocks {
+		return fmt.Errorf("response of %v blocks exceeds the maximum of %v blocks: request fewer blocks with 'from_height' and 'limit'", count, api.maxResponseBlocks)
+	}
+
+	return nil
 }
 
 func (api *API) Stop() {
@@ -0,0 +1,90 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/anee769/essensio/core"
+)
+
+type AuditUTXOArgs struct{}
+
+type UTXOEntry struct {
+	Value    Value  `json:"value"`
+	PubKey   string `json:"pubkey"`
+	Height   uint64 `json:"height"`
+	Coinbase bool   `json:"coinbase"`
+}
+
+type UTXODiscrepancy struct {
+	Kind string `json:"kind"`
+	// Outpoint of the discrepancy, omitted for a corrupt entry with an invalid key
+	Outpoint string `json:"outpoint,omitempty"`
+	// Entry replayed from the chain, omitted if the output is not unspent
+	Expected *UTXOEntry `json:"expected,omitempty"`
+	// Entry in the UTXO set, omitted if the output is absent or corrupt
+	Actual *UTXOEntry `json:"actual,omitempty"`
+}
+
+type AuditUTXOResult struct {
+	Height        uint64            `json:"height"`
+	Head          string            `json:"head"`
+	Checked       int               `json:"checked"`
+	Consistent    bool              `json:"consistent"`
+	Discrepancies []UTXODiscrepancy `json:"discrepancies"`
+}
+
+// AuditUTXO compares the UTXO set against a full scan of the chain and reports any discrepancies.
+// It is expensive and blocks new blocks while it runs, so it requires the auth token of the server.
+func (api *API) AuditUTXO(r *http.Request, args *AuditUTXOArgs, result *AuditUTXOResult) error {
+	log.Println("'AuditUTXO' Called")
+
+	audit, err := api.chain.AuditUTXOContext(r.Context())
+	if err != nil {
+		if ctxErr := checkContext(r); ctxErr != nil {
+			return ctxErr
+		}
+
+		return fmt.Errorf("failed to audit utxo set: %w", err)
+	}
+
+	discrepancies := make([]UTXODiscrepancy, 0, len(audit.Discrepancies))
+	for _, discrepancy := range audit.Discrepancies {
+		converted := UTXODiscrepancy{
+			Kind:     discrepancy.Kind,
+			Expected: newUTXOEntry(discrepancy.Expected),
+			Actual:   newUTXOEntry(discrepancy.Actual),
+		}
+
+		if discrepancy.Outpoint != (core.Outpoint{}) {
+			converted.Outpoint = discrepancy.Outpoint.String()
+		}
+
+		discrepancies = append(discrepancies, converted)
+	}
+
+	*result = AuditUTXOResult{
+		Height:        toUint64(audit.Height),
+		Head:          audit.Head.Hex(),
+		Checked:       audit.Checked,
+		Consistent:    audit.Consistent(),
+		Discrepancies: discrepancies,
+	}
+
+	return nil
+}
+
+// newUTXOEntry converts a core.UTXOEntry into a UTXOEntry, which is nil for a nil entry
+func newUTXOEntry(entry *core.UTXOEntry) *UTXOEntry {
+	if entry == nil {
+		return nil
+	}
+
+	return &UTXOEntry{
+		Value:    NewValue(entry.Output.Value),
+		PubKey:   string(entry.Output.PubKey),
+		Height:   toUint64(entry.Height),
+		Coinbase: entry.Coinbase,
+	}
+}
@@ -0,0 +1,43 @@
+package jsonrpc
+
+import (
+	"net/http"
+	"testing"
+)
+
+// TestAuditUTXO checks that AuditUTXO reports a consistent UTXO set for the chain head,
+// and that it is only served to requests with the auth token of the server
+func TestAuditUTXO(t *testing.T) {
+	api := newTestAPI(t, 2)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	var result AuditUTXOResult
+	callResult(t, router, "API.AuditUTXO", AuditUTXOArgs{}, &result)
+
+	if !result.Consistent || len(result.Discrepancies) != 0 || result.Checked == 0 {
+		t.Fatalf("expected a consistent audit, got %+v", result)
+	}
+
+	if result.Height != uint64(api.chain.Height) || result.Head != api.chain.Head.Hex() {
+		t.Fatalf("expected an audit of the chain head, got height %v and head '%v'", result.Height, result.Head)
+	}
+
+	body := `{"method":"API.AuditUTXO","params":[{}],"id":1}`
+	for _, headers := range []map[string]string{
+		{"Content-Type": "application/json"},
+		{"Content-Type": "application/json", "Authorization": "Bearer wrong-token"},
+	} {
+		if response := serve(router, http.MethodPost, DefaultPath, body, headers); response.Code != http.StatusUnauthorized {
+			t.Fatalf("expected an unauthorized audit to be rejected, got status %v", response.Code)
+		}
+	}
+
+	disabled, err := NewRouter(api, DefaultServerConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response := serve(disabled, http.MethodPost, DefaultPath, body, map[string]string{"Content-Type": "application/json"}); response.Code != http.StatusForbidden {
+		t.Fatalf("expected the audit to be disabled without an auth token, got status %v", response.Code)
+	}
+}
@@ -0,0 +1,58 @@
+package jsonrpc
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// AuthMethods is the set of JSON-RPC methods that require the ServerConfig.AuthToken.
+// They are expensive or operational methods that should not be exposed to every client.
+var AuthMethods = map[string]bool{
+	"API.AuditUTXO":     true,
+	"API.GetDebugState": true,
+	"API.AddPeer":       true,
+	"API.RemovePeer":    true,
+}
+
+// withAuth wraps an http.Handler with bearer token authentication of the AuthMethods.
+// Requests for the AuthMethods must carry the header "Authorization: Bearer <token>",
+// and are rejected if the token is empty, which disables the AuthMethods entirely.
+// Requests for all other methods are served without authentication.
+func withAuth(handler http.Handler, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, err := peekMethod(r)
+		if err != nil {
+			http.Error(w, "rpc: failed to read request body", http.StatusBadRequest)
+			return
+		}
+
+		if !AuthMethods[method] {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		if token == "" {
+			http.Error(w, "rpc: method '"+method+"' is disabled without an auth token", http.StatusForbidden)
+			return
+		}
+
+		given, bearer := cutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !bearer || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "rpc: method '"+method+"' requires authorization", http.StatusUnauthorized)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// cutPrefix returns the string without the prefix and whether the string has the prefix
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+
+	return s[len(prefix):], true
+}
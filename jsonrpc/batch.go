@@ -0,0 +1,138 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxBatchSize is the default maximum number of requests in a batch
+const DefaultMaxBatchSize = 100
+
+// batchResponse represents the response to a request of a batch that was rejected before reaching
+// the JSON-RPC server, such as by authentication, in the response format of the JSON codec
+type batchResponse struct {
+	Result any             `json:"result"`
+	Error  string          `json:"error"`
+	ID     json.RawMessage `json:"id"`
+}
+
+// batchRecorder is an http.ResponseWriter that records the response to a single request of a batch
+type batchRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// Header returns the header of the recorded response
+func (rec *batchRecorder) Header() http.Header {
+	return rec.header
+}
+
+// WriteHeader records the status of the response, if it has not already been written
+func (rec *batchRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+// Write records data of the response body
+func (rec *batchRecorder) Write(data []byte) (int, error) {
+	rec.WriteHeader(http.StatusOK)
+	return rec.body.Write(data)
+}
+
+// withBatch wraps an http.Handler with support for batch requests, which are JSON arrays of requests.
+// Each request of a batch is served by the handler in order, as if it were sent alone with the headers of
+// the batch, so that authentication and timeouts apply to each of them. The responses are returned as a
+// JSON array in the same order, with an error response for each request rejected before the server.
+// Batches are rejected if they are empty or exceed the limit, and are disabled if the limit is 0.
+func withBatch(handler http.Handler, limit int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "rpc: failed to read request body", http.StatusBadRequest)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Requests that are not batches are served directly
+		if trimmed := bytes.TrimSpace(body); len(trimmed) == 0 || trimmed[0] != '[' {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		if limit <= 0 {
+			http.Error(w, "rpc: batch requests are disabled", http.StatusBadRequest)
+			return
+		}
+
+		var requests []json.RawMessage
+		if err := json.Unmarshal(body, &requests); err != nil {
+			http.Error(w, "rpc: malformed batch request", http.StatusBadRequest)
+			return
+		}
+
+		if len(requests) == 0 {
+			http.Error(w, "rpc: empty batch request", http.StatusBadRequest)
+			return
+		}
+
+		if len(requests) > limit {
+			http.Error(w, fmt.Sprintf("rpc: batch of %v requests exceeds the limit of %v", len(requests), limit), http.StatusBadRequest)
+			return
+		}
+
+		responses := make([]json.RawMessage, 0, len(requests))
+		for _, request := range requests {
+			responses = append(responses, serveBatched(handler, r, request))
+		}
+
+		data, err := json.Marshal(responses)
+		if err != nil {
+			http.Error(w, "rpc: failed to encode batch response", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write(data)
+	})
+}
+
+// serveBatched serves a single request of a batch with the handler and returns its response.
+// A request that is not answered with a JSON response is answered with an error response carrying its ID.
+func serveBatched(handler http.Handler, batch *http.Request, request json.RawMessage) json.RawMessage {
+	sub := batch.Clone(batch.Context())
+	sub.Body = io.NopCloser(bytes.NewReader(request))
+	sub.ContentLength = int64(len(request))
+
+	rec := &batchRecorder{header: make(http.Header)}
+	handler.ServeHTTP(rec, sub)
+
+	response := bytes.TrimSpace(rec.body.Bytes())
+	if rec.status == http.StatusOK && json.Valid(response) {
+		return response
+	}
+
+	var envelope struct {
+		ID json.RawMessage `json:"id"`
+	}
+
+	_ = json.Unmarshal(request, &envelope)
+
+	message := strings.TrimSpace(rec.body.String())
+	if message == "" {
+		message = fmt.Sprintf("rpc: request failed with status %v", rec.status)
+	}
+
+	data, err := json.Marshal(batchResponse{Error: message, ID: envelope.ID})
+	if err != nil {
+		return json.RawMessage("null")
+	}
+
+	return data
+}
@@ -0,0 +1,104 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// batchedResponse is the response to a single request of a batch
+type batchedResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *string         `json:"error"`
+	ID     int             `json:"id"`
+}
+
+// TestBatchRequest checks that a batch of GetChainInfo and GetBalance requests returns both results in order,
+// and that a request of the batch rejected by authentication fails alone with an error response carrying its ID
+func TestBatchRequest(t *testing.T) {
+	api := newTestAPI(t, 2)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	body := `[
+		{"method":"API.GetChainInfo","params":[{}],"id":1},
+		{"method":"API.GetBalance","params":[{"address":"` + string(common.MinerAddress()) + `"}],"id":2},
+		{"method":"API.AuditUTXO","params":[{}],"id":3}
+	]`
+
+	response := serve(router, http.MethodPost, DefaultPath, body, map[string]string{"Content-Type": "application/json"})
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected the batch to be served, got status %v", response.Code)
+	}
+
+	var responses []batchedResponse
+	if err := json.Unmarshal(response.Body.Bytes(), &responses); err != nil {
+		t.Fatalf("expected a json array of responses, got %s", response.Body)
+	}
+
+	if len(responses) != 3 || responses[0].ID != 1 || responses[1].ID != 2 || responses[2].ID != 3 {
+		t.Fatalf("expected 3 responses in the order of the batch, got %s", response.Body)
+	}
+
+	var info GetChainInfoResult
+	if err := json.Unmarshal(responses[0].Result, &info); err != nil || info.Head != api.chain.Head.Hex() {
+		t.Fatalf("expected the chain info of the chain head, got %s", responses[0].Result)
+	}
+
+	balances, err := api.chain.AllBalances()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var balance GetBalanceResult
+	if err := json.Unmarshal(responses[1].Result, &balance); err != nil || balance.Balance.Int() != balances[common.MinerAddress()] {
+		t.Fatalf("expected the balance of the miner, got %s", responses[1].Result)
+	}
+
+	if responses[2].Error == nil || !strings.Contains(*responses[2].Error, "requires authorization") {
+		t.Fatalf("expected the unauthenticated request to fail alone, got %+v", responses[2])
+	}
+}
+
+// TestBatchRequestLimit checks that empty batches and batches beyond the limit are rejected, and that
+// batches are disabled without a limit
+func TestBatchRequestLimit(t *testing.T) {
+	api := newTestAPI(t, 0)
+
+	config := DefaultServerConfig()
+	config.MaxBatchSize = 2
+	router := newTestRouter(t, api, config)
+
+	request := `{"method":"API.GetChainInfo","params":[{}],"id":1}`
+	headers := map[string]string{"Content-Type": "application/json"}
+
+	tests := []struct {
+		name    string
+		body    string
+		message string
+	}{
+		{"empty", `[]`, "empty batch"},
+		{"beyond the limit", "[" + strings.Repeat(request+",", 2) + request + "]", "exceeds the limit of 2"},
+		{"malformed", `[{"method"`, "malformed batch"},
+	}
+
+	for _, test := range tests {
+		response := serve(router, http.MethodPost, DefaultPath, test.body, headers)
+		if response.Code != http.StatusBadRequest || !strings.Contains(response.Body.String(), test.message) {
+			t.Errorf("%v: expected the batch to be rejected with %q, got status %v and %q", test.name, test.message, response.Code, response.Body)
+		}
+	}
+
+	config.MaxBatchSize = 0
+	disabled := newTestRouter(t, api, config)
+
+	if response := serve(disabled, http.MethodPost, DefaultPath, "["+request+"]", headers); response.Code != http.StatusBadRequest || !strings.Contains(response.Body.String(), "disabled") {
+		t.Fatalf("expected batches to be disabled, got status %v", response.Code)
+	}
+
+	if response := serve(disabled, http.MethodPost, DefaultPath, request, headers); response.Code != http.StatusOK {
+		t.Fatalf("expected a single request to be served, got status %v", response.Code)
+	}
+}
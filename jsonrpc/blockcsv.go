@@ -0,0 +1,88 @@
+package jsonrpc
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+)
+
+// DefaultBlockCSVPath is the default route at which the Transactions of a Block are served as CSV
+const DefaultBlockCSVPath = "/csv/block"
+
+// BlockCSVHeader is the header row of the CSV served for the Transactions of a Block
+var BlockCSVHeader = []string{"txid", "input_count", "output_count", "total_value", "fee"}
+
+// serveBlockCSV serves the Transactions of a Block as CSV, with a row for each Transaction including the coinbase.
+// The Block is selected with either the 'hash' or the 'height' query parameter of the request.
+func (api *API) serveBlockCSV(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "csv: method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	block, err := api.queryBlock(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("csv: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// Build all rows before writing, so that an error can still be reported with a status
+	rows := [][]string{BlockCSVHeader}
+	for _, txn := range block.BlockTxns {
+		fee, err := api.chain.TransactionFee(txn)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("csv: transaction '%v': %v", txn.ID, err), http.StatusInternalServerError)
+			return
+		}
+
+		total := 0
+		for _, output := range txn.Outputs {
+			total += output.Value
+		}
+
+		rows = append(rows, []string{
+			txn.ID.Hex(),
+			strconv.Itoa(len(txn.Inputs)),
+			strconv.Itoa(len(txn.Outputs)),
+			strconv.Itoa(total),
+			strconv.Itoa(fee),
+		})
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"block-%v.csv\"", block.BlockHeight))
+
+	if err := csv.NewWriter(w).WriteAll(rows); err != nil {
+		log.Println("Failed to write block csv:", err)
+	}
+}
+
+// queryBlock returns the Block selected by the 'hash' or 'height' query parameter of a request
+func (api *API) queryBlock(r *http.Request) (*core.Block, error) {
+	query := r.URL.Query()
+
+	if hash := query.Get("hash"); hash != "" {
+		blockHash, err := common.HexToHash(hash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block hash: %w", err)
+		}
+
+		return api.chain.GetBlock(blockHash)
+	}
+
+	if height := query.Get("height"); height != "" {
+		blockHeight, err := strconv.ParseInt(height, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block height: %w", err)
+		}
+
+		return api.chain.GetBlockByHeight(blockHeight)
+	}
+
+	return nil, fmt.Errorf("missing 'hash' or 'height' query parameter")
+}
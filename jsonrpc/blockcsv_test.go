@@ -0,0 +1,56 @@
+package jsonrpc
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestBlockCSV checks that the CSV export of a Block has the header row and a row for each of its Transactions,
+// including the coinbase, for a Block selected by height or by hash
+func TestBlockCSV(t *testing.T) {
+	api := newTestAPI(t, 2)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	block, err := api.chain.GetBlockByHeight(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, query := range []string{"height=1", "hash=" + block.BlockHash.Hex()} {
+		response := serve(router, http.MethodGet, DefaultBlockCSVPath+"?"+query, "", nil)
+		if response.Code != http.StatusOK || response.Header().Get("Content-Type") != "text/csv" {
+			t.Fatalf("%v: expected a csv response, got status %v and headers %v", query, response.Code, response.Header())
+		}
+
+		rows, err := csv.NewReader(response.Body).ReadAll()
+		if err != nil {
+			t.Fatalf("%v: invalid csv: %v", query, err)
+		}
+
+		if strings.Join(rows[0], ",") != strings.Join(BlockCSVHeader, ",") {
+			t.Fatalf("%v: expected the header %v, got %v", query, BlockCSVHeader, rows[0])
+		}
+
+		if len(rows) != block.TxnCount()+1 {
+			t.Fatalf("%v: expected %v transaction rows, got %v", query, block.TxnCount(), len(rows)-1)
+		}
+
+		for idx, txn := range block.BlockTxns {
+			row := rows[idx+1]
+			if row[0] != txn.ID.Hex() || row[1] != strconv.Itoa(len(txn.Inputs)) || row[2] != strconv.Itoa(len(txn.Outputs)) {
+				t.Fatalf("%v: expected the row of transaction '%v', got %v", query, txn.ID, row)
+			}
+		}
+	}
+
+	if response := serve(router, http.MethodGet, DefaultBlockCSVPath, "", nil); response.Code != http.StatusBadRequest {
+		t.Fatalf("expected a request without a block to be rejected, got status %v", response.Code)
+	}
+
+	if response := serve(router, http.MethodPost, DefaultBlockCSVPath+"?height=1", "", nil); response.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected a post request to be rejected, got status %v", response.Code)
+	}
+}
@@ -0,0 +1,64 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+)
+
+type CheckTransactionArgs struct {
+	// Hex encoded serialized Transaction
+	Transaction string `json:"transaction"`
+}
+
+type CheckTransactionResult struct {
+	TxID   string `json:"txid"`
+	Valid  bool   `json:"valid"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// CheckTransaction validates a serialized Transaction against the current chain state
+// without submitting it. Invalid transactions are reported in the result, not as errors.
+func (api *API) CheckTransaction(r *http.Request, args *CheckTransactionArgs, result *CheckTransactionResult) error {
+	log.Println("'CheckTransaction' Called")
+
+	txn, err := decodeTransaction(args.Transaction)
+	if err != nil {
+		return err
+	}
+
+	*result = CheckTransactionResult{TxID: txn.ID.Hex(), Valid: true}
+	if err := api.chain.CheckTransaction(txn); err != nil {
+		result.Valid, result.Reason = false, err.Error()
+	}
+
+	return nil
+}
+
+// decodeTransaction decodes a hex encoded serialized Transaction
+func decodeTransaction(input string) (*core.Transaction, error) {
+	data, err := common.HexDecode(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction hex: %w", err)
+	}
+
+	txn := new(core.Transaction)
+	if err := txn.Deserialize(data); err != nil {
+		return nil, fmt.Errorf("invalid transaction data: %w", err)
+	}
+
+	return txn, nil
+}
+
+// encodeTransaction encodes a Transaction as a hex encoded serialized Transaction
+func encodeTransaction(txn *core.Transaction) (string, error) {
+	data, err := txn.Serialize()
+	if err != nil {
+		return "", fmt.Errorf("transaction serialize failed: %w", err)
+	}
+
+	return common.HexEncode(data), nil
+}
@@ -0,0 +1,66 @@
+package jsonrpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/core"
+)
+
+// TestCheckTransaction checks that CheckTransaction reports whether a serialized Transaction is valid for the next Block,
+// with the reason for an invalid Transaction, without adding it to the mempool or the chain
+func TestCheckTransaction(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	// The genesis output is spent to alice, whose output is unspent
+	spent := spendGenesis(t, api, core.TxOutput{Value: core.BlockReward, PubKey: "alice"})
+	unspent := []core.Outpoint{{ID: spent.ID, Index: 0}}
+
+	tests := []struct {
+		name   string
+		txn    *core.Transaction
+		reason string
+	}{
+		{"valid", signedTransaction(t, "alice", unspent, core.TxOutput{Value: 60, PubKey: "bob"}, core.TxOutput{Value: 40, PubKey: "alice"}), ""},
+		{"bad signature", signedTransaction(t, "mallory", unspent, core.TxOutput{Value: core.BlockReward, PubKey: "mallory"}), "signature"},
+		{"double spend", signedTransaction(t, "alice", []core.Outpoint{{ID: spent.Inputs[0].ID, Index: spent.Inputs[0].Out}}, core.TxOutput{Value: core.BlockReward, PubKey: "bob"}), "not in the utxo set"},
+		{"negative fee", signedTransaction(t, "alice", unspent, core.TxOutput{Value: core.BlockReward + 1, PubKey: "bob"}), "exceeds input value"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoded, err := encodeTransaction(test.txn)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var result CheckTransactionResult
+			callResult(t, router, "API.CheckTransaction", CheckTransactionArgs{Transaction: encoded}, &result)
+
+			if result.TxID != test.txn.ID.Hex() {
+				t.Fatalf("expected txid '%v', got '%v'", test.txn.ID.Hex(), result.TxID)
+			}
+
+			if test.reason == "" {
+				if !result.Valid || result.Reason != "" {
+					t.Fatalf("expected the transaction to be valid, got %+v", result)
+				}
+
+				return
+			}
+
+			if result.Valid || !strings.Contains(result.Reason, test.reason) {
+				t.Fatalf("expected the transaction to be invalid for a reason containing %q, got %+v", test.reason, result)
+			}
+		})
+	}
+
+	if api.chain.Height != 2 || api.chain.Mempool().Size() != 0 {
+		t.Fatalf("expected the chain and the mempool to be unchanged")
+	}
+
+	if message := callError(t, router, "API.CheckTransaction", CheckTransactionArgs{Transaction: "not hex"}); !strings.Contains(message, "invalid transaction hex") {
+		t.Fatalf("expected an invalid hex error, got %q", message)
+	}
+}
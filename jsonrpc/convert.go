@@ -0,0 +1,23 @@
+package jsonrpc
+
+import "fmt"
+
+// toUint64 converts a non-negative int64 such as a height or count into a uint64.
+// Negative values are reported as 0 rather than wrapping around to a huge value.
+func toUint64(value int64) uint64 {
+	if value < 0 {
+		return 0
+	}
+
+	return uint64(value)
+}
+
+// headHeight returns the height of the Block at the chain head.
+// Returns an error if the chain has no Blocks, instead of underflowing.
+func (api *API) headHeight() (uint64, error) {
+	if api.chain.Height < 1 {
+		return 0, fmt.Errorf("chain has no blocks")
+	}
+
+	return uint64(api.chain.Height - 1), nil
+}
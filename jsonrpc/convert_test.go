@@ -0,0 +1,61 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+)
+
+// TestToUint64 checks that negative values convert to 0 instead of wrapping around
+func TestToUint64(t *testing.T) {
+	tests := []struct {
+		value int64
+		want  uint64
+	}{
+		{-1, 0},
+		{-1 << 63, 0},
+		{0, 0},
+		{1, 1},
+		{1<<63 - 1, 1<<63 - 1},
+	}
+
+	for _, test := range tests {
+		if got := toUint64(test.value); got != test.want {
+			t.Errorf("toUint64(%v): expected %v, got %v", test.value, test.want, got)
+		}
+	}
+}
+
+// TestHeightBoundaries checks the heights reported by MineBlock, AddBlock and ShowChain from the Genesis Block,
+// and that the head height of a chain without Blocks is an error rather than an underflow
+func TestHeightBoundaries(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	var chain ShowChainResult
+	callResult(t, router, "API.ShowChain", ShowChainArgs{}, &chain)
+
+	if chain.ChainHeight != 1 || len(chain.Blocks) != 1 || chain.Blocks[0].Height != 0 {
+		t.Fatalf("expected a chain of height 1 with the genesis block at height 0, got %+v", chain)
+	}
+
+	var mined MineBlockResult
+	callResult(t, router, "API.MineBlock", MineBlockArgs{}, &mined)
+
+	if mined.BlockHeight != 1 || mined.BlockHash != api.chain.Head.Hex() {
+		t.Fatalf("expected the mined block at height 1, got %+v", mined)
+	}
+
+	var added AddBlockResult
+	callResult(t, router, "API.AddBlock", AddBlockArgs{Transactions: []TransactionInput{{From: string(common.MinerAddress()), To: "alice", Value: NewValue(10)}}}, &added)
+
+	if added.BlockHeight != 2 || added.BlockHash != api.chain.Head.Hex() {
+		t.Fatalf("expected the added block at height 2, got %+v", added)
+	}
+
+	empty := &API{chain: &core.ChainManager{}}
+	if height, err := empty.headHeight(); err == nil {
+		t.Fatalf("expected an error for the head height of an empty chain, got %v", height)
+	}
+}
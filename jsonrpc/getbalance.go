@@ -0,0 +1,109 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/anee769/essensio/common"
+)
+
+// MaxBatchAddresses is the maximum number of addresses in a BatchGetBalance request
+const MaxBatchAddresses = 1000
+
+type GetBalanceArgs struct {
+	Address string `json:"address"`
+	// Height of the Block after which the balance is returned, or the chain head if omitted
+	Height *int64 `json:"height,omitempty"`
+	// Minimum height of the Blocks whose outputs are counted. The balance is partial if set,
+	// as outputs created below it are excluded. Cannot be combined with a height.
+	MinHeight int64 `json:"min_height,omitempty"`
+}
+
+type GetBalanceResult struct {
+	Address string `json:"address"`
+	Balance Value  `json:"balance"`
+}
+
+// GetBalance returns the balance of an address at the chain head, or after the Block at the given height.
+// Historical balances are replayed from the nearest balance snapshot of the chain.
+// With a minimum height, only the outputs created at or above it are counted, for a partial balance.
+func (api *API) GetBalance(r *http.Request, args *GetBalanceArgs, result *GetBalanceResult) error {
+	log.Println("'GetBalance' Called")
+
+	address := common.NormalizeAddress(args.Address)
+	if args.Height != nil {
+		if args.MinHeight != 0 {
+			return fmt.Errorf("height and min_height cannot be combined")
+		}
+
+		balance, err := api.chain.BalanceAt(address, *args.Height)
+		if err != nil {
+			return fmt.Errorf("failed to compute balance: %w", err)
+		}
+
+		*result = GetBalanceResult{Address: string(address), Balance: NewValue(balance)}
+		return nil
+	}
+
+	outputs, err := api.chain.FindUTXOFrom(address, args.MinHeight)
+	if err != nil {
+		return fmt.Errorf("failed to find unspent outputs: %w", err)
+	}
+
+	balance := 0
+	for _, output := range outputs {
+		balance += output.Value
+	}
+
+	*result = GetBalanceResult{
+		Address: string(address),
+		Balance: NewValue(balance),
+	}
+
+	return nil
+}
+
+type BatchGetBalanceArgs struct {
+	Addresses []string `json:"addresses"`
+}
+
+type BatchGetBalanceResult struct {
+	Balances map[string]Value `json:"balances"`
+}
+
+func (api *API) BatchGetBalance(r *http.Request, args *BatchGetBalanceArgs, result *BatchGetBalanceResult) error {
+	log.Println("'BatchGetBalance' Called")
+
+	if len(args.Addresses) == 0 {
+		return fmt.Errorf("no addresses for batch")
+	}
+
+	if len(args.Addresses) > MaxBatchAddresses {
+		return fmt.Errorf("too many addresses for batch: %v > %v", len(args.Addresses), MaxBatchAddresses)
+	}
+
+	addresses := make([]common.Address, 0, len(args.Addresses))
+	for _, address := range args.Addresses {
+		addresses = append(addresses, common.NormalizeAddress(address))
+	}
+
+	UTXOs, err := api.chain.FindUTXOMulti(addresses)
+	if err != nil {
+		return fmt.Errorf("failed to find unspent outputs: %w", err)
+	}
+
+	// Every requested address is present in the result, with a zero balance if it has no outputs
+	balances := make(map[string]Value, len(addresses))
+	for _, address := range addresses {
+		balance := 0
+		for _, output := range UTXOs[address] {
+			balance += output.Value
+		}
+
+		balances[string(address)] = NewValue(balance)
+	}
+
+	*result = BatchGetBalanceResult{Balances: balances}
+	return nil
+}
@@ -0,0 +1,128 @@
+package jsonrpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+)
+
+// TestBatchGetBalance checks that the balances of BatchGetBalance equal those of individual GetBalance calls,
+// including addresses without any outputs
+func TestBatchGetBalance(t *testing.T) {
+	api := newTestAPI(t, 10)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	addresses := []string{"missing"}
+	for idx := 0; idx < core.DefaultTestAddresses; idx++ {
+		addresses = append(addresses, string(core.TestAddress(idx)))
+	}
+
+	var batch BatchGetBalanceResult
+	callResult(t, router, "API.BatchGetBalance", BatchGetBalanceArgs{Addresses: addresses}, &batch)
+
+	if len(batch.Balances) != len(addresses) {
+		t.Fatalf("expected %v balances, got %v", len(addresses), len(batch.Balances))
+	}
+
+	total := 0
+	for _, address := range addresses {
+		var single GetBalanceResult
+		callResult(t, router, "API.GetBalance", GetBalanceArgs{Address: address}, &single)
+
+		if batch.Balances[address] != single.Balance {
+			t.Fatalf("expected the batch balance of %v to be %v, got %v", address, single.Balance, batch.Balances[address])
+		}
+
+		total += single.Balance.Int()
+	}
+
+	if total == 0 || batch.Balances["missing"].Int() != 0 {
+		t.Fatalf("expected nonzero balances for the test addresses and none for a missing address, got %v", batch.Balances)
+	}
+}
+
+// TestBatchGetBalanceLimit checks that BatchGetBalance rejects empty batches and batches beyond the address cap
+func TestBatchGetBalanceLimit(t *testing.T) {
+	router := newTestRouter(t, newTestAPI(t, 0), DefaultServerConfig())
+
+	if err := callError(t, router, "API.BatchGetBalance", BatchGetBalanceArgs{}); !strings.Contains(err, "no addresses") {
+		t.Fatalf("expected an empty batch to be rejected, got %v", err)
+	}
+
+	addresses := make([]string, MaxBatchAddresses+1)
+	for idx := range addresses {
+		addresses[idx] = "address"
+	}
+
+	if err := callError(t, router, "API.BatchGetBalance", BatchGetBalanceArgs{Addresses: addresses}); !strings.Contains(err, "too many addresses") {
+		t.Fatalf("expected a batch beyond the cap to be rejected, got %v", err)
+	}
+}
+
+// TestGetBalanceHeight checks that the historical balances of GetBalance, which are replayed from the balance
+// snapshots of the chain, equal the balances of a full scan of the Blocks up to each height
+func TestGetBalanceHeight(t *testing.T) {
+	opts := testChainOptions(8)
+	opts.Options.SnapshotInterval = 3
+
+	api := newTestAPIOptions(t, opts)
+	router := newTestRouter(t, api, DefaultServerConfig())
+	address := core.TestAddress(0)
+
+	scanned := 0
+	for height := int64(0); height < api.chain.Height; height++ {
+		block, err := api.chain.GetBlockByHeight(height)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		deltas, err := core.BlockDeltas(block, api.chain)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		scanned += deltas[address]
+
+		var result GetBalanceResult
+		callResult(t, router, "API.GetBalance", GetBalanceArgs{Address: string(address), Height: &height}, &result)
+
+		if result.Balance.Int() != scanned {
+			t.Fatalf("expected the balance at height %v to be %v, got %v", height, scanned, result.Balance.Int())
+		}
+	}
+
+	var head GetBalanceResult
+	callResult(t, router, "API.GetBalance", GetBalanceArgs{Address: string(address)}, &head)
+
+	if head.Balance.Int() != scanned || scanned == 0 {
+		t.Fatalf("expected the balance at the chain head to be %v, got %v", scanned, head.Balance.Int())
+	}
+}
+
+// TestGetBalanceMinHeight checks that GetBalance with a minimum height only counts the outputs created at or above it,
+// and that a minimum height cannot be combined with a height
+func TestGetBalanceMinHeight(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+	miner := string(common.MinerAddress())
+
+	var mined MineBlockResult
+	callResult(t, router, "API.MineBlock", MineBlockArgs{}, &mined)
+
+	var full GetBalanceResult
+	callResult(t, router, "API.GetBalance", GetBalanceArgs{Address: miner}, &full)
+
+	var partial GetBalanceResult
+	callResult(t, router, "API.GetBalance", GetBalanceArgs{Address: miner, MinHeight: 1}, &partial)
+
+	if full.Balance.Int() != 2*core.BlockReward || partial.Balance.Int() != core.BlockReward {
+		t.Fatalf("expected a full balance of %v and a partial balance of %v, got %v and %v", 2*core.BlockReward, core.BlockReward, full.Balance, partial.Balance)
+	}
+
+	height := int64(0)
+	if err := callError(t, router, "API.GetBalance", GetBalanceArgs{Address: miner, Height: &height, MinHeight: 1}); !strings.Contains(err, "cannot be combined") {
+		t.Fatalf("expected a height with a minimum height to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+)
+
+type GetBlockByHashArgs struct {
+	Hash   string `json:"hash"`
+	Deltas bool   `json:"deltas"`
+	Verify bool   `json:"verify"`
+}
+
+type GetBlockByHashResult struct {
+	Block  ChainBlock       `json:"block"`
+	Deltas map[string]Value `json:"deltas,omitempty"`
+}
+
+// GetBlockByHash returns the Block with the given hash.
+// If deltas are requested, the net change in balance the Block causes for each address is included.
+// If verify is set, the Block reports whether its Transactions match its header summary.
+func (api *API) GetBlockByHash(r *http.Request, args *GetBlockByHashArgs, result *GetBlockByHashResult) error {
+	log.Println("'GetBlockByHash' Called")
+
+	hash, err := common.HexToHash(args.Hash)
+	if err != nil {
+		return fmt.Errorf("invalid block hash: %w", err)
+	}
+
+	block, err := api.chain.GetBlock(hash)
+	if err != nil {
+		return fmt.Errorf("block not found: %w", err)
+	}
+
+	*result = GetBlockByHashResult{Block: NewVerifiedChainBlock(block, args.Verify)}
+
+	if args.Deltas {
+		deltas, err := core.BlockDeltas(block, api.chain)
+		if err != nil {
+			return fmt.Errorf("failed to compute block deltas: %w", err)
+		}
+
+		result.Deltas = make(map[string]Value, len(deltas))
+		for address, delta := range deltas {
+			result.Deltas[string(address)] = NewValue(delta)
+		}
+	}
+
+	return nil
+}
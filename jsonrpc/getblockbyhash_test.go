@@ -0,0 +1,44 @@
+package jsonrpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+)
+
+// TestGetBlockByHash checks that GetBlockByHash returns the Block with the given hash,
+// with its balance deltas only if they are requested
+func TestGetBlockByHash(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	spendGenesis(t, api, core.TxOutput{Value: 60, PubKey: "alice"}, core.TxOutput{Value: 40, PubKey: "bob"})
+	hash := api.chain.Head.Hex()
+
+	var result GetBlockByHashResult
+	callResult(t, router, "API.GetBlockByHash", GetBlockByHashArgs{Hash: hash}, &result)
+
+	if result.Block.BlockHash != hash || result.Deltas != nil {
+		t.Fatalf("expected block '%v' without deltas, got %+v", hash, result)
+	}
+
+	callResult(t, router, "API.GetBlockByHash", GetBlockByHashArgs{Hash: hash, Deltas: true}, &result)
+
+	// The miner spends the genesis reward and is paid the coinbase of the Block
+	want := map[string]int{"alice": 60, "bob": 40, string(common.MinerAddress()): 0}
+	if len(result.Deltas) != len(want) {
+		t.Fatalf("expected deltas %v, got %v", want, result.Deltas)
+	}
+
+	for address, delta := range want {
+		if result.Deltas[address].Int() != delta {
+			t.Fatalf("expected deltas %v, got %v", want, result.Deltas)
+		}
+	}
+
+	if message := callError(t, router, "API.GetBlockByHash", GetBlockByHashArgs{Hash: common.NullHash().Hex()}); !strings.Contains(message, "block not found") {
+		t.Fatalf("expected an unknown block error, got %q", message)
+	}
+}
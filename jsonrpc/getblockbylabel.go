@@ -0,0 +1,28 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+)
+
+type GetBlockByLabelArgs struct {
+	Label string `json:"label"`
+}
+
+type GetBlockByLabelResult struct {
+	Block ChainBlock `json:"block"`
+}
+
+// GetBlockByLabel returns the Block assigned to a label with LabelBlock
+func (api *API) GetBlockByLabel(r *http.Request, args *GetBlockByLabelArgs, result *GetBlockByLabelResult) error {
+	log.Println("'GetBlockByLabel' Called")
+
+	block, err := api.chain.GetBlockByLabel(args.Label)
+	if err != nil {
+		return fmt.Errorf("block not found: %w", err)
+	}
+
+	*result = GetBlockByLabelResult{Block: NewChainBlock(block)}
+	return nil
+}
@@ -0,0 +1,47 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/anee769/essensio/common"
+)
+
+type GetBlockPathArgs struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type GetBlockPathResult struct {
+	Blocks []ChainBlock `json:"blocks"`
+}
+
+// GetBlockPath returns the blocks that connect two blocks along their priori links, from the ancestor
+// to the descendant. The path is bounded by the maximum response size of the server.
+func (api *API) GetBlockPath(r *http.Request, args *GetBlockPathArgs, result *GetBlockPathResult) error {
+	log.Println("'GetBlockPath' Called")
+
+	from, err := common.HexToHash(args.From)
+	if err != nil {
+		return fmt.Errorf("invalid from block hash: %w", err)
+	}
+
+	to, err := common.HexToHash(args.To)
+	if err != nil {
+		return fmt.Errorf("invalid to block hash: %w", err)
+	}
+
+	path, err := api.chain.BlockPath(from, to, api.maxResponseBlocks)
+	if err != nil {
+		return fmt.Errorf("failed to find block path: %w", err)
+	}
+
+	blocks := make([]ChainBlock, 0, len(path))
+	for _, block := range path {
+		blocks = append(blocks, NewChainBlock(block))
+	}
+
+	*result = GetBlockPathResult{Blocks: blocks}
+	return nil
+}
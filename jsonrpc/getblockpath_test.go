@@ -0,0 +1,45 @@
+package jsonrpc
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGetBlockPath checks that the path between the Genesis Block and the chain head lists all Blocks in order,
+// and that paths beyond the maximum response size are rejected
+func TestGetBlockPath(t *testing.T) {
+	api := newTestAPI(t, 3)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	genesis, err := api.chain.BlockHashAtHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := GetBlockPathArgs{From: genesis.Hex(), To: api.chain.Head.Hex()}
+
+	var result GetBlockPathResult
+	callResult(t, router, "API.GetBlockPath", args, &result)
+
+	if int64(len(result.Blocks)) != api.chain.Height {
+		t.Fatalf("expected a path of %v blocks, got %v", api.chain.Height, len(result.Blocks))
+	}
+
+	for idx, block := range result.Blocks {
+		hash, err := api.chain.BlockHashAtHeight(int64(idx))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if block.Height != uint64(idx) || block.BlockHash != hash.Hex() {
+			t.Fatalf("expected block '%v' at position %v of the path, got %+v", hash.Hex(), idx, block)
+		}
+	}
+
+	config := DefaultServerConfig()
+	config.MaxResponseBlocks = 2
+
+	if err := callError(t, newTestRouter(t, api, config), "API.GetBlockPath", args); !strings.Contains(err, "exceeds the maximum") {
+		t.Fatalf("expected a path beyond the maximum response size to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// MaxBlockStatsRange is the maximum number of blocks for which GetBlockStats returns metrics
+const MaxBlockStatsRange = 1000
+
+type GetBlockStatsArgs struct {
+	StartHeight int64 `json:"start_height"`
+	Limit       int64 `json:"limit"`
+}
+
+type GetBlockStatsResult struct {
+	Blocks []BlockStats `json:"blocks"`
+}
+
+type BlockStats struct {
+	Height     uint64 `json:"height"`
+	Timestamp  int64  `json:"timestamp"`
+	TxnCount   int    `json:"txn_count"`
+	TotalValue Value  `json:"total_value"`
+	Size       int    `json:"size"`
+	Interval   int64  `json:"interval"`
+}
+
+// GetBlockStats returns the metrics of the blocks in a range of heights, starting from the given height.
+// The interval of a block is the time since the timestamp of its parent, and is 0 for the Genesis Block.
+// The range is bounded by the limit, which defaults to and cannot exceed MaxBlockStatsRange.
+func (api *API) GetBlockStats(r *http.Request, args *GetBlockStatsArgs, result *GetBlockStatsResult) error {
+	log.Println("'GetBlockStats' Called")
+
+	if args.StartHeight < 0 || args.StartHeight >= api.chain.Height {
+		return fmt.Errorf("start height %v out of range for chain height %v", args.StartHeight, api.chain.Height)
+	}
+
+	limit := args.Limit
+	if limit < 0 {
+		return fmt.Errorf("invalid limit: %v", limit)
+	}
+
+	if limit == 0 || limit > MaxBlockStatsRange {
+		limit = MaxBlockStatsRange
+	}
+
+	end := args.StartHeight + limit
+	if end > api.chain.Height {
+		end = api.chain.Height
+	}
+
+	// Find the timestamp of the parent of the first block
+	var parentTime int64
+	if args.StartHeight > 0 {
+		parent, err := api.chain.GetBlockByHeight(args.StartHeight - 1)
+		if err != nil {
+			return fmt.Errorf("failed to get block: %w", err)
+		}
+
+		parentTime = parent.Timestamp
+	}
+
+	stats := make([]BlockStats, 0, end-args.StartHeight)
+	for height := args.StartHeight; height < end; height++ {
+		if err := checkContext(r); err != nil {
+			return err
+		}
+
+		block, err := api.chain.GetBlockByHeight(height)
+		if err != nil {
+			return fmt.Errorf("failed to get block: %w", err)
+		}
+
+		data, err := block.Serialize()
+		if err != nil {
+			return fmt.Errorf("failed to serialize block: %w", err)
+		}
+
+		total := 0
+		for _, txn := range block.BlockTxns {
+			for _, output := range txn.Outputs {
+				total += output.Value
+			}
+		}
+
+		var interval int64
+		if height > 0 {
+			interval = block.Timestamp - parentTime
+		}
+
+		stats = append(stats, BlockStats{
+			Height:     toUint64(block.BlockHeight),
+			Timestamp:  block.Timestamp,
+			TxnCount:   block.TxnCount(),
+			TotalValue: NewValue(total),
+			Size:       len(data),
+			Interval:   interval,
+		})
+
+		parentTime = block.Timestamp
+	}
+
+	*result = GetBlockStatsResult{Blocks: stats}
+	return nil
+}
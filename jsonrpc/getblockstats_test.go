@@ -0,0 +1,54 @@
+package jsonrpc
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGetBlockStats checks that the intervals of GetBlockStats are the timestamp deltas of the generated Blocks
+// and that the range of heights is bounded by the limit and the chain height
+func TestGetBlockStats(t *testing.T) {
+	api := newTestAPI(t, 4)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	var result GetBlockStatsResult
+	callResult(t, router, "API.GetBlockStats", GetBlockStatsArgs{StartHeight: 1, Limit: 3}, &result)
+
+	if len(result.Blocks) != 3 {
+		t.Fatalf("expected 3 block stats, got %v", len(result.Blocks))
+	}
+
+	for idx, stats := range result.Blocks {
+		height := int64(idx + 1)
+
+		block, err := api.chain.GetBlockByHeight(height)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		parent, err := api.chain.GetBlockByHeight(height - 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if stats.Height != uint64(height) || stats.Timestamp != block.Timestamp || stats.TxnCount != block.TxnCount() {
+			t.Fatalf("expected the stats of the block at height %v, got %+v", height, stats)
+		}
+
+		if want := block.Timestamp - parent.Timestamp; stats.Interval != want {
+			t.Fatalf("expected the interval at height %v to be %v, got %v", height, want, stats.Interval)
+		}
+	}
+
+	// The range is truncated at the chain head, and the Genesis Block has no interval
+	var all GetBlockStatsResult
+	callResult(t, router, "API.GetBlockStats", GetBlockStatsArgs{}, &all)
+
+	if int64(len(all.Blocks)) != api.chain.Height || all.Blocks[0].Interval != 0 {
+		t.Fatalf("expected the stats of all %v blocks from the genesis block, got %+v", api.chain.Height, all.Blocks)
+	}
+
+	if err := callError(t, router, "API.GetBlockStats", GetBlockStatsArgs{StartHeight: api.chain.Height}); !strings.Contains(err, "out of range") {
+		t.Fatalf("expected a start height out of range, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/anee769/essensio/core"
+)
+
+type GetChainInfoArgs struct{}
+
+type GetChainInfoResult struct {
+	ChainHeight uint64 `json:"chain_height"`
+	Head        string `json:"head"`
+	Work        string `json:"work"`
+	// Median timestamp of the most recent blocks, which the timestamp of the next block must not precede
+	MedianTime int64 `json:"median_time"`
+	// Maximum number of seconds by which the timestamp of a block may be ahead of the clock of the node
+	MaxClockSkew int64 `json:"max_clock_skew"`
+}
+
+// GetChainInfo returns the state of the chain head along with the effective timestamp validation bounds of the node
+func (api *API) GetChainInfo(r *http.Request, args *GetChainInfoArgs, result *GetChainInfoResult) error {
+	log.Println("'GetChainInfo' Called")
+
+	state := api.chain.State()
+
+	median, err := api.chain.MedianTimePast(core.MedianTimeBlocks)
+	if err != nil {
+		return fmt.Errorf("failed to get median time past: %w", err)
+	}
+
+	*result = GetChainInfoResult{
+		ChainHeight:  toUint64(state.Height),
+		Head:         state.Head.Hex(),
+		Work:         state.Work.String(),
+		MedianTime:   median,
+		MaxClockSkew: int64(api.chain.MaxClockSkew().Seconds()),
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package jsonrpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anee769/essensio/core"
+)
+
+// TestGetChainInfo checks that GetChainInfo reports the chain head and the effective clock skew of the node
+func TestGetChainInfo(t *testing.T) {
+	opts := testChainOptions(2)
+	opts.Options.MaxClockSkew = 15 * time.Minute
+
+	api := newTestAPIOptions(t, opts)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	var result GetChainInfoResult
+	callResult(t, router, "API.GetChainInfo", GetChainInfoArgs{}, &result)
+
+	median, err := api.chain.MedianTimePast(core.MedianTimeBlocks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Head != api.chain.Head.Hex() || result.ChainHeight != uint64(api.chain.Height) || result.MedianTime != median {
+		t.Fatalf("expected the state of the chain head, got %+v", result)
+	}
+
+	if result.MaxClockSkew != 15*60 || result.Work != api.chain.Work.String() {
+		t.Fatalf("expected a clock skew of %v seconds, got %+v", 15*60, result)
+	}
+}
@@ -0,0 +1,55 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+)
+
+type GetDebugStateArgs struct{}
+
+type GetDebugStateResult struct {
+	Head   string `json:"head"`
+	Height uint64 `json:"height"`
+	// Cumulative chain work as a decimal string
+	Work string `json:"work"`
+
+	// Legacy chain head and height entries, omitted if they do not exist
+	LegacyHead   string  `json:"legacy_head,omitempty"`
+	LegacyHeight *uint64 `json:"legacy_height,omitempty"`
+
+	TxnCount uint64 `json:"txn_count"`
+	Supply   Value  `json:"supply"`
+
+	Blocks  int            `json:"blocks"`
+	Entries map[string]int `json:"entries"`
+}
+
+// GetDebugState returns the raw chain state entries stored in the database along with
+// the number of entries of each key prefix. It exposes internal state, so it requires the
+// auth token of the server. The stored state may lag the chain with batched durability.
+func (api *API) GetDebugState(r *http.Request, args *GetDebugStateArgs, result *GetDebugStateResult) error {
+	log.Println("'GetDebugState' Called")
+
+	state, err := api.chain.DebugState()
+	if err != nil {
+		return fmt.Errorf("failed to read debug state: %w", err)
+	}
+
+	*result = GetDebugStateResult{
+		Head:     state.Head.Hex(),
+		Height:   toUint64(state.Height),
+		Work:     state.Work.String(),
+		TxnCount: toUint64(state.TxnCount),
+		Supply:   NewValue(state.Supply),
+		Blocks:   state.Blocks,
+		Entries:  state.Entries,
+	}
+
+	if state.Legacy {
+		height := toUint64(state.LegacyHeight)
+		result.LegacyHead, result.LegacyHeight = state.LegacyHead.Hex(), &height
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package jsonrpc
+
+import (
+	"net/http"
+	"testing"
+)
+
+// TestGetDebugState checks that GetDebugState reports the head and height of the chain after Blocks are added,
+// and that it is only served to requests with the auth token of the server
+func TestGetDebugState(t *testing.T) {
+	api := newTestAPI(t, 2)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	var mined MineBlockResult
+	callResult(t, router, "API.MineBlock", MineBlockArgs{}, &mined)
+
+	var result GetDebugStateResult
+	callResult(t, router, "API.GetDebugState", GetDebugStateArgs{}, &result)
+
+	if result.Head != api.chain.Head.Hex() || result.Height != uint64(api.chain.Height) {
+		t.Fatalf("expected head '%v' at height %v, got '%v' at height %v", api.chain.Head.Hex(), api.chain.Height, result.Head, result.Height)
+	}
+
+	if result.Blocks != int(api.chain.Height) || result.LegacyHeight != nil {
+		t.Fatalf("expected %v blocks and no legacy height, got %+v", api.chain.Height, result)
+	}
+
+	body := `{"method":"API.GetDebugState","params":[{}],"id":1}`
+	if response := serve(router, http.MethodPost, DefaultPath, body, map[string]string{"Content-Type": "application/json"}); response.Code != http.StatusUnauthorized {
+		t.Fatalf("expected an unauthorized request to be rejected, got status %v", response.Code)
+	}
+}
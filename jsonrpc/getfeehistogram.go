@@ -0,0 +1,66 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+
+	"github.com/anee769/essensio/core"
+)
+
+type GetFeeHistogramArgs struct {
+	// Strictly increasing lower edges of the buckets in fee per byte, core.DefaultFeeRateEdges if empty
+	Edges []float64 `json:"edges"`
+}
+
+type FeeBucket struct {
+	MinRate float64 `json:"min_rate"`
+	// Upper edge of the bucket in fee per byte, omitted for the highest bucket
+	MaxRate        *float64 `json:"max_rate,omitempty"`
+	Count          int      `json:"count"`
+	Size           int      `json:"size"`
+	CumulativeSize int      `json:"cumulative_size"`
+}
+
+type GetFeeHistogramResult struct {
+	// Buckets in order of decreasing fee rate
+	Buckets []FeeBucket `json:"buckets"`
+}
+
+// GetFeeHistogram returns the distribution of the fee rates of the pending transactions in the mempool.
+// The cumulative size of a bucket is the size of the transactions that pay at least its minimum fee rate,
+// which a wallet can compare against the block space it expects to confirm a transaction quickly.
+func (api *API) GetFeeHistogram(r *http.Request, args *GetFeeHistogramArgs, result *GetFeeHistogramResult) error {
+	log.Println("'GetFeeHistogram' Called")
+
+	edges := args.Edges
+	if len(edges) == 0 {
+		edges = core.DefaultFeeRateEdges
+	}
+
+	histogram, err := api.chain.Mempool().FeeHistogram(edges)
+	if err != nil {
+		return fmt.Errorf("failed to compute fee histogram: %w", err)
+	}
+
+	buckets := make([]FeeBucket, 0, len(histogram))
+	for _, bucket := range histogram {
+		converted := FeeBucket{
+			MinRate:        bucket.MinRate,
+			Count:          bucket.Count,
+			Size:           bucket.Size,
+			CumulativeSize: bucket.CumulativeSize,
+		}
+
+		if !math.IsInf(bucket.MaxRate, 1) {
+			rate := bucket.MaxRate
+			converted.MaxRate = &rate
+		}
+
+		buckets = append(buckets, converted)
+	}
+
+	*result = GetFeeHistogramResult{Buckets: buckets}
+	return nil
+}
@@ -0,0 +1,48 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/anee769/essensio/core"
+)
+
+// TestGetFeeHistogram checks that GetFeeHistogram counts every pending Transaction in the default buckets,
+// ordered from the highest fee rate, whose upper edge is omitted
+func TestGetFeeHistogram(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	spent := spendGenesis(t, api, core.TxOutput{Value: 50, PubKey: "alice"}, core.TxOutput{Value: 50, PubKey: "alice"})
+
+	size := 0
+	for idx, fee := range []int{1, 20} {
+		txn := signedTransaction(t, "alice", []core.Outpoint{{ID: spent.ID, Index: idx}}, core.TxOutput{Value: 50 - fee, PubKey: "bob"})
+		if err := api.chain.SubmitTransaction(txn); err != nil {
+			t.Fatalf("failed to submit transaction: %v", err)
+		}
+
+		size += txn.Size()
+	}
+
+	var result GetFeeHistogramResult
+	callResult(t, router, "API.GetFeeHistogram", GetFeeHistogramArgs{}, &result)
+
+	if len(result.Buckets) != len(core.DefaultFeeRateEdges) || result.Buckets[0].MaxRate != nil {
+		t.Fatalf("expected the default buckets from the highest fee rate, got %+v", result.Buckets)
+	}
+
+	count := 0
+	for idx, bucket := range result.Buckets {
+		count += bucket.Count
+
+		if idx > 0 && (bucket.MaxRate == nil || *bucket.MaxRate != result.Buckets[idx-1].MinRate) {
+			t.Fatalf("expected bucket %v to end at the minimum rate of the bucket above it, got %+v", idx, bucket)
+		}
+	}
+
+	if last := result.Buckets[len(result.Buckets)-1]; count != 2 || last.CumulativeSize != size {
+		t.Fatalf("expected 2 transactions with a cumulative size of %v, got %v and %v", size, count, last.CumulativeSize)
+	}
+
+	callError(t, router, "API.GetFeeHistogram", GetFeeHistogramArgs{Edges: []float64{1, 0}})
+}
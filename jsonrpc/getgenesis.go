@@ -0,0 +1,27 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+)
+
+type GetGenesisArgs struct{}
+
+type GetGenesisResult struct {
+	Block ChainBlock `json:"block"`
+}
+
+// GetGenesis returns the Genesis Block of the chain, resolved with the height index.
+// The Genesis Block never changes, so clients can cache the result to identify the chain.
+func (api *API) GetGenesis(r *http.Request, args *GetGenesisArgs, result *GetGenesisResult) error {
+	log.Println("'GetGenesis' Called")
+
+	genesis, err := api.chain.GetBlockByHeight(0)
+	if err != nil {
+		return fmt.Errorf("failed to get genesis block: %w", err)
+	}
+
+	*result = GetGenesisResult{Block: NewChainBlock(genesis)}
+	return nil
+}
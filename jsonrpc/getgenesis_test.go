@@ -0,0 +1,37 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestGetGenesis checks that GetGenesis returns the Genesis Block at height 0 with its coinbase Transaction,
+// and that it does not change as Blocks are added to the chain
+func TestGetGenesis(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	var genesis GetGenesisResult
+	callResult(t, router, "API.GetGenesis", GetGenesisArgs{}, &genesis)
+
+	block := genesis.Block
+	if block.Height != 0 || block.PrevBlockHash != common.NullHash().Hex() || block.TxnCount != 1 {
+		t.Fatalf("expected a genesis block at height 0 with a single transaction, got %+v", block)
+	}
+
+	if coinbase := block.BLockTransactions[0]; len(coinbase.Inputs) != 1 || coinbase.Inputs[0].Out != -1 {
+		t.Fatalf("expected the genesis transaction to be a coinbase, got %+v", coinbase)
+	}
+
+	if err := api.chain.MineBlock(nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	var again GetGenesisResult
+	callResult(t, router, "API.GetGenesis", GetGenesisArgs{}, &again)
+
+	if again.Block.BlockHash != block.BlockHash {
+		t.Fatalf("expected the genesis block to remain '%v', got '%v'", block.BlockHash, again.Block.BlockHash)
+	}
+}
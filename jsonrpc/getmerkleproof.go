@@ -0,0 +1,58 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/anee769/essensio/common"
+)
+
+type GetMerkleProofArgs struct {
+	TxID string `json:"txid"`
+}
+
+type GetMerkleProofResult struct {
+	BlockHash  string       `json:"block_hash"`
+	MerkleRoot string       `json:"merkle_root"`
+	Leaf       string       `json:"leaf"`
+	Index      int          `json:"index"`
+	Path       []MerkleStep `json:"path"`
+}
+
+type MerkleStep struct {
+	Hash string `json:"hash"`
+	Left bool   `json:"left"`
+}
+
+// GetMerkleProof returns the Merkle proof for the inclusion of a Transaction in its Block.
+// The leaf is the hash of the Transaction and the root is the summary of the Block header,
+// so clients can verify the proof with core.VerifyMerkleProof against a trusted header.
+func (api *API) GetMerkleProof(r *http.Request, args *GetMerkleProofArgs, result *GetMerkleProofResult) error {
+	log.Println("'GetMerkleProof' Called")
+
+	txid, err := common.HexToHash(args.TxID)
+	if err != nil {
+		return fmt.Errorf("invalid txid: %w", err)
+	}
+
+	block, index, proof, err := api.chain.GetMerkleProof(txid)
+	if err != nil {
+		return fmt.Errorf("transaction not found: %w", err)
+	}
+
+	path := make([]MerkleStep, len(proof))
+	for idx, step := range proof {
+		path[idx] = MerkleStep{Hash: step.Hash.Hex(), Left: step.Left}
+	}
+
+	*result = GetMerkleProofResult{
+		BlockHash:  block.BlockHash.Hex(),
+		MerkleRoot: block.Summary.Hex(),
+		Leaf:       block.BlockTxns[index].Hash().Hex(),
+		Index:      index,
+		Path:       path,
+	}
+
+	return nil
+}
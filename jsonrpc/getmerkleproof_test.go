@@ -0,0 +1,49 @@
+package jsonrpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+)
+
+// TestGetMerkleProof checks that the proof returned by GetMerkleProof verifies the inclusion of a Transaction
+// against the summary of the header of its Block
+func TestGetMerkleProof(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	txn := spendGenesis(t, api, core.TxOutput{Value: core.BlockReward, PubKey: "alice"})
+
+	header, err := api.chain.GetHeader(api.chain.Head)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result GetMerkleProofResult
+	callResult(t, router, "API.GetMerkleProof", GetMerkleProofArgs{TxID: txn.ID.Hex()}, &result)
+
+	if result.BlockHash != api.chain.Head.Hex() || result.MerkleRoot != header.Summary.Hex() || result.Index != 1 {
+		t.Fatalf("expected the proof of the spend at index 1 of the chain head, got %+v", result)
+	}
+
+	proof := make([]core.MerkleStep, len(result.Path))
+	for idx, step := range result.Path {
+		hash, err := common.HexToHash(step.Hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		proof[idx] = core.MerkleStep{Hash: hash, Left: step.Left}
+	}
+
+	if !core.VerifyMerkleProof(txn.Hash(), proof, header.Summary) || result.Leaf != txn.Hash().Hex() {
+		t.Fatalf("expected the proof to verify against the header summary")
+	}
+
+	message := callError(t, router, "API.GetMerkleProof", GetMerkleProofArgs{TxID: common.Hash256([]byte("unknown")).Hex()})
+	if !strings.Contains(message, "transaction not found") {
+		t.Fatalf("expected a not found error, got %q", message)
+	}
+}
@@ -0,0 +1,52 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/anee769/essensio/core"
+)
+
+type GetNetworkHashRateArgs struct {
+	// Number of the most recent blocks to estimate over, or core.DefaultHashRateBlocks if omitted
+	Blocks int64 `json:"blocks,omitempty"`
+}
+
+type GetNetworkHashRateResult struct {
+	// Estimated hash rate of the network in hashes per second
+	HashRate float64 `json:"hash_rate"`
+	// Number of blocks requested for the estimate
+	Blocks int64 `json:"blocks"`
+	// Height of the chain at which the hash rate was estimated
+	ChainHeight uint64 `json:"chain_height"`
+}
+
+// GetNetworkHashRate estimates the hash rate of the network over the most recent blocks,
+// as the work of the blocks divided by the time between their timestamps.
+func (api *API) GetNetworkHashRate(r *http.Request, args *GetNetworkHashRateArgs, result *GetNetworkHashRateResult) error {
+	log.Println("'GetNetworkHashRate' Called")
+
+	blocks := args.Blocks
+	if blocks < 0 {
+		return fmt.Errorf("invalid block count: %v", blocks)
+	}
+
+	if blocks == 0 {
+		blocks = core.DefaultHashRateBlocks
+	}
+
+	height := api.chain.Height
+	rate, err := api.chain.NetworkHashRate(blocks)
+	if err != nil {
+		return fmt.Errorf("failed to estimate hash rate: %w", err)
+	}
+
+	*result = GetNetworkHashRateResult{
+		HashRate:    rate,
+		Blocks:      blocks,
+		ChainHeight: toUint64(height),
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package jsonrpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/core"
+)
+
+// TestGetNetworkHashRate checks that GetNetworkHashRate returns the estimate of the chain over the requested
+// number of blocks, which defaults to core.DefaultHashRateBlocks, and rejects a negative block count
+func TestGetNetworkHashRate(t *testing.T) {
+	api := newTestAPI(t, 4)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	var result GetNetworkHashRateResult
+	callResult(t, router, "API.GetNetworkHashRate", GetNetworkHashRateArgs{}, &result)
+
+	want, err := api.chain.NetworkHashRate(core.DefaultHashRateBlocks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Blocks != core.DefaultHashRateBlocks || result.HashRate != want || result.ChainHeight != uint64(api.chain.Height) {
+		t.Fatalf("expected a hash rate of %v over %v blocks, got %+v", want, core.DefaultHashRateBlocks, result)
+	}
+
+	if err := callError(t, router, "API.GetNetworkHashRate", GetNetworkHashRateArgs{Blocks: -1}); !strings.Contains(err, "invalid block count") {
+		t.Fatalf("expected a negative block count to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,27 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+)
+
+type GetNetworkIDArgs struct{}
+
+type GetNetworkIDResult struct {
+	NetworkID string `json:"network_id"`
+}
+
+// GetNetworkID returns the identifier of the network of the chain, which is the hash of its Genesis Block.
+// Clients can compare it with the expected value to reject nodes of a different network.
+func (api *API) GetNetworkID(r *http.Request, args *GetNetworkIDArgs, result *GetNetworkIDResult) error {
+	log.Println("'GetNetworkID' Called")
+
+	id, err := api.chain.NetworkID()
+	if err != nil {
+		return fmt.Errorf("failed to get network id: %w", err)
+	}
+
+	*result = GetNetworkIDResult{NetworkID: id.Hex()}
+	return nil
+}
@@ -0,0 +1,21 @@
+package jsonrpc
+
+import "testing"
+
+// TestGetNetworkID checks that GetNetworkID returns the hash of the Genesis Block of the chain
+func TestGetNetworkID(t *testing.T) {
+	api := newTestAPI(t, 2)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	genesis, err := api.chain.GetBlockByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result GetNetworkIDResult
+	callResult(t, router, "API.GetNetworkID", GetNetworkIDArgs{}, &result)
+
+	if result.NetworkID != genesis.BlockHash.Hex() {
+		t.Fatalf("expected network id '%v', got '%v'", genesis.BlockHash.Hex(), result.NetworkID)
+	}
+}
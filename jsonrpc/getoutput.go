@@ -0,0 +1,50 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+)
+
+type GetOutputArgs struct {
+	TxID  string `json:"txid"`
+	Index int    `json:"index"`
+}
+
+type GetOutputResult struct {
+	Value   Value  `json:"value"`
+	Owner   string `json:"owner"`
+	Unspent bool   `json:"unspent"`
+}
+
+func (api *API) GetOutput(r *http.Request, args *GetOutputArgs, result *GetOutputResult) error {
+	log.Println("'GetOutput' Called")
+
+	txid, err := common.HexToHash(args.TxID)
+	if err != nil {
+		return fmt.Errorf("invalid txid: %w", err)
+	}
+
+	outpoint := core.Outpoint{ID: txid, Index: args.Index}
+
+	output, err := api.chain.FindOutput(outpoint)
+	if err != nil {
+		return fmt.Errorf("output not found: %w", err)
+	}
+
+	unspent, err := api.chain.IsUnspent(outpoint)
+	if err != nil {
+		return fmt.Errorf("failed to check output: %w", err)
+	}
+
+	*result = GetOutputResult{
+		Value:   NewValue(output.Value),
+		Owner:   string(output.PubKey),
+		Unspent: unspent,
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package jsonrpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+)
+
+// TestGetOutput checks that GetOutput reports the value, owner and spent status of an unspent
+// and a spent output, and returns a not found error for a nonexistent output
+func TestGetOutput(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	genesis, err := api.chain.GetBlockByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spend := spendGenesis(t, api, core.TxOutput{Value: 60, PubKey: "alice"}, core.TxOutput{Value: 40, PubKey: common.MinerAddress()})
+
+	var unspent GetOutputResult
+	callResult(t, router, "API.GetOutput", GetOutputArgs{TxID: spend.ID.Hex(), Index: 0}, &unspent)
+
+	if unspent != (GetOutputResult{Value: NewValue(60), Owner: "alice", Unspent: true}) {
+		t.Fatalf("expected an unspent output of 60 to alice, got %+v", unspent)
+	}
+
+	var spent GetOutputResult
+	callResult(t, router, "API.GetOutput", GetOutputArgs{TxID: genesis.BlockTxns[0].ID.Hex(), Index: 0}, &spent)
+
+	if spent != (GetOutputResult{Value: NewValue(core.BlockReward), Owner: string(common.MinerAddress()), Unspent: false}) {
+		t.Fatalf("expected the spent genesis output of the miner, got %+v", spent)
+	}
+
+	for _, args := range []GetOutputArgs{{TxID: spend.ID.Hex(), Index: 2}, {TxID: common.Hash256([]byte("missing")).Hex(), Index: 0}} {
+		if err := callError(t, router, "API.GetOutput", args); !strings.Contains(err, "output not found") {
+			t.Fatalf("expected a not found error for %+v, got %v", args, err)
+		}
+	}
+}
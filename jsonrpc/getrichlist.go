@@ -0,0 +1,68 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+)
+
+// DefaultRichListLimit is the number of entries returned by GetRichList if no limit is given
+const DefaultRichListLimit = 100
+
+type GetRichListArgs struct {
+	Limit int `json:"limit"`
+}
+
+type GetRichListResult struct {
+	Entries []RichListEntry `json:"entries"`
+}
+
+type RichListEntry struct {
+	Address string `json:"address"`
+	Balance Value  `json:"balance"`
+}
+
+// GetRichList returns the addresses with the highest balances in descending order of balance
+func (api *API) GetRichList(r *http.Request, args *GetRichListArgs, result *GetRichListResult) error {
+	log.Println("'GetRichList' Called")
+
+	limit := args.Limit
+	if limit < 0 {
+		return fmt.Errorf("invalid limit: %v", limit)
+	}
+
+	if limit == 0 {
+		limit = DefaultRichListLimit
+	}
+
+	balances, err := api.chain.AllBalancesContext(r.Context())
+	if err != nil {
+		if ctxErr := checkContext(r); ctxErr != nil {
+			return ctxErr
+		}
+
+		return fmt.Errorf("failed to get balances: %w", err)
+	}
+
+	entries := make([]RichListEntry, 0, len(balances))
+	for address, balance := range balances {
+		entries = append(entries, RichListEntry{string(address), NewValue(balance)})
+	}
+
+	// Sort by descending balance, with ties broken by address for a stable order
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Balance.Int() != entries[j].Balance.Int() {
+			return entries[i].Balance.Int() > entries[j].Balance.Int()
+		}
+
+		return entries[i].Address < entries[j].Address
+	})
+
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	*result = GetRichListResult{Entries: entries}
+	return nil
+}
@@ -0,0 +1,48 @@
+package jsonrpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+)
+
+// TestGetRichList checks that GetRichList returns the addresses with the highest balances in descending order,
+// up to its limit, with ties broken by address
+func TestGetRichList(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	// The miner is paid the coinbase of the Block of the spend
+	spendGenesis(t, api, core.TxOutput{Value: 60, PubKey: "alice"}, core.TxOutput{Value: 20, PubKey: "carol"}, core.TxOutput{Value: 20, PubKey: "bob"})
+
+	var result GetRichListResult
+	callResult(t, router, "API.GetRichList", GetRichListArgs{}, &result)
+
+	want := []RichListEntry{
+		{string(common.MinerAddress()), NewValue(core.BlockReward)},
+		{"alice", NewValue(60)},
+		{"bob", NewValue(20)},
+		{"carol", NewValue(20)},
+	}
+
+	if len(result.Entries) != len(want) {
+		t.Fatalf("expected entries %v, got %v", want, result.Entries)
+	}
+
+	for idx := range want {
+		if result.Entries[idx] != want[idx] {
+			t.Fatalf("expected entries %v, got %v", want, result.Entries)
+		}
+	}
+
+	callResult(t, router, "API.GetRichList", GetRichListArgs{Limit: 2}, &result)
+	if len(result.Entries) != 2 || result.Entries[1] != want[1] {
+		t.Fatalf("expected the top 2 entries, got %v", result.Entries)
+	}
+
+	if message := callError(t, router, "API.GetRichList", GetRichListArgs{Limit: -1}); !strings.Contains(message, "invalid limit") {
+		t.Fatalf("expected an invalid limit error, got %q", message)
+	}
+}
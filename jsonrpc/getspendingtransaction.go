@@ -0,0 +1,72 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+)
+
+type GetSpendingTransactionArgs struct {
+	TxID  string `json:"txid"`
+	Index int    `json:"index"`
+}
+
+type GetSpendingTransactionResult struct {
+	Spent       bool   `json:"spent"`
+	SpenderID   string `json:"spender_txid,omitempty"`
+	BlockHeight uint64 `json:"block_height,omitempty"`
+}
+
+// GetSpendingTransaction returns the Transaction that spent an output and the height of its Block.
+// If the output is still unspent, the result reports it as not spent.
+func (api *API) GetSpendingTransaction(r *http.Request, args *GetSpendingTransactionArgs, result *GetSpendingTransactionResult) error {
+	log.Println("'GetSpendingTransaction' Called")
+
+	txid, err := common.HexToHash(args.TxID)
+	if err != nil {
+		return fmt.Errorf("invalid txid: %w", err)
+	}
+
+	outpoint := core.Outpoint{ID: txid, Index: args.Index}
+
+	// Check that the output exists on the chain
+	if _, err := api.chain.FindOutput(outpoint); err != nil {
+		return fmt.Errorf("output not found: %w", err)
+	}
+
+	spender, spent, err := api.chain.FindSpender(outpoint)
+	if err != nil {
+		return fmt.Errorf("failed to find spender: %w", err)
+	}
+
+	if !spent {
+		// An output that is neither indexed as spent nor in the UTXO set was spent before the spent index existed
+		unspent, err := api.chain.IsUnspent(outpoint)
+		if err != nil {
+			return fmt.Errorf("failed to check output: %w", err)
+		}
+
+		if !unspent {
+			return fmt.Errorf("output is spent but its spender is not indexed")
+		}
+
+		*result = GetSpendingTransactionResult{Spent: false}
+		return nil
+	}
+
+	block, err := api.chain.FindTransactionBlock(spender)
+	if err != nil {
+		return fmt.Errorf("failed to find spender block: %w", err)
+	}
+
+	*result = GetSpendingTransactionResult{
+		Spent:       true,
+		SpenderID:   spender.Hex(),
+		BlockHeight: toUint64(block.BlockHeight),
+	}
+
+	return nil
+}
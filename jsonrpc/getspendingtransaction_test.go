@@ -0,0 +1,41 @@
+package jsonrpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/core"
+)
+
+// TestGetSpendingTransaction checks that GetSpendingTransaction returns the spender of a spent output
+// and the height of its Block, and reports an unspent output as not spent
+func TestGetSpendingTransaction(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	genesis, err := api.chain.GetBlockByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spend := spendGenesis(t, api, core.TxOutput{Value: core.BlockReward, PubKey: "alice"})
+
+	var result GetSpendingTransactionResult
+	callResult(t, router, "API.GetSpendingTransaction", GetSpendingTransactionArgs{TxID: genesis.BlockTxns[0].ID.Hex(), Index: 0}, &result)
+
+	if want := (GetSpendingTransactionResult{Spent: true, SpenderID: spend.ID.Hex(), BlockHeight: 1}); result != want {
+		t.Fatalf("expected the genesis output to be spent by %+v, got %+v", want, result)
+	}
+
+	var unspent GetSpendingTransactionResult
+	callResult(t, router, "API.GetSpendingTransaction", GetSpendingTransactionArgs{TxID: spend.ID.Hex(), Index: 0}, &unspent)
+
+	if unspent != (GetSpendingTransactionResult{}) {
+		t.Fatalf("expected the output of the spend to be unspent, got %+v", unspent)
+	}
+
+	message := callError(t, router, "API.GetSpendingTransaction", GetSpendingTransactionArgs{TxID: spend.ID.Hex(), Index: 1})
+	if !strings.Contains(message, "output not found") {
+		t.Fatalf("expected an unknown output to be rejected, got %q", message)
+	}
+}
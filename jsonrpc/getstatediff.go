@@ -0,0 +1,55 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// MaxStateDiffBlocks is the maximum number of blocks between the heights of a GetStateDiff request
+const MaxStateDiffBlocks = 10000
+
+type GetStateDiffArgs struct {
+	FromHeight int64 `json:"from_height"`
+	ToHeight   int64 `json:"to_height"`
+}
+
+type GetStateDiffResult struct {
+	FromHeight int64 `json:"from_height"`
+	ToHeight   int64 `json:"to_height"`
+	// Net change in balance of each address whose balance changed between the heights
+	Changes map[string]Value `json:"changes"`
+}
+
+// GetStateDiff returns the net change in balance of every address between the Blocks at two heights,
+// from the balances after the Block at the first height to those after the Block at the second height.
+// The heights may be at most MaxStateDiffBlocks apart, as the changes are summed over every Block between them.
+func (api *API) GetStateDiff(r *http.Request, args *GetStateDiffArgs, result *GetStateDiffResult) error {
+	log.Println("'GetStateDiff' Called")
+
+	if args.FromHeight > args.ToHeight {
+		return fmt.Errorf("from_height %v is above to_height %v", args.FromHeight, args.ToHeight)
+	}
+
+	if span := args.ToHeight - args.FromHeight; span > MaxStateDiffBlocks {
+		return fmt.Errorf("range of %v blocks exceeds the maximum of %v blocks", span, MaxStateDiffBlocks)
+	}
+
+	diff, err := api.chain.StateDiff(args.FromHeight, args.ToHeight)
+	if err != nil {
+		return fmt.Errorf("failed to compute state diff: %w", err)
+	}
+
+	changes := make(map[string]Value, len(diff))
+	for address, delta := range diff {
+		changes[string(address)] = NewValue(delta)
+	}
+
+	*result = GetStateDiffResult{
+		FromHeight: args.FromHeight,
+		ToHeight:   args.ToHeight,
+		Changes:    changes,
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package jsonrpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+)
+
+// TestGetStateDiff checks that GetStateDiff returns the net balance changes between two heights,
+// omitting unchanged balances, and rejects reversed and over-long ranges
+func TestGetStateDiff(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	// The miner spends the genesis reward and is paid the coinbase of the Block of the spend, so its balance is unchanged
+	spendGenesis(t, api, core.TxOutput{Value: 60, PubKey: "alice"}, core.TxOutput{Value: 40, PubKey: "bob"})
+
+	var result GetStateDiffResult
+	callResult(t, router, "API.GetStateDiff", GetStateDiffArgs{FromHeight: 0, ToHeight: 1}, &result)
+
+	want := map[string]Value{"alice": NewValue(60), "bob": NewValue(40)}
+	if result.FromHeight != 0 || result.ToHeight != 1 || len(result.Changes) != len(want) {
+		t.Fatalf("expected changes %v from 0 to 1, got %+v", want, result)
+	}
+
+	for address, value := range want {
+		if result.Changes[address] != value {
+			t.Fatalf("expected changes %v, got %v", want, result.Changes)
+		}
+	}
+
+	if _, exists := result.Changes[string(common.MinerAddress())]; exists {
+		t.Fatalf("expected the unchanged balance of the miner to be omitted, got %v", result.Changes)
+	}
+
+	var empty GetStateDiffResult
+	callResult(t, router, "API.GetStateDiff", GetStateDiffArgs{FromHeight: 1, ToHeight: 1}, &empty)
+	if len(empty.Changes) != 0 {
+		t.Fatalf("expected no changes for an empty range, got %v", empty.Changes)
+	}
+
+	tests := []struct {
+		name    string
+		args    GetStateDiffArgs
+		message string
+	}{
+		{"reversed", GetStateDiffArgs{FromHeight: 1, ToHeight: 0}, "is above to_height"},
+		{"over-long", GetStateDiffArgs{FromHeight: 0, ToHeight: MaxStateDiffBlocks + 1}, "exceeds the maximum"},
+		{"above head", GetStateDiffArgs{FromHeight: 0, ToHeight: 2}, "failed to compute state diff"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if message := callError(t, router, "API.GetStateDiff", test.args); !strings.Contains(message, test.message) {
+				t.Fatalf("expected an error containing %q, got %q", test.message, message)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package jsonrpc
+
+import (
+	"log"
+	"net/http"
+)
+
+type GetStatsArgs struct{}
+
+type GetStatsResult struct {
+	TotalBlocks       uint64  `json:"total_blocks"`
+	TotalTransactions uint64  `json:"total_transactions"`
+	AvgTxnsPerBlock   float64 `json:"avg_txns_per_block"`
+	MempoolSize       int     `json:"mempool_size"`
+	TotalSupply       Value   `json:"total_supply"`
+}
+
+func (api *API) GetStats(r *http.Request, args *GetStatsArgs, result *GetStatsResult) error {
+	log.Println("'GetStats' Called")
+
+	*result = GetStatsResult{
+		TotalBlocks:       toUint64(api.chain.Height),
+		TotalTransactions: toUint64(api.chain.TxnCount),
+		MempoolSize:       api.chain.Mempool().Size(),
+		TotalSupply:       NewValue(api.chain.Supply),
+	}
+
+	if api.chain.Height > 0 {
+		result.AvgTxnsPerBlock = float64(api.chain.TxnCount) / float64(api.chain.Height)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/anee769/essensio/core"
+)
+
+// TestGetStats checks the block, transaction, mempool and supply counts of GetStats after Blocks
+// with known transaction counts are added to the chain
+func TestGetStats(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	// The genesis Block and an empty Block each have a coinbase, and the Block of the spend has two Transactions
+	spent := spendGenesis(t, api, core.TxOutput{Value: 50, PubKey: "alice"}, core.TxOutput{Value: 50, PubKey: "bob"})
+	if err := api.chain.MineBlock(nil, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	pending := signedTransaction(t, "alice", []core.Outpoint{{ID: spent.ID, Index: 0}}, core.TxOutput{Value: 50, PubKey: "carol"})
+	if err := api.chain.SubmitTransaction(pending); err != nil {
+		t.Fatalf("failed to submit transaction: %v", err)
+	}
+
+	var result GetStatsResult
+	callResult(t, router, "API.GetStats", GetStatsArgs{}, &result)
+
+	want := GetStatsResult{
+		TotalBlocks:       3,
+		TotalTransactions: 4,
+		AvgTxnsPerBlock:   4.0 / 3.0,
+		MempoolSize:       1,
+		TotalSupply:       NewValue(3 * core.BlockReward),
+	}
+
+	if result != want {
+		t.Fatalf("expected stats %+v, got %+v", want, result)
+	}
+}
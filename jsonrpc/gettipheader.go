@@ -0,0 +1,46 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+)
+
+type GetTipHeaderArgs struct{}
+
+type GetTipHeaderResult struct {
+	Height    uint64 `json:"height"`
+	BlockHash string `json:"block_hash"`
+	Priori    string `json:"priori"`
+	Summary   string `json:"summary"`
+	// Unix timestamp of the block, in seconds
+	Timestamp int64  `json:"timestamp"`
+	Nonce     uint64 `json:"nonce"`
+	// Proof of work target of the block, in its compact representation and in full
+	Bits   uint32 `json:"bits"`
+	Target string `json:"target"`
+}
+
+// GetTipHeader returns the header of the Block at the chain head, which the next Block must build on.
+// The header is read from the cached chain tip, without the Transactions of the Block.
+func (api *API) GetTipHeader(r *http.Request, args *GetTipHeaderArgs, result *GetTipHeaderResult) error {
+	log.Println("'GetTipHeader' Called")
+
+	tip, err := api.chain.Tip()
+	if err != nil {
+		return fmt.Errorf("failed to get chain tip: %w", err)
+	}
+
+	*result = GetTipHeaderResult{
+		Height:    toUint64(tip.BlockHeight),
+		BlockHash: tip.BlockHash.Hex(),
+		Priori:    tip.Priori.Hex(),
+		Summary:   tip.Summary.Hex(),
+		Timestamp: tip.Timestamp,
+		Nonce:     toUint64(tip.Nonce),
+		Bits:      tip.Bits,
+		Target:    "0x" + tip.Target().Text(16),
+	}
+
+	return nil
+}
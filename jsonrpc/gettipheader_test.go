@@ -0,0 +1,39 @@
+package jsonrpc
+
+import (
+	"testing"
+)
+
+// TestGetTipHeader checks that GetTipHeader returns the header of the most recently added block
+func TestGetTipHeader(t *testing.T) {
+	api := newTestAPI(t, 2)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	for idx := 0; idx < 2; idx++ {
+		var mined MineBlockResult
+		callResult(t, router, "API.MineBlock", MineBlockArgs{}, &mined)
+
+		block, err := api.chain.GetBlock(api.chain.Head)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var result GetTipHeaderResult
+		callResult(t, router, "API.GetTipHeader", GetTipHeaderArgs{}, &result)
+
+		want := GetTipHeaderResult{
+			Height:    toUint64(block.BlockHeight),
+			BlockHash: block.BlockHash.Hex(),
+			Priori:    block.Priori.Hex(),
+			Summary:   block.Summary.Hex(),
+			Timestamp: block.Timestamp,
+			Nonce:     toUint64(block.Nonce),
+			Bits:      block.Bits,
+			Target:    "0x" + block.Target().Text(16),
+		}
+
+		if result != want || result.BlockHash != mined.BlockHash {
+			t.Fatalf("expected the header of the mined block %+v, got %+v", want, result)
+		}
+	}
+}
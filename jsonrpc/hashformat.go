@@ -0,0 +1,36 @@
+package jsonrpc
+
+import (
+	"fmt"
+
+	"github.com/anee769/essensio/common"
+)
+
+// HashFormat is the format of the hashes in an RPC result
+type HashFormat string
+
+const (
+	// HashFormatPrefixed formats hashes as 0x prefixed hex strings. It is the default format.
+	HashFormatPrefixed HashFormat = "0x"
+	// HashFormatBare formats hashes as hex strings without the 0x prefix
+	HashFormatBare HashFormat = "bare"
+)
+
+// check returns an error if the HashFormat is not known. An empty HashFormat is HashFormatPrefixed.
+func (format HashFormat) check() error {
+	switch format {
+	case "", HashFormatPrefixed, HashFormatBare:
+		return nil
+	default:
+		return fmt.Errorf("unknown hash format '%v'", format)
+	}
+}
+
+// encode returns a Hash formatted with the HashFormat
+func (format HashFormat) encode(hash common.Hash) string {
+	if format == HashFormatBare {
+		return hash.BareHex()
+	}
+
+	return hash.Hex()
+}
@@ -0,0 +1,48 @@
+package jsonrpc
+
+import (
+	"regexp"
+	"testing"
+)
+
+// TestShowChainHashFormat checks that every hash field of a ShowChain response is formatted with the requested
+// HashFormat, and that unknown formats are rejected
+func TestShowChainHashFormat(t *testing.T) {
+	api := newTestAPI(t, 2)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	tests := []struct {
+		format  HashFormat
+		pattern *regexp.Regexp
+	}{
+		{"", regexp.MustCompile(`^0x[0-9a-f]{64}$`)},
+		{HashFormatPrefixed, regexp.MustCompile(`^0x[0-9a-f]{64}$`)},
+		{HashFormatBare, regexp.MustCompile(`^[0-9a-f]{64}$`)},
+	}
+
+	for _, test := range tests {
+		var result ShowChainResult
+		callResult(t, router, "API.ShowChain", ShowChainArgs{HashFormat: test.format}, &result)
+
+		hashes := []string{result.ChainHead}
+		for _, block := range result.Blocks {
+			hashes = append(hashes, block.BlockHash, block.PrevBlockHash)
+
+			for _, txn := range block.BLockTransactions {
+				hashes = append(hashes, txn.TxID)
+
+				for _, input := range txn.Inputs {
+					hashes = append(hashes, input.TxID)
+				}
+			}
+		}
+
+		for _, hash := range hashes {
+			if !test.pattern.MatchString(hash) {
+				t.Fatalf("expected hash %q to match the format %q", hash, test.format)
+			}
+		}
+	}
+
+	callError(t, router, "API.ShowChain", ShowChainArgs{HashFormat: "base64"})
+}
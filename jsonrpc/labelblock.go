@@ -0,0 +1,41 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/anee769/essensio/common"
+)
+
+type LabelBlockArgs struct {
+	Hash  string `json:"hash"`
+	Label string `json:"label"`
+}
+
+type LabelBlockResult struct {
+	Hash  string `json:"hash"`
+	Label string `json:"label"`
+}
+
+// LabelBlock assigns a label to the Block with the given hash, so that it can be retrieved with GetBlockByLabel.
+// An existing label is moved to the Block.
+func (api *API) LabelBlock(r *http.Request, args *LabelBlockArgs, result *LabelBlockResult) error {
+	log.Println("'LabelBlock' Called")
+
+	hash, err := common.HexToHash(args.Hash)
+	if err != nil {
+		return fmt.Errorf("invalid block hash: %w", err)
+	}
+
+	if err := checkContext(r); err != nil {
+		return err
+	}
+
+	if err := api.chain.LabelBlock(hash, args.Label); err != nil {
+		return fmt.Errorf("failed to label block: %w", err)
+	}
+
+	*result = LabelBlockResult{Hash: hash.Hex(), Label: args.Label}
+	return nil
+}
@@ -0,0 +1,30 @@
+package jsonrpc
+
+import "testing"
+
+// TestLabelBlock checks that a Block labeled with LabelBlock is returned by GetBlockByLabel
+func TestLabelBlock(t *testing.T) {
+	api := newTestAPI(t, 2)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	hash, err := api.chain.BlockHashAtHeight(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var labeled LabelBlockResult
+	callResult(t, router, "API.LabelBlock", LabelBlockArgs{Hash: hash.Hex(), Label: "pre-upgrade"}, &labeled)
+
+	if labeled.Hash != hash.Hex() || labeled.Label != "pre-upgrade" {
+		t.Fatalf("expected block '%v' to be labeled, got %+v", hash.Hex(), labeled)
+	}
+
+	var result GetBlockByLabelResult
+	callResult(t, router, "API.GetBlockByLabel", GetBlockByLabelArgs{Label: "pre-upgrade"}, &result)
+
+	if result.Block.BlockHash != hash.Hex() || result.Block.Height != 1 {
+		t.Fatalf("expected the labeled block '%v', got %+v", hash.Hex(), result.Block)
+	}
+
+	callError(t, router, "API.GetBlockByLabel", GetBlockByLabelArgs{Label: "unknown"})
+}
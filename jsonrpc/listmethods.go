@@ -0,0 +1,110 @@
+package jsonrpc
+
+import (
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type ListMethodsArgs struct{}
+
+type ListMethodsResult struct {
+	Methods []MethodInfo `json:"methods"`
+}
+
+type MethodInfo struct {
+	Name   string   `json:"name"`
+	Args   []string `json:"args"`
+	Result []string `json:"result"`
+}
+
+var (
+	typeOfRequest = reflect.TypeOf((*http.Request)(nil))
+	typeOfError   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// ListMethods returns the JSON-RPC methods of the API, sorted by name,
+// along with the JSON field names of their arguments and results.
+func (api *API) ListMethods(r *http.Request, args *ListMethodsArgs, result *ListMethodsResult) error {
+	log.Println("'ListMethods' Called")
+
+	*result = ListMethodsResult{Methods: listMethods(reflect.TypeOf(api))}
+	return nil
+}
+
+// listMethods returns the MethodInfo for all methods of the service type that are served as JSON-RPC methods.
+// A method is served if it has the signature func(*http.Request, *Args, *Result) error.
+func listMethods(service reflect.Type) []MethodInfo {
+	name := service.Elem().Name()
+	methods := make([]MethodInfo, 0, service.NumMethod())
+
+	for idx := 0; idx < service.NumMethod(); idx++ {
+		method := service.Method(idx)
+		mtype := method.Type
+
+		// The receiver is the first input of the method type
+		if mtype.NumIn() != 4 || mtype.NumOut() != 1 || mtype.Out(0) != typeOfError {
+			continue
+		}
+
+		if mtype.In(1) != typeOfRequest || mtype.In(2).Kind() != reflect.Ptr || mtype.In(3).Kind() != reflect.Ptr {
+			continue
+		}
+
+		methods = append(methods, MethodInfo{
+			Name:   name + "." + method.Name,
+			Args:   fieldNames(mtype.In(2).Elem()),
+			Result: fieldNames(mtype.In(3).Elem()),
+		})
+	}
+
+	sort.Slice(methods, func(i, j int) bool { return methods[i].Name < methods[j].Name })
+	return methods
+}
+
+// fieldNames returns the JSON field names of a struct type.
+// Like encoding/json, the fields of an embedded struct without a JSON name are flattened into the struct.
+func fieldNames(object reflect.Type) []string {
+	names := make([]string, 0)
+	if object.Kind() != reflect.Struct {
+		return names
+	}
+
+	for idx := 0; idx < object.NumField(); idx++ {
+		field := object.Field(idx)
+
+		tag := ""
+		if value, ok := field.Tag.Lookup("json"); ok {
+			if tag = strings.Split(value, ",")[0]; tag == "-" {
+				continue
+			}
+		}
+
+		if field.Anonymous && tag == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				names = append(names, fieldNames(embedded)...)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag != "" {
+			name = tag
+		}
+
+		names = append(names, name)
+	}
+
+	return names
+}
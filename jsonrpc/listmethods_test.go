@@ -0,0 +1,52 @@
+package jsonrpc
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestListMethods checks that ListMethods returns the known methods of the API in order of name,
+// with the JSON field names of their arguments and results
+func TestListMethods(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	var result ListMethodsResult
+	callResult(t, router, "API.ListMethods", ListMethodsArgs{}, &result)
+
+	methods := make(map[string]MethodInfo, len(result.Methods))
+	for _, method := range result.Methods {
+		methods[method.Name] = method
+	}
+
+	for _, name := range []string{"API.AddBlock", "API.ShowChain", "API.GetBalance", "API.SendTransaction", "API.ListMethods"} {
+		if _, exists := methods[name]; !exists {
+			t.Fatalf("expected method %v in the list", name)
+		}
+	}
+
+	if !sort.SliceIsSorted(result.Methods, func(i, j int) bool { return result.Methods[i].Name < result.Methods[j].Name }) {
+		t.Fatalf("expected the methods to be sorted by name")
+	}
+
+	// Methods without the signature of a JSON-RPC method are not listed
+	if _, exists := methods["API.Stop"]; exists {
+		t.Fatalf("expected Stop not to be listed")
+	}
+
+	balance := methods["API.GetBalance"]
+	if strings.Join(balance.Args, ",") != "address,height,min_height" || strings.Join(balance.Result, ",") != "address,balance" {
+		t.Fatalf("expected the fields of GetBalance, got %v and %v", balance.Args, balance.Result)
+	}
+
+	if block := methods["API.AddBlock"]; strings.Join(block.Result, ",") != "block_height,block_hash" {
+		t.Fatalf("expected the result fields of AddBlock, got %v", block.Result)
+	}
+
+	// The fields of the embedded TransactionInput are flattened into the arguments of SendTransaction
+	want := "to,from,value,change,min_confirmations,consolidate,lock_height,lock_time"
+	if send := methods["API.SendTransaction"]; strings.Join(send.Args, ",") != want {
+		t.Fatalf("expected the argument fields of SendTransaction to be %v, got %v", want, send.Args)
+	}
+}
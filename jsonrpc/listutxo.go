@@ -0,0 +1,54 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/anee769/essensio/common"
+)
+
+type ListUTXOArgs struct {
+	Address string `json:"address"`
+}
+
+type ListUTXOResult struct {
+	Address string       `json:"address"`
+	UTXOs   []ListedUTXO `json:"utxos"`
+}
+
+type ListedUTXO struct {
+	Outpoint      string `json:"outpoint"`
+	Value         Value  `json:"value"`
+	Height        uint64 `json:"height"`
+	Confirmations uint64 `json:"confirmations"`
+	Coinbase      bool   `json:"coinbase"`
+	Mature        bool   `json:"mature"`
+}
+
+// ListUTXO returns the unspent outputs owned by an address.
+// Each output reports whether it is mature, as immature coinbase outputs cannot be spent yet.
+func (api *API) ListUTXO(r *http.Request, args *ListUTXOArgs, result *ListUTXOResult) error {
+	log.Println("'ListUTXO' Called")
+
+	address := common.NormalizeAddress(args.Address)
+	listed, err := api.chain.ListUTXO(address)
+	if err != nil {
+		return fmt.Errorf("failed to list unspent outputs: %w", err)
+	}
+
+	utxos := make([]ListedUTXO, 0, len(listed))
+	for _, utxo := range listed {
+		utxos = append(utxos, ListedUTXO{
+			Outpoint:      utxo.Outpoint.String(),
+			Value:         NewValue(utxo.Output.Value),
+			Height:        toUint64(utxo.Height),
+			Confirmations: toUint64(utxo.Confirmations),
+			Coinbase:      utxo.Coinbase,
+			Mature:        utxo.Mature,
+		})
+	}
+
+	*result = ListUTXOResult{Address: string(address), UTXOs: utxos}
+	return nil
+}
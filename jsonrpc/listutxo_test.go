@@ -0,0 +1,34 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestListUTXO checks that ListUTXO flags a recent coinbase output as immature and an old one as mature
+func TestListUTXO(t *testing.T) {
+	opts := testChainOptions(0)
+	opts.Options.CoinbaseMaturity = 2
+
+	api := newTestAPIOptions(t, opts)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	if err := api.chain.MineBlock(nil, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	var result ListUTXOResult
+	callResult(t, router, "API.ListUTXO", ListUTXOArgs{Address: string(common.MinerAddress())}, &result)
+
+	if len(result.UTXOs) != 2 {
+		t.Fatalf("expected 2 unspent outputs, got %+v", result.UTXOs)
+	}
+
+	for _, utxo := range result.UTXOs {
+		// The genesis output has 2 confirmations and the output of the chain head has 1
+		if want := utxo.Height == 0; !utxo.Coinbase || utxo.Mature != want || utxo.Confirmations != 2-utxo.Height {
+			t.Fatalf("expected the coinbase output at height %v to be mature: %v, got %+v", utxo.Height, want, utxo)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+)
+
+type DumpMempoolArgs struct{}
+
+type DumpMempoolResult struct {
+	// Hex encoded serialized Transactions in order of arrival
+	Transactions []string `json:"transactions"`
+}
+
+// DumpMempool returns all the pending transactions in the mempool in their serialized form
+func (api *API) DumpMempool(r *http.Request, args *DumpMempoolArgs, result *DumpMempoolResult) error {
+	log.Println("'DumpMempool' Called")
+
+	txns := api.chain.Mempool().Transactions()
+
+	dump := make([]string, 0, len(txns))
+	for _, txn := range txns {
+		encoded, err := encodeTransaction(txn)
+		if err != nil {
+			return err
+		}
+
+		dump = append(dump, encoded)
+	}
+
+	*result = DumpMempoolResult{Transactions: dump}
+	return nil
+}
+
+type LoadMempoolArgs struct {
+	// Hex encoded serialized Transactions, as returned by DumpMempool
+	Transactions []string `json:"transactions"`
+}
+
+type LoadMempoolResult struct {
+	Loaded  int      `json:"loaded"`
+	Skipped int      `json:"skipped"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// LoadMempool validates and adds serialized transactions to the mempool.
+// Invalid transactions are skipped and reported without aborting the load.
+func (api *API) LoadMempool(r *http.Request, args *LoadMempoolArgs, result *LoadMempoolResult) error {
+	log.Println("'LoadMempool' Called")
+
+	var loaded LoadMempoolResult
+	for idx, encoded := range args.Transactions {
+		// Transactions that were already loaded remain in the mempool when the request times out
+		if err := checkContext(r); err != nil {
+			return fmt.Errorf("%w after loading %v of %v transactions", err, loaded.Loaded, len(args.Transactions))
+		}
+
+		txn, err := decodeTransaction(encoded)
+		if err == nil {
+			err = api.chain.SubmitTransaction(txn)
+		}
+
+		if err != nil {
+			loaded.Skipped++
+			loaded.Reasons = append(loaded.Reasons, fmt.Sprintf("transaction %v: %v", idx, err))
+			continue
+		}
+
+		loaded.Loaded++
+	}
+
+	*result = loaded
+	return nil
+}
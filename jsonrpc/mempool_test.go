@@ -0,0 +1,58 @@
+package jsonrpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+)
+
+// TestDumpLoadMempool checks that a mempool dumped by DumpMempool is reloaded by LoadMempool,
+// which skips and reports the invalid transactions of the load
+func TestDumpLoadMempool(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	spent := spendGenesis(t, api, core.TxOutput{Value: 50, PubKey: "alice"}, core.TxOutput{Value: 50, PubKey: "bob"})
+
+	var ids []common.Hash
+	for idx, from := range []common.Address{"alice", "bob"} {
+		txn := signedTransaction(t, from, []core.Outpoint{{ID: spent.ID, Index: idx}}, core.TxOutput{Value: 50, PubKey: "carol"})
+		if err := api.chain.SubmitTransaction(txn); err != nil {
+			t.Fatalf("failed to submit transaction: %v", err)
+		}
+
+		ids = append(ids, txn.ID)
+	}
+
+	var dump DumpMempoolResult
+	callResult(t, router, "API.DumpMempool", DumpMempoolArgs{}, &dump)
+
+	if len(dump.Transactions) != 2 {
+		t.Fatalf("expected 2 dumped transactions, got %v", len(dump.Transactions))
+	}
+
+	api.chain.Mempool().Remove(ids...)
+
+	// The transactions of the dump are followed by a malformed transaction and a duplicate of the first
+	args := LoadMempoolArgs{Transactions: append(dump.Transactions, "not hex", dump.Transactions[0])}
+
+	var loaded LoadMempoolResult
+	callResult(t, router, "API.LoadMempool", args, &loaded)
+
+	if loaded.Loaded != 2 || loaded.Skipped != 2 || len(loaded.Reasons) != 2 {
+		t.Fatalf("expected 2 loaded and 2 skipped transactions, got %+v", loaded)
+	}
+
+	if !strings.HasPrefix(loaded.Reasons[0], "transaction 2:") || !strings.HasPrefix(loaded.Reasons[1], "transaction 3:") {
+		t.Fatalf("expected the skipped transactions to be reported by their index, got %v", loaded.Reasons)
+	}
+
+	var reloaded DumpMempoolResult
+	callResult(t, router, "API.DumpMempool", DumpMempoolArgs{}, &reloaded)
+
+	if strings.Join(reloaded.Transactions, ",") != strings.Join(dump.Transactions, ",") {
+		t.Fatalf("expected the reloaded mempool to match the dump")
+	}
+}
@@ -0,0 +1,68 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/anee769/essensio/core"
+)
+
+type MineBlockArgs struct {
+	CoinbaseData string `json:"coinbase_data"`
+}
+
+type MineBlockResult struct {
+	BlockHeight uint64 `json:"block_height"`
+	BlockHash   string `json:"block_hash"`
+	TxnCount    int    `json:"txn_count"`
+
+	// IDs of the pending transactions included in the block, excluding the coinbase
+	Included []string `json:"included"`
+	// Pending transactions that were rejected from the block and evicted from the mempool
+	Rejected []RejectedTransaction `json:"rejected"`
+}
+
+type RejectedTransaction struct {
+	TxID   string `json:"txid"`
+	Reason string `json:"reason"`
+}
+
+// MineBlock mines a Block with the pending transactions in the mempool. Transactions that are no longer
+// valid for the chain are rejected from the block and evicted from the mempool, and reported with their reasons.
+func (api *API) MineBlock(r *http.Request, args *MineBlockArgs, result *MineBlockResult) error {
+	log.Println("'MineBlock' Called")
+
+	if len(args.CoinbaseData) > core.MaxCoinbaseDataLength {
+		return fmt.Errorf("coinbase data exceeds maximum length %v", core.MaxCoinbaseDataLength)
+	}
+
+	if err := checkContext(r); err != nil {
+		return err
+	}
+
+	assembly, err := api.chain.MinePending(args.CoinbaseData)
+	if err != nil {
+		return fmt.Errorf("failed to mine block: %w", err)
+	}
+
+	included := make([]string, 0, len(assembly.Included))
+	for _, id := range assembly.Included {
+		included = append(included, id.Hex())
+	}
+
+	rejected := make([]RejectedTransaction, 0, len(assembly.Rejected))
+	for _, txn := range assembly.Rejected {
+		rejected = append(rejected, RejectedTransaction{TxID: txn.ID.Hex(), Reason: txn.Reason.Error()})
+	}
+
+	*result = MineBlockResult{
+		BlockHeight: toUint64(assembly.Block.BlockHeight),
+		BlockHash:   assembly.Block.BlockHash.Hex(),
+		TxnCount:    assembly.Block.TxnCount(),
+		Included:    included,
+		Rejected:    rejected,
+	}
+
+	return nil
+}
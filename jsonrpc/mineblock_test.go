@@ -0,0 +1,114 @@
+package jsonrpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+)
+
+// tipCoinbaseData returns the coinbase data of the Block at the chain head, as shown by ShowChain
+func tipCoinbaseData(t *testing.T, api *API) string {
+	t.Helper()
+
+	var chain ShowChainResult
+	callResult(t, newTestRouter(t, api, DefaultServerConfig()), "API.ShowChain", ShowChainArgs{Limit: 1}, &chain)
+
+	return chain.Blocks[0].CoinbaseData
+}
+
+// TestMineBlockCoinbaseData checks that the coinbase data of MineBlock, or the configured default data,
+// is tagged in the coinbase of the mined Block and shown by ShowChain, and that overlong data is rejected
+func TestMineBlockCoinbaseData(t *testing.T) {
+	opts := testChainOptions(0)
+	opts.Options.CoinbaseData = "node-tag"
+
+	api := newTestAPIOptions(t, opts)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	var mined MineBlockResult
+	callResult(t, router, "API.MineBlock", MineBlockArgs{CoinbaseData: "pool-x"}, &mined)
+
+	if data := tipCoinbaseData(t, api); data != "pool-x" {
+		t.Fatalf("expected the coinbase data of the mined block to be %q, got %q", "pool-x", data)
+	}
+
+	callResult(t, router, "API.MineBlock", MineBlockArgs{}, &mined)
+
+	if data := tipCoinbaseData(t, api); data != "node-tag" {
+		t.Fatalf("expected the coinbase data of the mined block to default to %q, got %q", "node-tag", data)
+	}
+
+	args := MineBlockArgs{CoinbaseData: strings.Repeat("x", core.MaxCoinbaseDataLength+1)}
+	if err := callError(t, router, "API.MineBlock", args); !strings.Contains(err, "exceeds maximum length") {
+		t.Fatalf("expected overlong coinbase data to be rejected, got %v", err)
+	}
+}
+
+// TestMineBlockRejected checks that MineBlock reports the included pending transactions, and a pending
+// transaction whose input was spent before mining as rejected with its reason
+func TestMineBlockRejected(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	genesis, err := api.chain.GetBlockByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spend := spendGenesis(t, api, core.TxOutput{Value: 90, PubKey: "alice"})
+
+	// The conflict is added to the mempool unchecked, as if it was pending when its input was spent
+	conflict := signedTransaction(t, common.MinerAddress(), []core.Outpoint{{ID: genesis.BlockTxns[0].ID, Index: 0}}, core.TxOutput{Value: 90, PubKey: "bob"})
+	if err := api.chain.Mempool().Add(conflict, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := signedTransaction(t, "alice", []core.Outpoint{{ID: spend.ID, Index: 0}}, core.TxOutput{Value: 80, PubKey: "carol"})
+	if err := api.chain.SubmitTransaction(valid); err != nil {
+		t.Fatal(err)
+	}
+
+	var mined MineBlockResult
+	callResult(t, router, "API.MineBlock", MineBlockArgs{}, &mined)
+
+	if len(mined.Included) != 1 || mined.Included[0] != valid.ID.Hex() || mined.TxnCount != 2 {
+		t.Fatalf("expected only '%v' to be included, got %+v", valid.ID.Hex(), mined)
+	}
+
+	if len(mined.Rejected) != 1 || mined.Rejected[0].TxID != conflict.ID.Hex() || mined.Rejected[0].Reason == "" {
+		t.Fatalf("expected '%v' to be rejected with a reason, got %+v", conflict.ID.Hex(), mined.Rejected)
+	}
+
+	if _, exists := api.chain.Mempool().Get(conflict.ID); exists {
+		t.Fatalf("expected the rejected transaction to be evicted from the mempool")
+	}
+}
+
+// TestMineBlockEmpty checks that MineBlock mines a coinbase-only block with an empty mempool,
+// and fails without core.Options.AllowEmptyBlocks
+func TestMineBlockEmpty(t *testing.T) {
+	t.Run("allowed", func(t *testing.T) {
+		api := newTestAPI(t, 0)
+		router := newTestRouter(t, api, DefaultServerConfig())
+
+		var mined MineBlockResult
+		callResult(t, router, "API.MineBlock", MineBlockArgs{}, &mined)
+
+		if mined.TxnCount != 1 || mined.BlockHeight != 1 || mined.BlockHash != api.chain.Head.Hex() {
+			t.Fatalf("expected an empty block at height 1, got %+v", mined)
+		}
+	})
+
+	t.Run("disallowed", func(t *testing.T) {
+		opts := testChainOptions(0)
+		opts.Options.AllowEmptyBlocks = false
+
+		router := newTestRouter(t, newTestAPIOptions(t, opts), DefaultServerConfig())
+
+		if err := callError(t, router, "API.MineBlock", MineBlockArgs{}); !strings.Contains(err, core.ErrNoPendingTransactions.Error()) {
+			t.Fatalf("expected empty blocks to be disallowed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// PeerSet is the set of peer node URLs of the node, managed at runtime with the peer methods.
+// It only tracks the peers, which are not contacted by the node. It is safe for concurrent use.
+type PeerSet struct {
+	mutex sync.RWMutex
+
+	// Represents the normalized URLs of the peers in order of addition
+	urls []string
+}
+
+// NewPeerSet returns a new empty PeerSet
+func NewPeerSet() *PeerSet {
+	return &PeerSet{}
+}
+
+// normalizePeer validates the URL of a peer and returns its normalized form, with a lowercase
+// scheme and host and no trailing slash, so that equivalent URLs are deduplicated.
+// Returns an error if the URL is not an absolute http or https URL with a host.
+func normalizePeer(raw string) (string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", fmt.Errorf("invalid peer url %q: %w", raw, err)
+	}
+
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", fmt.Errorf("invalid peer url %q: scheme must be http or https", raw)
+	}
+
+	if parsed.Host == "" {
+		return "", fmt.Errorf("invalid peer url %q: missing host", raw)
+	}
+
+	if parsed.User != nil || parsed.RawQuery != "" || parsed.Fragment != "" {
+		return "", fmt.Errorf("invalid peer url %q: must not have credentials, a query or a fragment", raw)
+	}
+
+	parsed.Host = strings.ToLower(parsed.Host)
+	parsed.Path = strings.TrimRight(parsed.Path, "/")
+
+	return parsed.String(), nil
+}
+
+// Add adds the peer with the given URL to the PeerSet and returns its normalized URL,
+// along with whether it was added or was already in the PeerSet.
+// Returns an error if the URL is invalid.
+func (peers *PeerSet) Add(raw string) (string, bool, error) {
+	peer, err := normalizePeer(raw)
+	if err != nil {
+		return "", false, err
+	}
+
+	peers.mutex.Lock()
+	defer peers.mutex.Unlock()
+
+	for _, existing := range peers.urls {
+		if existing == peer {
+			return peer, false, nil
+		}
+	}
+
+	peers.urls = append(peers.urls, peer)
+	return peer, true, nil
+}
+
+// Remove removes the peer with the given URL from the PeerSet and returns its normalized URL,
+// along with whether it was in the PeerSet. Returns an error if the URL is invalid.
+func (peers *PeerSet) Remove(raw string) (string, bool, error) {
+	peer, err := normalizePeer(raw)
+	if err != nil {
+		return "", false, err
+	}
+
+	peers.mutex.Lock()
+	defer peers.mutex.Unlock()
+
+	for idx, existing := range peers.urls {
+		if existing == peer {
+			peers.urls = append(peers.urls[:idx], peers.urls[idx+1:]...)
+			return peer, true, nil
+		}
+	}
+
+	return peer, false, nil
+}
+
+// Peers returns the URLs of the peers in the PeerSet in order of addition
+func (peers *PeerSet) Peers() []string {
+	peers.mutex.RLock()
+	defer peers.mutex.RUnlock()
+
+	return append([]string{}, peers.urls...)
+}
+
+type GetPeersArgs struct{}
+
+type GetPeersResult struct {
+	Peers []string `json:"peers"`
+}
+
+// GetPeers returns the URLs of the peers of the node in order of addition
+func (api *API) GetPeers(r *http.Request, args *GetPeersArgs, result *GetPeersResult) error {
+	log.Println("'GetPeers' Called")
+
+	*result = GetPeersResult{Peers: api.peers.Peers()}
+	return nil
+}
+
+type PeerArgs struct {
+	URL string `json:"url"`
+}
+
+type PeerResult struct {
+	// Normalized URL of the peer
+	URL string `json:"url"`
+	// Whether the set of peers was modified, which is false if the peer was already added or removed
+	Changed bool `json:"changed"`
+}
+
+// AddPeer adds a peer to the node by its http or https URL. Adding a peer that is already known is a no-op.
+func (api *API) AddPeer(r *http.Request, args *PeerArgs, result *PeerResult) error {
+	log.Println("'AddPeer' Called")
+
+	peer, added, err := api.peers.Add(args.URL)
+	if err != nil {
+		return fmt.Errorf("failed to add peer: %w", err)
+	}
+
+	*result = PeerResult{URL: peer, Changed: added}
+	return nil
+}
+
+// RemovePeer removes a peer from the node by its URL. Removing a peer that is not known is a no-op.
+func (api *API) RemovePeer(r *http.Request, args *PeerArgs, result *PeerResult) error {
+	log.Println("'RemovePeer' Called")
+
+	peer, removed, err := api.peers.Remove(args.URL)
+	if err != nil {
+		return fmt.Errorf("failed to remove peer: %w", err)
+	}
+
+	*result = PeerResult{URL: peer, Changed: removed}
+	return nil
+}
@@ -0,0 +1,67 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/anee769/essensio/core"
+)
+
+type ReplayTransactionArgs struct {
+	// Hex encoded serialized Transaction
+	Transaction string `json:"transaction"`
+}
+
+type ReplayTransactionResult struct {
+	TxID    string                `json:"txid"`
+	Spent   []string              `json:"spent"`
+	Created []ReplayCreatedOutput `json:"created"`
+	Fee     Value                 `json:"fee"`
+	Deltas  map[string]Value      `json:"deltas"`
+}
+
+type ReplayCreatedOutput struct {
+	Outpoint string `json:"outpoint"`
+	Value    Value  `json:"value"`
+	Address  string `json:"address"`
+}
+
+// ReplayTransaction computes the effect of a serialized Transaction against the current UTXO set without submitting it.
+// The result has the outputs the Transaction spends and creates, its fee and the net change in balance of each address.
+func (api *API) ReplayTransaction(r *http.Request, args *ReplayTransactionArgs, result *ReplayTransactionResult) error {
+	log.Println("'ReplayTransaction' Called")
+
+	txn, err := decodeTransaction(args.Transaction)
+	if err != nil {
+		return err
+	}
+
+	replay, err := api.chain.ReplayTransaction(txn)
+	if err != nil {
+		return fmt.Errorf("transaction replay failed: %w", err)
+	}
+
+	*result = ReplayTransactionResult{
+		TxID:    txn.ID.Hex(),
+		Spent:   make([]string, 0, len(replay.Spent)),
+		Created: make([]ReplayCreatedOutput, 0, len(replay.Created)),
+		Fee:     NewValue(replay.Fee),
+		Deltas:  make(map[string]Value, len(replay.Deltas)),
+	}
+
+	for _, outpoint := range replay.Spent {
+		result.Spent = append(result.Spent, outpoint.String())
+	}
+
+	for idx, output := range replay.Created {
+		outpoint := core.Outpoint{ID: txn.ID, Index: idx}
+		result.Created = append(result.Created, ReplayCreatedOutput{outpoint.String(), NewValue(output.Value), string(output.PubKey)})
+	}
+
+	for address, delta := range replay.Deltas {
+		result.Deltas[string(address)] = NewValue(delta)
+	}
+
+	return nil
+}
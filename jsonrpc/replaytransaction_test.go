@@ -0,0 +1,55 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+)
+
+// TestReplayTransaction checks the spent and created outputs, fee and deltas of ReplayTransaction,
+// and that the replayed Transaction is neither mined nor added to the mempool
+func TestReplayTransaction(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+	miner := common.MinerAddress()
+
+	genesis, err := api.chain.GetBlockByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outpoint := core.Outpoint{ID: genesis.BlockTxns[0].ID, Index: 0}
+	spend := signedTransaction(t, miner, []core.Outpoint{outpoint}, core.TxOutput{Value: 60, PubKey: "alice"}, core.TxOutput{Value: 30, PubKey: miner})
+
+	encoded, err := encodeTransaction(spend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result ReplayTransactionResult
+	callResult(t, router, "API.ReplayTransaction", ReplayTransactionArgs{Transaction: encoded}, &result)
+
+	if result.TxID != spend.ID.Hex() || len(result.Spent) != 1 || result.Spent[0] != outpoint.String() || result.Fee.Int() != 10 {
+		t.Fatalf("expected the replay to spend the genesis output with a fee of 10, got %+v", result)
+	}
+
+	if len(result.Created) != 2 || result.Created[1].Outpoint != (core.Outpoint{ID: spend.ID, Index: 1}).String() || result.Created[1].Address != string(miner) {
+		t.Fatalf("expected the replay to create the outputs of the transaction, got %+v", result.Created)
+	}
+
+	want := map[string]int{"alice": 60, string(miner): -70}
+	if len(result.Deltas) != len(want) {
+		t.Fatalf("expected deltas %v, got %v", want, result.Deltas)
+	}
+
+	for address, delta := range want {
+		if result.Deltas[address].Int() != delta {
+			t.Fatalf("expected the delta of %v to be %v, got %v", address, delta, result.Deltas[address].Int())
+		}
+	}
+
+	if api.chain.Height != 1 || api.chain.Mempool().Size() != 0 {
+		t.Fatalf("expected the replay not to modify the chain or the mempool")
+	}
+}
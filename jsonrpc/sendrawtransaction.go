@@ -0,0 +1,38 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+)
+
+type SendRawTransactionArgs struct {
+	// Hex encoded serialized Transaction
+	Transaction string `json:"transaction"`
+}
+
+type SendRawTransactionResult struct {
+	TxID string `json:"txid"`
+}
+
+// SendRawTransaction submits a serialized Transaction that was built and signed offline to the mempool.
+// The Transaction is fully validated against the chain before it is accepted.
+func (api *API) SendRawTransaction(r *http.Request, args *SendRawTransactionArgs, result *SendRawTransactionResult) error {
+	log.Println("'SendRawTransaction' Called")
+
+	txn, err := decodeTransaction(args.Transaction)
+	if err != nil {
+		return err
+	}
+
+	if err := checkContext(r); err != nil {
+		return err
+	}
+
+	if err := api.chain.SubmitTransaction(txn); err != nil {
+		return fmt.Errorf("failed to submit transaction: %w", err)
+	}
+
+	*result = SendRawTransactionResult{TxID: txn.ID.Hex()}
+	return nil
+}
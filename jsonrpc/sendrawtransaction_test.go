@@ -0,0 +1,68 @@
+package jsonrpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+)
+
+// TestSendRawTransaction checks that a valid serialized Transaction is added to the mempool by SendRawTransaction,
+// and that malformed hex, corrupted data and invalid Transactions are rejected without being added
+func TestSendRawTransaction(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	genesis, err := api.chain.GetBlockByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outpoint := core.Outpoint{ID: genesis.BlockTxns[0].ID, Index: 0}
+	spend := signedTransaction(t, common.MinerAddress(), []core.Outpoint{outpoint}, core.TxOutput{Value: 90, PubKey: "alice"})
+
+	encoded, err := encodeTransaction(spend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forged := signedTransaction(t, "mallory", []core.Outpoint{outpoint}, core.TxOutput{Value: 90, PubKey: "mallory"})
+	invalid, err := encodeTransaction(forged)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		error string
+	}{
+		{"malformed hex", "0xzz", "invalid transaction hex"},
+		{"corrupted", encoded[:len(encoded)/2], "invalid transaction data"},
+		{"invalid", invalid, "failed to submit transaction"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := callError(t, router, "API.SendRawTransaction", SendRawTransactionArgs{Transaction: test.input}); !strings.Contains(err, test.error) {
+				t.Fatalf("expected error containing %q, got %v", test.error, err)
+			}
+		})
+	}
+
+	if size := api.chain.Mempool().Size(); size != 0 {
+		t.Fatalf("expected the rejected transactions not to be added to the mempool, got %v", size)
+	}
+
+	var result SendRawTransactionResult
+	callResult(t, router, "API.SendRawTransaction", SendRawTransactionArgs{Transaction: encoded}, &result)
+
+	if result.TxID != spend.ID.Hex() {
+		t.Fatalf("expected txid '%v', got '%v'", spend.ID.Hex(), result.TxID)
+	}
+
+	if _, pending := api.chain.Mempool().Get(spend.ID); !pending {
+		t.Fatalf("expected the transaction to be added to the mempool")
+	}
+}
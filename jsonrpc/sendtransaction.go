@@ -0,0 +1,59 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+)
+
+type SendTransactionArgs struct {
+	TransactionInput
+}
+
+type SendTransactionResult struct {
+	TxID string `json:"txid"`
+}
+
+// SendTransaction builds and signs a Transaction for the given sender and submits it to the mempool.
+// Concurrent calls for the same sender are serialized, so that they never select the same outputs.
+func (api *API) SendTransaction(r *http.Request, args *SendTransactionArgs, result *SendTransactionResult) error {
+	log.Println("'SendTransaction' Called")
+
+	if err := args.validate(api.chain); err != nil {
+		return err
+	}
+
+	from := common.NormalizeAddress(args.From)
+
+	// Hold the spend lock of the sender until the transaction is in the mempool
+	unlock := api.chain.LockSpends(from)
+	defer unlock()
+
+	// The request may have timed out while waiting for the spend lock
+	if err := checkContext(r); err != nil {
+		return err
+	}
+
+	txn, err := core.BuildUnsignedTransaction(from, args.outputs(), api.chain, args.buildOptions())
+	if err != nil {
+		return fmt.Errorf("failed to build transaction: %w", err)
+	}
+
+	if err := core.SignTransaction(txn, core.NewWallet(from)); err != nil {
+		return fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	if err := checkContext(r); err != nil {
+		return err
+	}
+
+	if err := api.chain.SubmitTransaction(txn); err != nil {
+		return fmt.Errorf("failed to submit transaction: %w", err)
+	}
+
+	*result = SendTransactionResult{TxID: txn.ID.Hex()}
+	return nil
+}
@@ -0,0 +1,260 @@
+package jsonrpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+)
+
+// TestSendTransactionChange checks that SendTransaction sends the change of the Transaction to its change address
+func TestSendTransactionChange(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	args := SendTransactionArgs{TransactionInput{From: string(common.MinerAddress()), To: "alice", Value: NewValue(30), Change: "change"}}
+
+	var result SendTransactionResult
+	callResult(t, router, "API.SendTransaction", args, &result)
+
+	id, err := common.HexToHash(result.TxID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txn, exists := api.chain.Mempool().Get(id)
+	if !exists {
+		t.Fatalf("expected transaction '%v' in the mempool", result.TxID)
+	}
+
+	want := []core.TxOutput{{Value: 30, PubKey: "alice"}, {Value: 70, PubKey: "change"}}
+	if len(txn.Outputs) != len(want) || txn.Outputs[0] != want[0] || txn.Outputs[1] != want[1] {
+		t.Fatalf("expected outputs %v, got %v", want, txn.Outputs)
+	}
+}
+
+// TestSendTransactionMinConfirmations checks that SendTransaction only spends the outputs with the minimum
+// number of confirmations of its arguments
+func TestSendTransactionMinConfirmations(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	// The output of alice is in the chain head, with 1 confirmation
+	spendGenesis(t, api, core.TxOutput{Value: core.BlockReward, PubKey: "alice"})
+
+	args := SendTransactionArgs{TransactionInput{From: "alice", To: "bob", Value: NewValue(30), MinConfirmations: 2}}
+	if message := callError(t, router, "API.SendTransaction", args); !strings.Contains(message, "failed to build transaction") {
+		t.Fatalf("expected the unconfirmed output not to be spent, got %q", message)
+	}
+
+	args.MinConfirmations = 1
+
+	var result SendTransactionResult
+	callResult(t, router, "API.SendTransaction", args, &result)
+}
+
+// TestSendTransactionDust checks that SendTransaction rejects a Transaction with an output below the dust threshold
+func TestSendTransactionDust(t *testing.T) {
+	opts := testChainOptions(0)
+	opts.Options.DustThreshold = 10
+
+	api := newTestAPIOptions(t, opts)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	args := SendTransactionArgs{TransactionInput{From: string(common.MinerAddress()), To: "alice", Value: NewValue(5)}}
+	if message := callError(t, router, "API.SendTransaction", args); !strings.Contains(message, "below dust threshold") {
+		t.Fatalf("expected the dust output to be rejected, got %q", message)
+	}
+
+	args.Value = NewValue(10)
+
+	var result SendTransactionResult
+	callResult(t, router, "API.SendTransaction", args, &result)
+}
+
+// TestSendTransactionConcurrent checks that concurrent SendTransaction calls for the same sender
+// select different outputs, so that their Transactions do not conflict
+func TestSendTransactionConcurrent(t *testing.T) {
+	api := newTestAPI(t, 0)
+
+	// Each send can be funded by a single output of alice
+	spendGenesis(t, api, core.TxOutput{Value: 50, PubKey: "alice"}, core.TxOutput{Value: 50, PubKey: "alice"})
+
+	const senders = 2
+
+	var wg sync.WaitGroup
+	ids := make(chan string, senders)
+	errs := make(chan error, senders)
+
+	for idx := 0; idx < senders; idx++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			args := SendTransactionArgs{TransactionInput{From: "alice", To: "bob", Value: NewValue(40)}}
+			request := httptest.NewRequest(http.MethodPost, DefaultPath, nil)
+
+			var result SendTransactionResult
+			if err := api.SendTransaction(request, &args, &result); err != nil {
+				errs <- err
+				return
+			}
+
+			ids <- result.TxID
+		}()
+	}
+
+	wg.Wait()
+	close(ids)
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("concurrent send failed: %v", err)
+	}
+
+	spent := make(map[core.Outpoint]string)
+	for txid := range ids {
+		id, err := common.HexToHash(txid)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		txn, exists := api.chain.Mempool().Get(id)
+		if !exists {
+			t.Fatalf("expected transaction '%v' in the mempool", txid)
+		}
+
+		for _, input := range txn.Inputs {
+			outpoint := core.Outpoint{ID: input.ID, Index: input.Out}
+			if other, conflict := spent[outpoint]; conflict {
+				t.Fatalf("expected transactions '%v' and '%v' not to spend the same output %v", other, txid, outpoint)
+			}
+
+			spent[outpoint] = txid
+		}
+	}
+
+	if len(spent) != senders {
+		t.Fatalf("expected each send to spend one output, got %v spent outputs", len(spent))
+	}
+}
+
+// TestSendTransactionNormalizeAddress checks that addresses differing only by surrounding whitespace
+// refer to the same owner at the RPC boundary
+func TestSendTransactionNormalizeAddress(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	args := SendTransactionArgs{TransactionInput{From: " " + string(common.MinerAddress()) + "\n", To: "\talice ", Value: NewValue(30)}}
+
+	var sent SendTransactionResult
+	callResult(t, router, "API.SendTransaction", args, &sent)
+
+	var mined MineBlockResult
+	callResult(t, router, "API.MineBlock", MineBlockArgs{}, &mined)
+
+	for _, address := range []string{"alice", " alice", "alice\n"} {
+		var balance GetBalanceResult
+		callResult(t, router, "API.GetBalance", GetBalanceArgs{Address: address}, &balance)
+
+		if balance.Address != "alice" || balance.Balance.Int() != 30 {
+			t.Fatalf("expected %q to have the balance of alice, got %+v", address, balance)
+		}
+	}
+}
+
+// TestSendTransactionLock checks that SendTransaction applies the lock of its arguments to the paid output
+func TestSendTransactionLock(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	args := SendTransactionArgs{TransactionInput{From: string(common.MinerAddress()), To: "alice", Value: NewValue(30), LockHeight: 5, LockTime: 1700000000}}
+
+	var result SendTransactionResult
+	callResult(t, router, "API.SendTransaction", args, &result)
+
+	id, err := common.HexToHash(result.TxID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txn, exists := api.chain.Mempool().Get(id)
+	if !exists {
+		t.Fatalf("expected transaction '%v' in the mempool", result.TxID)
+	}
+
+	if want := (core.OutputLock{Height: 5, Time: 1700000000}); txn.Outputs[0].Lock != want {
+		t.Fatalf("expected the paid output to be locked with %+v, got %+v", want, txn.Outputs[0].Lock)
+	}
+
+	if !txn.Outputs[1].Lock.IsZero() {
+		t.Fatalf("expected the change output not to be locked, got %+v", txn.Outputs[1].Lock)
+	}
+}
+
+// TestSendTransactionConsolidate checks that SendTransaction sweeps the number of additional outputs of its arguments
+// into the change of the Transaction
+func TestSendTransactionConsolidate(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	spendGenesis(t, api, core.TxOutput{Value: 30, PubKey: "alice"}, core.TxOutput{Value: 30, PubKey: "alice"}, core.TxOutput{Value: 30, PubKey: "alice"})
+
+	args := SendTransactionArgs{TransactionInput{From: "alice", To: "bob", Value: NewValue(5), Consolidate: 2}}
+
+	var result SendTransactionResult
+	callResult(t, router, "API.SendTransaction", args, &result)
+
+	id, err := common.HexToHash(result.TxID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txn, exists := api.chain.Mempool().Get(id)
+	if !exists {
+		t.Fatalf("expected transaction '%v' in the mempool", result.TxID)
+	}
+
+	if len(txn.Inputs) != 3 || len(txn.Outputs) != 2 {
+		t.Fatalf("expected every output of alice to be spent into a single change, got %v inputs and %v outputs", len(txn.Inputs), len(txn.Outputs))
+	}
+}
+
+// TestSendTransactionAddress checks that SendTransaction rejects over-long addresses and addresses
+// with disallowed characters before building a Transaction
+func TestSendTransactionAddress(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	miner := string(common.MinerAddress())
+	long := strings.Repeat("a", common.DefaultMaxAddressLength+1)
+
+	tests := []struct {
+		name    string
+		input   TransactionInput
+		message string
+	}{
+		{"over-long recipient", TransactionInput{From: miner, To: long, Value: NewValue(30)}, "invalid recipient"},
+		{"disallowed recipient", TransactionInput{From: miner, To: "al ice", Value: NewValue(30)}, "invalid recipient"},
+		{"disallowed sender", TransactionInput{From: "miner!", To: "alice", Value: NewValue(30)}, "invalid sender"},
+		{"over-long change", TransactionInput{From: miner, To: "alice", Value: NewValue(30), Change: long}, "invalid change address"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			message := callError(t, router, "API.SendTransaction", SendTransactionArgs{test.input})
+			if !strings.Contains(message, test.message) || !strings.Contains(message, common.ErrInvalidAddress.Error()) {
+				t.Fatalf("expected an error containing %q, got %q", test.message, message)
+			}
+		})
+	}
+
+	if count := api.chain.Mempool().Size(); count != 0 {
+		t.Fatalf("expected no transactions in the mempool, got %v", count)
+	}
+}
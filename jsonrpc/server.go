@@ -0,0 +1,172 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/rpc"
+	"github.com/gorilla/rpc/json"
+)
+
+// DefaultPath is the default route at which the JSON-RPC server is mounted
+const DefaultPath = "/rpc"
+
+// DefaultMaxResponseBlocks is the default maximum number of Blocks in a response
+const DefaultMaxResponseBlocks = 1000
+
+// ServerConfig represents the configuration of the JSON-RPC server
+type ServerConfig struct {
+	// Path is the route at which the JSON-RPC server is mounted
+	Path string
+	// BlockCSVPath is the route at which the Transactions of a Block are served as CSV
+	BlockCSVPath string
+	// AllowedOrigins is the set of origins allowed to make cross-origin requests.
+	// CORS is disabled if empty. The wildcard origin "*" allows all origins.
+	AllowedOrigins []string
+	// MaxResponseBlocks is the maximum number of Blocks in a response, such as that of ShowChain.
+	// Requests for more Blocks are rejected and must be paginated. Responses are not limited if it is 0.
+	MaxResponseBlocks int64
+	// AuthToken is the bearer token required by the AuthMethods, which are disabled if it is empty
+	AuthToken string
+	// StringValues is whether amounts in results, such as values and balances, are encoded as decimal strings
+	// instead of JSON numbers, which JavaScript clients cannot represent exactly beyond 2^53
+	StringValues bool
+	// Peers are the URLs of the initial peers of the node, which can be managed at runtime with AddPeer and RemovePeer
+	Peers []string
+	// MaxConcurrent is the maximum number of requests served concurrently.
+	// Requests beyond the limit are rejected as busy. Requests are not limited if it is 0.
+	MaxConcurrent int
+	// MaxBatchSize is the maximum number of requests in a batch request, which is a JSON array of requests.
+	// Each request of a batch is authenticated and timed out separately. Batches are disabled if it is 0.
+	MaxBatchSize int
+
+	// ReadTimeout is the timeout of methods that do not modify the chain. No timeout applies if it is 0.
+	ReadTimeout time.Duration
+	// WriteTimeout is the timeout of the WriteMethods. No timeout applies if it is 0.
+	WriteTimeout time.Duration
+	// MethodTimeouts is the timeout of specific methods by name, such as "API.ShowChain".
+	// It overrides ReadTimeout and WriteTimeout, and a timeout of 0 disables the timeout of a method.
+	MethodTimeouts map[string]time.Duration
+}
+
+// DefaultServerConfig returns the default ServerConfig with CORS disabled
+func DefaultServerConfig() ServerConfig {
+	return ServerConfig{
+		Path:              DefaultPath,
+		BlockCSVPath:      DefaultBlockCSVPath,
+		MaxResponseBlocks: DefaultMaxResponseBlocks,
+		MaxBatchSize:      DefaultMaxBatchSize,
+	}
+}
+
+// NewRouter returns an http.Handler that serves the given API
+// as a JSON-RPC server configured with the given ServerConfig
+func NewRouter(api *API, config ServerConfig) (http.Handler, error) {
+	// Apply the response limits of the configuration to the API
+	api.maxResponseBlocks = config.MaxResponseBlocks
+
+	// Add the static peers of the configuration
+	for _, peer := range config.Peers {
+		if _, _, err := api.peers.Add(peer); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create a new RPC Server and register the JSON Codec
+	server := rpc.NewServer()
+	// Encode the values in results as strings if configured
+	var codec rpc.Codec = json.NewCodec()
+	if config.StringValues {
+		codec = valueCodec{codec}
+	}
+
+	server.RegisterCodec(codec, "application/json")
+	server.RegisterCodec(codec, "application/json;charset=UTF-8")
+
+	// Register the Essensio API with the Server
+	if err := server.RegisterService(api, ""); err != nil {
+		return nil, fmt.Errorf("failed to register essensio api: %w", err)
+	}
+
+	path := config.Path
+	if path == "" {
+		path = DefaultPath
+	}
+
+	csvPath := config.BlockCSVPath
+	if csvPath == "" {
+		csvPath = DefaultBlockCSVPath
+	}
+
+	// Set up a new Multiplexed Router
+	router := mux.NewRouter()
+	// Batches are split before authentication, so that each of their requests is authenticated
+	router.Handle(path, withCORS(withBatch(withAuth(withTimeouts(server, config), config.AuthToken), config.MaxBatchSize), config.AllowedOrigins))
+
+	// Serve the CSV export of Blocks separately from the JSON-RPC methods
+	router.Handle(csvPath, withCORS(http.HandlerFunc(api.serveBlockCSV), config.AllowedOrigins))
+
+	// Limit the concurrent requests across all routes
+	return withConcurrencyLimit(router, config.MaxConcurrent), nil
+}
+
+// withConcurrencyLimit wraps an http.Handler with a limit on the number of requests it serves concurrently.
+// Requests beyond the limit are rejected immediately with a 503 status, providing backpressure to clients.
+func withConcurrencyLimit(handler http.Handler, limit int) http.Handler {
+	// Requests are not limited without a positive limit
+	if limit <= 0 {
+		return handler
+	}
+
+	semaphore := make(chan struct{}, limit)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case semaphore <- struct{}{}:
+			defer func() { <-semaphore }()
+			handler.ServeHTTP(w, r)
+
+		default:
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, fmt.Sprintf("rpc: server busy: %v requests in flight", limit), http.StatusServiceUnavailable)
+		}
+	})
+}
+
+// withCORS wraps an http.Handler with CORS handling for the given set of allowed origins.
+// Preflight requests from allowed origins are answered directly without invoking the handler.
+func withCORS(handler http.Handler, origins []string) http.Handler {
+	// CORS is disabled without any allowed origins
+	if len(origins) == 0 {
+		return handler
+	}
+
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[strings.TrimSpace(origin)] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !(allowed["*"] || allowed[origin]) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+
+		// Answer preflight requests
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,167 @@
+package jsonrpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// serve sends an HTTP request with the given method, body and headers to the router and returns its response
+func serve(router http.Handler, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+// TestRouterPathAndCORS checks that the JSON-RPC server is mounted at the configured path, and that
+// CORS headers are only returned to the allowed origins, whose preflight requests succeed
+func TestRouterPathAndCORS(t *testing.T) {
+	api := newTestAPI(t, 0)
+
+	config := DefaultServerConfig()
+	config.Path = "/api"
+	config.AllowedOrigins = []string{"https://explorer.example"}
+
+	router := newTestRouter(t, api, config)
+	body := `{"method":"API.ShowChain","params":[{}],"id":1}`
+	headers := map[string]string{"Content-Type": "application/json", "Origin": "https://explorer.example"}
+
+	response := serve(router, http.MethodPost, "/api", body, headers)
+	if response.Code != http.StatusOK || response.Header().Get("Access-Control-Allow-Origin") != "https://explorer.example" {
+		t.Fatalf("expected a response with the allowed origin, got status %v and headers %v", response.Code, response.Header())
+	}
+
+	if response := serve(router, http.MethodPost, DefaultPath, body, headers); response.Code != http.StatusNotFound {
+		t.Fatalf("expected the default path to be unmounted, got status %v", response.Code)
+	}
+
+	preflight := serve(router, http.MethodOptions, "/api", "", map[string]string{
+		"Origin":                        "https://explorer.example",
+		"Access-Control-Request-Method": http.MethodPost,
+	})
+
+	if preflight.Code != http.StatusNoContent || !strings.Contains(preflight.Header().Get("Access-Control-Allow-Methods"), http.MethodPost) {
+		t.Fatalf("expected the preflight request to succeed, got status %v and headers %v", preflight.Code, preflight.Header())
+	}
+
+	headers["Origin"] = "https://other.example"
+	if response := serve(router, http.MethodPost, "/api", body, headers); response.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("expected no CORS headers for an origin that is not allowed, got %v", response.Header())
+	}
+}
+
+// TestRouterCORSDisabled checks that no CORS headers are returned without any allowed origins
+func TestRouterCORSDisabled(t *testing.T) {
+	router := newTestRouter(t, newTestAPI(t, 0), DefaultServerConfig())
+
+	response := serve(router, http.MethodPost, DefaultPath, `{"method":"API.ShowChain","params":[{}],"id":1}`, map[string]string{
+		"Content-Type": "application/json",
+		"Origin":       "https://explorer.example",
+	})
+
+	if response.Code != http.StatusOK || response.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("expected a response without CORS headers, got status %v and headers %v", response.Code, response.Header())
+	}
+}
+
+// TestConcurrencyLimit checks that a handler with a concurrency limit never serves more requests than the limit
+// at once, and that requests beyond the limit are rejected as busy with a 503 status and a Retry-After header
+func TestConcurrencyLimit(t *testing.T) {
+	const limit = 3
+
+	var inFlight, peak int32
+	started, release := make(chan struct{}, limit), make(chan struct{})
+
+	handler := withConcurrencyLimit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			if max := atomic.LoadInt32(&peak); count <= max || atomic.CompareAndSwapInt32(&peak, max, count) {
+				break
+			}
+		}
+
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+
+		<-release
+	}), limit)
+
+	// Hold the limit of requests blocked in the handler
+	var wg sync.WaitGroup
+	codes := make(chan int, 2*limit)
+
+	for idx := 0; idx < limit; idx++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			codes <- serve(handler, http.MethodPost, DefaultPath, "", nil).Code
+		}()
+	}
+
+	for idx := 0; idx < limit; idx++ {
+		<-started
+	}
+
+	response := serve(handler, http.MethodPost, DefaultPath, "", nil)
+	if response.Code != http.StatusServiceUnavailable || !strings.Contains(response.Body.String(), "server busy") {
+		t.Fatalf("expected a busy error beyond the limit, got status %v and body %q", response.Code, response.Body.String())
+	}
+
+	if retry := response.Header().Get("Retry-After"); retry == "" {
+		t.Fatalf("expected a Retry-After header for a busy error")
+	}
+
+	close(release)
+	wg.Wait()
+	close(codes)
+
+	for code := range codes {
+		if code != http.StatusOK {
+			t.Fatalf("expected the requests within the limit to be served, got status %v", code)
+		}
+	}
+
+	// A burst of requests is served at most the limit at a time
+	served := int32(0)
+	for idx := 0; idx < 10*limit; idx++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if serve(handler, http.MethodPost, DefaultPath, "", nil).Code == http.StatusOK {
+				atomic.AddInt32(&served, 1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if served == 0 || peak > limit {
+		t.Fatalf("expected at most %v requests in flight, got %v with %v served", limit, peak, served)
+	}
+
+	// Sequential requests to a router with MaxConcurrent are served within the limit
+	config := DefaultServerConfig()
+	config.MaxConcurrent = 1
+
+	router := newTestRouter(t, newTestAPI(t, 0), config)
+	for idx := 0; idx < 2; idx++ {
+		var result ShowChainResult
+		callResult(t, router, "API.ShowChain", ShowChainArgs{}, &result)
+	}
+}
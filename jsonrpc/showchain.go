@@ -1,6 +1,7 @@
 package jsonrpc
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 	"time"
@@ -8,7 +9,15 @@ import (
 	"github.com/anee769/essensio/core"
 )
 
-type ShowChainArgs struct{}
+type ShowChainArgs struct {
+	Verify     bool       `json:"verify"`
+	HashFormat HashFormat `json:"hash_format"`
+
+	// Height of the first Block returned, defaults to the chain head
+	FromHeight *int64 `json:"from_height,omitempty"`
+	// Maximum number of Blocks returned, defaults to all Blocks down to the Genesis Block
+	Limit int64 `json:"limit,omitempty"`
+}
 
 type ShowChainResult struct {
 	ChainHead   string       `json:"chain_head"`
@@ -24,35 +33,153 @@ type ChainBlock struct {
 	BlockHash     string `json:"block_hash"`
 	PrevBlockHash string `json:"prev_block_hash"`
 
-	TxnCount          int               `json:"txn_count"`
-	BLockTransactions core.Transactions `json:"data"`
+	TxnCount          int                `json:"txn_count"`
+	BLockTransactions []ChainTransaction `json:"data"`
+	CoinbaseData      string             `json:"coinbase_data,omitempty"`
+	SummaryValid      *bool              `json:"summary_valid,omitempty"`
+}
+
+type ChainTransaction struct {
+	TxID    string                   `json:"txid"`
+	Inputs  []ChainTransactionInput  `json:"inputs"`
+	Outputs []ChainTransactionOutput `json:"outputs"`
+	Height  int64                    `json:"height"`
+}
+
+type ChainTransactionInput struct {
+	TxID string `json:"txid"`
+	Out  int    `json:"out"`
+	Sig  string `json:"sig"`
+}
+
+type ChainTransactionOutput struct {
+	Value   Value  `json:"value"`
+	Address string `json:"address"`
+
+	// Lock of the output, omitted if the output is not locked
+	LockHeight int64 `json:"lock_height,omitempty"`
+	LockTime   int64 `json:"lock_time,omitempty"`
+}
+
+// NewChainTransaction returns the ChainTransaction representation of a Transaction with its hashes in the given HashFormat
+func NewChainTransaction(txn *core.Transaction, format HashFormat) ChainTransaction {
+	chaintxn := ChainTransaction{
+		TxID:    format.encode(txn.ID),
+		Inputs:  make([]ChainTransactionInput, 0, len(txn.Inputs)),
+		Outputs: make([]ChainTransactionOutput, 0, len(txn.Outputs)),
+		Height:  txn.Height,
+	}
+
+	for _, input := range txn.Inputs {
+		chaintxn.Inputs = append(chaintxn.Inputs, ChainTransactionInput{format.encode(input.ID), input.Out, string(input.Sig)})
+	}
+
+	for _, output := range txn.Outputs {
+		chaintxn.Outputs = append(chaintxn.Outputs, ChainTransactionOutput{NewValue(output.Value), string(output.PubKey), output.Lock.Height, output.Lock.Time})
+	}
+
+	return chaintxn
+}
+
+// NewChainBlock returns the ChainBlock representation of a Block
+func NewChainBlock(block *core.Block) ChainBlock {
+	return newChainBlock(block, HashFormatPrefixed)
+}
+
+// newChainBlock returns the ChainBlock representation of a Block with all its hashes in the given HashFormat
+func newChainBlock(block *core.Block, format HashFormat) ChainBlock {
+	// Coinbase Transaction of the Block, if any, is always the first
+	var coinbase string
+	if block.TxnCount() > 0 {
+		coinbase = block.BlockTxns[0].CoinbaseData()
+	}
+
+	txns := make([]ChainTransaction, 0, block.TxnCount())
+	for _, txn := range block.BlockTxns {
+		txns = append(txns, NewChainTransaction(txn, format))
+	}
+
+	return ChainBlock{
+		Height:            toUint64(block.BlockHeight),
+		Timestamp:         time.Unix(block.Timestamp, 0).Format(time.RFC3339),
+		BlockHash:         format.encode(block.BlockHash),
+		PrevBlockHash:     format.encode(block.Priori),
+		Nonce:             toUint64(block.Nonce),
+		TxnCount:          block.TxnCount(),
+		BLockTransactions: txns,
+		CoinbaseData:      coinbase,
+	}
+}
+
+// NewVerifiedChainBlock returns the ChainBlock representation of a Block.
+// If verify is set, it reports whether the Transactions of the Block match its header summary.
+func NewVerifiedChainBlock(block *core.Block, verify bool) ChainBlock {
+	return newVerifiedChainBlock(block, verify, HashFormatPrefixed)
+}
+
+// newVerifiedChainBlock returns the ChainBlock representation of a Block with all its hashes in the given HashFormat.
+// If verify is set, it reports whether the Transactions of the Block match its header summary.
+func newVerifiedChainBlock(block *core.Block, verify bool, format HashFormat) ChainBlock {
+	chainblock := newChainBlock(block, format)
+	if verify {
+		valid := block.VerifySummary()
+		chainblock.SummaryValid = &valid
+	}
+
+	return chainblock
 }
 
+// ShowChain returns the Blocks of the chain from the given height, or the chain head, backwards up to the limit.
+// Requests for more Blocks than the maximum response size of the server are rejected and must be paginated.
+// All hashes in the result are formatted with the requested HashFormat, or 0x prefixed by default.
 func (api *API) ShowChain(r *http.Request, args *ShowChainArgs, result *ShowChainResult) error {
 	log.Println("'ShowChain' Called")
 
+	if err := args.HashFormat.check(); err != nil {
+		return err
+	}
+
+	if args.Limit < 0 {
+		return fmt.Errorf("invalid limit %v", args.Limit)
+	}
+
+	from := api.chain.Height - 1
+	if args.FromHeight != nil {
+		from = *args.FromHeight
+	}
+
+	// Blocks are returned down to the Genesis Block, unless limited
+	count := from + 1
+	if args.Limit > 0 && args.Limit < count {
+		count = args.Limit
+	}
+
+	iterator, err := api.chain.NewIteratorFrom(from)
+	if err != nil {
+		return fmt.Errorf("invalid start height: %w", err)
+	}
+
+	if err := api.checkResponseBlocks(count); err != nil {
+		return err
+	}
+
 	chainresult := ShowChainResult{
-		ChainHead:   api.chain.Head.Hex(),
-		ChainHeight: uint64(api.chain.Height),
+		ChainHead:   args.HashFormat.encode(api.chain.Head),
+		ChainHeight: toUint64(api.chain.Height),
 	}
 
-	iterator := api.chain.NewIterator()
-	for !iterator.Done() {
+	for !iterator.Done() && int64(len(chainresult.Blocks)) < count {
+		if err := checkContext(r); err != nil {
+			return err
+		}
+
 		// Get the next block
 		block, err := iterator.Next()
 		if err != nil {
-			log.Fatalln("Iterator Error:", err)
+			return fmt.Errorf("iterator error: %w", err)
 		}
 
-		chainresult.Blocks = append(chainresult.Blocks, ChainBlock{
-			Height:            uint64(block.BlockHeight),
-			Timestamp:         time.Unix(block.Timestamp, 0).Format(time.RFC3339),
-			BlockHash:         block.BlockHash.Hex(),
-			PrevBlockHash:     block.Priori.Hex(),
-			Nonce:             uint64(block.Nonce),
-			TxnCount:          block.TxnCount(),
-			BLockTransactions: block.BlockTxns,
-		})
+		chainresult.Blocks = append(chainresult.Blocks, newVerifiedChainBlock(block, args.Verify, args.HashFormat))
 	}
 
 	*result = chainresult
@@ -0,0 +1,105 @@
+package jsonrpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/core"
+)
+
+// TestShowChainVerify checks that ShowChain and GetBlockByHash only report whether the summary of each Block
+// is valid when verification is requested
+func TestShowChainVerify(t *testing.T) {
+	api := newTestAPI(t, 3)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	var chain ShowChainResult
+	callResult(t, router, "API.ShowChain", ShowChainArgs{}, &chain)
+
+	for _, block := range chain.Blocks {
+		if block.SummaryValid != nil {
+			t.Fatalf("expected no summary verification without verify")
+		}
+	}
+
+	callResult(t, router, "API.ShowChain", ShowChainArgs{Verify: true}, &chain)
+
+	if len(chain.Blocks) != 4 {
+		t.Fatalf("expected 4 blocks, got %v", len(chain.Blocks))
+	}
+
+	for _, block := range chain.Blocks {
+		if block.SummaryValid == nil || !*block.SummaryValid {
+			t.Fatalf("expected the summary of block '%v' to be valid", block.BlockHash)
+		}
+	}
+
+	var result GetBlockByHashResult
+	callResult(t, router, "API.GetBlockByHash", GetBlockByHashArgs{Hash: api.chain.Head.Hex(), Verify: true}, &result)
+
+	if result.Block.SummaryValid == nil || !*result.Block.SummaryValid {
+		t.Fatalf("expected the summary of the chain head to be valid")
+	}
+}
+
+// TestNewVerifiedChainBlockTampered checks that a Block whose Transactions were tampered with
+// reports an invalid summary
+func TestNewVerifiedChainBlockTampered(t *testing.T) {
+	api := newTestAPI(t, 1)
+
+	block, err := api.chain.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Redirect an output of the last Transaction without updating the header
+	txn := block.BlockTxns[len(block.BlockTxns)-1]
+	txn.Outputs[0] = core.TxOutput{Value: txn.Outputs[0].Value, PubKey: "mallory"}
+	txn.SetID()
+
+	chainblock := NewVerifiedChainBlock(block, true)
+	if chainblock.SummaryValid == nil || *chainblock.SummaryValid {
+		t.Fatalf("expected the summary of the tampered block to be invalid")
+	}
+}
+
+// TestShowChainMaxResponseBlocks checks that ShowChain rejects requests for more Blocks than the maximum response size,
+// while requests within it succeed, so that the whole chain can be paginated
+func TestShowChainMaxResponseBlocks(t *testing.T) {
+	api := newTestAPI(t, 5)
+
+	config := DefaultServerConfig()
+	config.MaxResponseBlocks = 3
+	router := newTestRouter(t, api, config)
+
+	if err := callError(t, router, "API.ShowChain", ShowChainArgs{}); !strings.Contains(err, "exceeds the maximum of 3 blocks") {
+		t.Fatalf("expected a request for the whole chain to be rejected, got %v", err)
+	}
+
+	if err := callError(t, router, "API.ShowChain", ShowChainArgs{Limit: 4}); !strings.Contains(err, "exceeds the maximum of 3 blocks") {
+		t.Fatalf("expected a request beyond the cap to be rejected, got %v", err)
+	}
+
+	// Paginate the chain from the chain head in pages of the maximum size
+	var heights []uint64
+	for from := api.chain.Height - 1; from >= 0; from -= 3 {
+		start := from
+
+		var page ShowChainResult
+		callResult(t, router, "API.ShowChain", ShowChainArgs{FromHeight: &start, Limit: 3}, &page)
+
+		for _, block := range page.Blocks {
+			heights = append(heights, block.Height)
+		}
+	}
+
+	if len(heights) != int(api.chain.Height) {
+		t.Fatalf("expected the pages to cover all %v blocks, got heights %v", api.chain.Height, heights)
+	}
+
+	for idx, height := range heights {
+		if want := uint64(api.chain.Height) - 1 - uint64(idx); height != want {
+			t.Fatalf("expected the pages to return height %v at position %v, got %v", want, idx, height)
+		}
+	}
+}
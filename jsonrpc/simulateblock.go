@@ -0,0 +1,64 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/anee769/essensio/core"
+)
+
+type SimulateBlockArgs struct {
+	Transactions []TransactionInput `json:"transactions"`
+}
+
+type SimulateBlockResult struct {
+	BlockHeight uint64   `json:"block_height"`
+	BlockHash   string   `json:"block_hash"`
+	Summary     string   `json:"summary"`
+	TxnIDs      []string `json:"txn_ids"`
+}
+
+// SimulateBlock assembles the Block that AddBlock would append for the given transactions, along with
+// its coinbase transaction, without storing the Block or updating the chain state.
+func (api *API) SimulateBlock(r *http.Request, args *SimulateBlockArgs, result *SimulateBlockResult) error {
+	log.Println("'SimulateBlock' Called")
+
+	if len(args.Transactions) == 0 {
+		return fmt.Errorf("no transactions for block")
+	}
+
+	transactions := make(core.Transactions, 0, len(args.Transactions))
+	for idx, txn := range args.Transactions {
+		newtxn, err := api.newTransaction(txn)
+		if err != nil {
+			return fmt.Errorf("transaction %v: %w", idx, err)
+		}
+
+		transactions = append(transactions, newtxn)
+	}
+
+	coinbase, err := api.chain.NewCoinbase(transactions, "")
+	if err != nil {
+		return fmt.Errorf("failed to generate coinbase: %w", err)
+	}
+
+	block, err := api.chain.SimulateBlock(append(core.Transactions{coinbase}, transactions...))
+	if err != nil {
+		return fmt.Errorf("failed to simulate block: %w", err)
+	}
+
+	txnIDs := make([]string, 0, block.TxnCount())
+	for _, txn := range block.BlockTxns {
+		txnIDs = append(txnIDs, txn.ID.Hex())
+	}
+
+	*result = SimulateBlockResult{
+		BlockHeight: toUint64(block.BlockHeight),
+		BlockHash:   block.BlockHash.Hex(),
+		Summary:     block.Summary.Hex(),
+		TxnIDs:      txnIDs,
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/anee769/essensio/common"
+)
+
+// TestSimulateBlock checks that SimulateBlock leaves the chain unchanged and returns the height, summary and
+// transactions of the Block that AddBlock appends for the same transactions
+func TestSimulateBlock(t *testing.T) {
+	api := newTestAPI(t, 0)
+	router := newTestRouter(t, api, DefaultServerConfig())
+
+	txns := []TransactionInput{{From: string(common.MinerAddress()), To: "alice", Value: NewValue(30)}}
+	head := api.chain.Head
+
+	var simulated SimulateBlockResult
+	callResult(t, router, "API.SimulateBlock", SimulateBlockArgs{Transactions: txns}, &simulated)
+
+	if !api.chain.Head.Equal(head) || api.chain.Height != 1 || api.chain.Mempool().Size() != 0 {
+		t.Fatalf("expected the chain to be unchanged by the simulation")
+	}
+
+	var added AddBlockResult
+	callResult(t, router, "API.AddBlock", AddBlockArgs{Transactions: txns}, &added)
+
+	block, err := api.chain.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if simulated.BlockHeight != added.BlockHeight || simulated.Summary != block.Summary.Hex() || len(simulated.TxnIDs) != block.TxnCount() {
+		t.Fatalf("expected the simulated block %+v to match the added block at height %v", simulated, added.BlockHeight)
+	}
+
+	for idx, txn := range block.BlockTxns {
+		if simulated.TxnIDs[idx] != txn.ID.Hex() {
+			t.Fatalf("expected simulated transaction %v to be '%v', got '%v'", idx, txn.ID.Hex(), simulated.TxnIDs[idx])
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/anee769/essensio/common"
+	"github.com/anee769/essensio/core"
+	"github.com/anee769/essensio/db"
+)
+
+// testToken is the auth token of the test servers
+const testToken = "test-token"
+
+// rpcResponse represents a response of the JSON codec
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *string         `json:"error"`
+}
+
+// resetDatabase removes the database directory and the write-ahead log of the test binary
+func resetDatabase(t *testing.T) {
+	t.Helper()
+
+	for _, path := range []string{db.Dir(), db.WALFile()} {
+		if err := os.RemoveAll(path); err != nil {
+			t.Fatalf("failed to remove %v: %v", path, err)
+		}
+	}
+}
+
+// testChainOptions returns the TestChainOptions of a chain of the given number of Blocks with the default Options,
+// mined without PoW
+func testChainOptions(blocks int) core.TestChainOptions {
+	return core.TestChainOptions{Options: core.DefaultOptions(), Seed: 1, Blocks: blocks, TxnsPerBlock: 2, NoPoW: true}
+}
+
+// newTestAPI returns an API for a test chain of the given number of Blocks, mined without PoW,
+// in an empty database directory. The chain is stopped and its database is removed when the test ends.
+func newTestAPI(t *testing.T, blocks int) *API {
+	t.Helper()
+	return newTestAPIOptions(t, testChainOptions(blocks))
+}
+
+// newTestAPIOptions returns an API for a test chain generated with the given TestChainOptions,
+// like newTestAPI
+func newTestAPIOptions(t *testing.T, opts core.TestChainOptions) *API {
+	t.Helper()
+	resetDatabase(t)
+	t.Cleanup(func() { resetDatabase(t) })
+
+	chain, err := core.GenerateTestChain(opts)
+	if err != nil {
+		t.Fatalf("failed to generate test chain: %v", err)
+	}
+
+	api := &API{chain: chain, peers: NewPeerSet()}
+	t.Cleanup(api.Stop)
+
+	return api
+}
+
+// newTestRouter returns the router of a test server for the API with the given ServerConfig,
+// which authenticates the AuthMethods with testToken
+func newTestRouter(t *testing.T, api *API, config ServerConfig) http.Handler {
+	t.Helper()
+	config.AuthToken = testToken
+
+	router, err := NewRouter(api, config)
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	return router
+}
+
+// call sends a JSON-RPC request for the method with the given params to the router, authenticated
+// with testToken, and returns its response. The test fails if the response is not a JSON-RPC response.
+func call(t *testing.T, router http.Handler, method string, params any) rpcResponse {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]any{"method": method, "params": []any{params}, "id": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := httptest.NewRequest(http.MethodPost, DefaultPath, bytes.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+testToken)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	var response rpcResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("%v: invalid response with status %v: %q", method, recorder.Code, recorder.Body.String())
+	}
+
+	return response
+}
+
+// callResult sends a JSON-RPC request like call and decodes its result into result.
+// The test fails if the request returns an error.
+func callResult(t *testing.T, router http.Handler, method string, params, result any) {
+	t.Helper()
+
+	response := call(t, router, method, params)
+	if response.Error != nil {
+		t.Fatalf("%v: unexpected error: %v", method, *response.Error)
+	}
+
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		t.Fatalf("%v: invalid result %s: %v", method, response.Result, err)
+	}
+}
+
+// callError sends a JSON-RPC request like call and returns its error.
+// The test fails if the request succeeds.
+func callError(t *testing.T, router http.Handler, method string, params any) string {
+	t.Helper()
+
+	response := call(t, router, method, params)
+	if response.Error == nil {
+		t.Fatalf("%v: expected an error, got %s", method, response.Result)
+	}
+
+	return *response.Error
+}
+
+// spendGenesis mines a Block with a Transaction that spends the genesis coinbase output,
+// which is owned by the miner address, to the given outputs. Returns the mined Transaction.
+func spendGenesis(t *testing.T, api *API, outputs ...core.TxOutput) *core.Transaction {
+	t.Helper()
+
+	genesis, err := api.chain.GetBlockByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txn := signedTransaction(t, common.MinerAddress(), []core.Outpoint{{ID: genesis.BlockTxns[0].ID, Index: 0}}, outputs...)
+	if err := api.chain.MineBlock(core.Transactions{txn}, ""); err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+
+	return txn
+}
+
+// signedTransaction returns a Transaction that spends the given outputs of an Address to the given outputs,
+// signed with the Wallet of the Address
+func signedTransaction(t *testing.T, from common.Address, spends []core.Outpoint, outputs ...core.TxOutput) *core.Transaction {
+	t.Helper()
+
+	txn := &core.Transaction{Outputs: outputs}
+	for _, outpoint := range spends {
+		txn.Inputs = append(txn.Inputs, core.TxInput{ID: outpoint.ID, Out: outpoint.Index})
+	}
+
+	if err := core.SignTransaction(txn, core.NewWallet(from)); err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+
+	return txn
+}
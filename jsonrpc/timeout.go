@@ -0,0 +1,97 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WriteMethods is the set of JSON-RPC methods that modify the chain or the mempool.
+// They are subject to ServerConfig.WriteTimeout, while all other methods are subject to ServerConfig.ReadTimeout.
+// They check the deadline before each change to the chain or the mempool, so a write that times out makes no further changes.
+var WriteMethods = map[string]bool{
+	"API.AddBlock":           true,
+	"API.MineBlock":          true,
+	"API.SendTransaction":    true,
+	"API.SendRawTransaction": true,
+	"API.LoadMempool":        true,
+	"API.LabelBlock":         true,
+}
+
+// timeout returns the timeout of a JSON-RPC method for the ServerConfig, which is 0 if it has no timeout
+func (config ServerConfig) timeout(method string) time.Duration {
+	if timeout, ok := config.MethodTimeouts[method]; ok {
+		return timeout
+	}
+
+	if WriteMethods[method] {
+		return config.WriteTimeout
+	}
+
+	return config.ReadTimeout
+}
+
+// withTimeouts wraps an http.Handler with the method timeouts of a ServerConfig. The method of each
+// request is read from its body, and the request is served with a context that has the deadline of the
+// method. Methods observe the deadline with checkContext and fail with a timeout error when it passes.
+func withTimeouts(handler http.Handler, config ServerConfig) http.Handler {
+	// Requests are not limited without any timeouts
+	if config.ReadTimeout <= 0 && config.WriteTimeout <= 0 && len(config.MethodTimeouts) == 0 {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, err := peekMethod(r)
+		if err != nil {
+			http.Error(w, "rpc: failed to read request body", http.StatusBadRequest)
+			return
+		}
+
+		if timeout := config.timeout(method); timeout > 0 {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			r = r.WithContext(ctx)
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// peekMethod returns the JSON-RPC method of a request, read from its body. The body is restored
+// for the handler, and the method is empty for malformed requests, which are rejected by the handler.
+// Returns an error if the body cannot be read.
+func peekMethod(r *http.Request) (string, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", err
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var request struct {
+		Method string `json:"method"`
+	}
+
+	_ = json.Unmarshal(body, &request)
+	return request.Method, nil
+}
+
+// checkContext returns an error if the context of a request is done,
+// which is a timeout error if the deadline of the request has passed
+func checkContext(r *http.Request) error {
+	err := r.Context().Err()
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("request timed out")
+	default:
+		return fmt.Errorf("request cancelled: %w", err)
+	}
+}
@@ -0,0 +1,63 @@
+package jsonrpc
+
+import (
+	"testing"
+	"time"
+)
+
+// TestMethodTimeouts checks that methods that exceed their timeout fail with a timeout error,
+// and that a write method which times out does not modify the chain
+func TestMethodTimeouts(t *testing.T) {
+	api := newTestAPI(t, 20)
+
+	config := DefaultServerConfig()
+	config.ReadTimeout = time.Nanosecond
+	config.WriteTimeout = time.Nanosecond
+	config.MethodTimeouts = map[string]time.Duration{"API.GetChainInfo": 0}
+
+	router := newTestRouter(t, api, config)
+	height := api.chain.Height
+
+	for _, method := range []string{"API.ShowChain", "API.GetRichList", "API.AuditUTXO", "API.MineBlock"} {
+		response := call(t, router, method, map[string]any{})
+		if response.Error == nil || *response.Error != "request timed out" {
+			t.Fatalf("%v: expected a timeout error, got %s", method, response.Result)
+		}
+	}
+
+	if api.chain.Height != height {
+		t.Fatalf("expected the chain height to remain %v after a timed out write, got %v", height, api.chain.Height)
+	}
+
+	// A method with its timeout disabled is served
+	if response := call(t, router, "API.GetChainInfo", map[string]any{}); response.Error != nil {
+		t.Fatalf("expected a method without a timeout to succeed, got %v", *response.Error)
+	}
+}
+
+// TestMethodTimeoutsLongScan checks that a scan which outlives its timeout is cut off cleanly,
+// while the same scan completes without a timeout
+func TestMethodTimeoutsLongScan(t *testing.T) {
+	api := newTestAPI(t, 200)
+
+	config := DefaultServerConfig()
+	config.MethodTimeouts = map[string]time.Duration{"API.ShowChain": time.Millisecond}
+
+	router := newTestRouter(t, api, config)
+	args := map[string]any{"verify": true}
+
+	// The same scan without a timeout must take longer than the timeout for the deadline to pass during it
+	started := time.Now()
+	if response := call(t, newTestRouter(t, api, DefaultServerConfig()), "API.ShowChain", args); response.Error != nil {
+		t.Fatalf("expected the scan to complete without a timeout, got %v", *response.Error)
+	}
+
+	if time.Since(started) < 2*time.Millisecond {
+		t.Skip("chain scan is too fast to outlive the timeout")
+	}
+
+	response := call(t, router, "API.ShowChain", args)
+	if response.Error == nil || *response.Error != "request timed out" {
+		t.Fatalf("expected the scan to be cut off with a timeout error, got %s", response.Result)
+	}
+}
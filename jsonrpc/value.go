@@ -0,0 +1,129 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/gorilla/rpc"
+)
+
+// Value is an amount in an RPC result or argument, such as the value of an output or a balance.
+// It is encoded as a JSON number, or as a decimal string if ServerConfig.StringValues is set, since
+// JavaScript clients lose the precision of numbers beyond 2^53. Both encodings are accepted as arguments.
+type Value struct {
+	amount int
+	quoted bool
+}
+
+// NewValue returns the Value of an amount
+func NewValue(amount int) Value {
+	return Value{amount: amount}
+}
+
+// Int returns the amount of the Value
+func (value Value) Int() int {
+	return value.amount
+}
+
+// String implements the Stringer interface for Value
+func (value Value) String() string {
+	return strconv.Itoa(value.amount)
+}
+
+// MarshalJSON implements the json.Marshaler interface for Value
+func (value Value) MarshalJSON() ([]byte, error) {
+	if value.quoted {
+		return json.Marshal(value.String())
+	}
+
+	return []byte(value.String()), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for Value.
+// The amount can be a JSON number or a decimal string.
+func (value *Value) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+
+	amount, err := strconv.Atoi(text)
+	if err != nil {
+		return fmt.Errorf("invalid value %s: must be an integer", data)
+	}
+
+	*value = Value{amount: amount}
+	return nil
+}
+
+// valueCodec is an rpc.Codec that encodes every Value in the results of methods as a decimal string
+type valueCodec struct {
+	rpc.Codec
+}
+
+// NewRequest implements the rpc.Codec interface for valueCodec
+func (codec valueCodec) NewRequest(r *http.Request) rpc.CodecRequest {
+	return valueCodecRequest{codec.Codec.NewRequest(r)}
+}
+
+// valueCodecRequest is the rpc.CodecRequest of a valueCodec
+type valueCodecRequest struct {
+	rpc.CodecRequest
+}
+
+// WriteResponse implements the rpc.CodecRequest interface for valueCodecRequest
+func (request valueCodecRequest) WriteResponse(w http.ResponseWriter, reply interface{}, methodErr error) error {
+	if methodErr == nil {
+		quoteValues(reflect.ValueOf(reply))
+	}
+
+	return request.CodecRequest.WriteResponse(w, reply, methodErr)
+}
+
+// valueType is the reflected type of Value
+var valueType = reflect.TypeOf(Value{})
+
+// quoteValues marks every Value reachable from a reflected result to be encoded as a decimal string
+func quoteValues(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			quoteValues(v.Elem())
+		}
+
+	case reflect.Struct:
+		if v.Type() == valueType {
+			if v.CanAddr() {
+				v.Addr().Interface().(*Value).quoted = true
+			}
+
+			return
+		}
+
+		for idx := 0; idx < v.NumField(); idx++ {
+			if v.Type().Field(idx).IsExported() {
+				quoteValues(v.Field(idx))
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for idx := 0; idx < v.Len(); idx++ {
+			quoteValues(v.Index(idx))
+		}
+
+	case reflect.Map:
+		// Map elements are not addressable, so each element is copied, marked and stored back
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			quoteValues(elem)
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/anee769/essensio/core"
+)
+
+// largeValue is an amount beyond 2^53, which JavaScript numbers cannot represent exactly
+const largeValue = 1<<53 + 1
+
+// TestValueJSON checks that a Value is encoded as a number, or as a decimal string once quoted,
+// and that both encodings decode to the exact amount
+func TestValueJSON(t *testing.T) {
+	value := NewValue(largeValue)
+
+	tests := []struct {
+		value   Value
+		encoded string
+	}{
+		{value, "9007199254740993"},
+		{Value{amount: largeValue, quoted: true}, `"9007199254740993"`},
+	}
+
+	for _, test := range tests {
+		data, err := json.Marshal(test.value)
+		if err != nil || string(data) != test.encoded {
+			t.Fatalf("expected the value to encode as %v, got %s and %v", test.encoded, data, err)
+		}
+
+		var decoded Value
+		if err := json.Unmarshal(data, &decoded); err != nil || decoded.Int() != largeValue {
+			t.Fatalf("expected %s to decode to %v, got %v and %v", data, largeValue, decoded, err)
+		}
+	}
+
+	for _, invalid := range []string{`1.5`, `"abc"`, `true`} {
+		var decoded Value
+		if err := json.Unmarshal([]byte(invalid), &decoded); err == nil {
+			t.Fatalf("expected %s to be rejected as a value", invalid)
+		}
+	}
+}
+
+// TestStringValues checks that a server with ServerConfig.StringValues encodes the values of results as decimal
+// strings, including values in maps, so that a large balance round-trips exactly
+func TestStringValues(t *testing.T) {
+	opts := testChainOptions(0)
+	opts.Options.Genesis.Mode = core.GenesisFunded
+	opts.Options.Genesis.Allocations = []core.GenesisAllocation{{Address: "whale", Value: largeValue}}
+
+	api := newTestAPIOptions(t, opts)
+
+	config := DefaultServerConfig()
+	config.StringValues = true
+
+	quoted := newTestRouter(t, api, config)
+	plain := newTestRouter(t, api, DefaultServerConfig())
+
+	response := call(t, quoted, "API.GetBalance", GetBalanceArgs{Address: "whale"})
+	if !strings.Contains(string(response.Result), `"balance":"9007199254740993"`) {
+		t.Fatalf("expected the balance to be encoded as a string, got %s", response.Result)
+	}
+
+	var result GetBalanceResult
+	if err := json.Unmarshal(response.Result, &result); err != nil || result.Balance.Int() != largeValue {
+		t.Fatalf("expected the balance to round-trip as %v, got %v and %v", largeValue, result.Balance, err)
+	}
+
+	response = call(t, quoted, "API.BatchGetBalance", BatchGetBalanceArgs{Addresses: []string{"whale"}})
+	if !strings.Contains(string(response.Result), `"whale":"9007199254740993"`) {
+		t.Fatalf("expected the balances of a map to be encoded as strings, got %s", response.Result)
+	}
+
+	response = call(t, plain, "API.GetBalance", GetBalanceArgs{Address: "whale"})
+	if !strings.Contains(string(response.Result), `"balance":9007199254740993`) {
+		t.Fatalf("expected the balance to be encoded as a number by default, got %s", response.Result)
+	}
+}
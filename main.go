@@ -1,38 +1,66 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strings"
 
-	"github.com/gorilla/mux"
-	"github.com/gorilla/rpc"
-	"github.com/gorilla/rpc/json"
-
+	"github.com/anee769/essensio/core"
 	"github.com/anee769/essensio/jsonrpc"
 )
 
 const SERVER_PORT = 8080
 
 func main() {
-	// Create a new RPC Server and register the JSON Codec
-	server := rpc.NewServer()
-	server.RegisterCodec(json.NewCodec(), "application/json")
-	server.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
+	// Parse the server configuration from the command line
+	config := jsonrpc.DefaultServerConfig()
+	origins := flag.String("cors-origins", "", "comma separated list of origins allowed for CORS (disabled if empty)")
+	verbosity := flag.Uint("verbosity", uint(core.LogInfo), "verbosity of chain logs (0: silent, 1: info, 2: debug)")
+	dust := flag.Int("dust-threshold", 0, "minimum value of non-coinbase transaction outputs (no limit if 0)")
+	maturity := flag.Int64("coinbase-maturity", 0, "confirmations required to spend coinbase outputs (none if 0)")
+	peers := flag.String("peers", "", "comma separated list of initial peer urls")
+	empty := flag.Bool("allow-empty-blocks", true, "mine blocks with only the coinbase when no transactions are pending")
+	skew := flag.Duration("max-clock-skew", 0, "maximum time by which block timestamps may be ahead of the local clock (2h if 0)")
+	flag.StringVar(&config.Path, "rpc-path", config.Path, "route at which the JSON-RPC server is mounted")
+	flag.StringVar(&config.BlockCSVPath, "csv-path", config.BlockCSVPath, "route at which block transactions are served as csv")
+	flag.DurationVar(&config.ReadTimeout, "rpc-read-timeout", 0, "timeout of rpc methods that do not modify the chain (none if 0)")
+	flag.DurationVar(&config.WriteTimeout, "rpc-write-timeout", 0, "timeout of rpc methods that modify the chain (none if 0)")
+	flag.Int64Var(&config.MaxResponseBlocks, "rpc-max-blocks", config.MaxResponseBlocks, "maximum number of blocks in an rpc response (unlimited if 0)")
+	flag.StringVar(&config.AuthToken, "rpc-auth-token", "", "bearer token required by operational rpc methods such as AuditUTXO (disabled if empty)")
+	flag.BoolVar(&config.StringValues, "rpc-string-values", false, "encode values and balances in rpc results as decimal strings")
+	flag.IntVar(&config.MaxBatchSize, "rpc-max-batch", config.MaxBatchSize, "maximum number of requests in an rpc batch (batches disabled if 0)")
+	flag.IntVar(&config.MaxConcurrent, "rpc-max-concurrent", 0, "maximum number of concurrent rpc requests (unlimited if 0)")
+	flag.Parse()
+
+	if *origins != "" {
+		config.AllowedOrigins = strings.Split(*origins, ",")
+	}
+
+	if *peers != "" {
+		config.Peers = strings.Split(*peers, ",")
+	}
+
+	// Configure the chain to log to the standard error
+	opts := core.DefaultOptions()
+	opts.Logger = core.NewLogger(os.Stderr, core.LogLevel(*verbosity))
+	opts.DustThreshold = *dust
+	opts.CoinbaseMaturity = *maturity
+	opts.MaxClockSkew = *skew
+	opts.AllowEmptyBlocks = *empty
 
 	// Create a new JSON-RPC API for Essensio
-	api := jsonrpc.NewAPI()
+	api := jsonrpc.NewAPI(opts)
 	defer api.Stop()
 
-	// Register the Essensio API with the Server
-	if err := server.RegisterService(api, ""); err != nil {
-		log.Fatalln("Failed to Register Essensio API:", err)
+	// Set up the JSON-RPC Server for the Essensio API
+	router, err := jsonrpc.NewRouter(api, config)
+	if err != nil {
+		log.Fatalln("Failed to Setup Server:", err)
 	}
 
-	// Set up a new Multiplexed Router
-	router := mux.NewRouter()
-	router.Handle("/rpc", server)
-
 	// HTTP Listen & Serve
 	fmt.Println("Server Starting...")
 	if err := http.ListenAndServe(fmt.Sprintf(":%v", SERVER_PORT), router); err != nil {